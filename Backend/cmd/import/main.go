@@ -0,0 +1,112 @@
+// Command import applies a bulk import file straight to the database, for
+// loads too large or too early for the admin API.
+//
+//	go run ./cmd/import -kind fixtures [-format csv] [-dry-run] [-report errors.csv] fixtures.csv
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fanzone/internal/cache"
+	"fanzone/internal/config"
+	"fanzone/internal/db"
+	"fanzone/internal/importer"
+	"fanzone/internal/repository"
+)
+
+func main() {
+	kind := flag.String("kind", "", "what the file holds: "+strings.Join(importer.Kinds, ", "))
+	format := flag.String("format", "", "csv or json (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "print the changes without applying them")
+	report := flag.String("report", "", "write the errors to this CSV file")
+	flag.Parse()
+	if flag.NArg() != 1 || *kind == "" {
+		fmt.Fprintln(os.Stderr, "usage: import -kind <kind> [-format csv|json] [-dry-run] [-report errors.csv] <file>")
+		os.Exit(2)
+	}
+
+	path := flag.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rows, err := importer.Parse(file, *format)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	cfg := config.LoadConfig()
+	client, database := db.ConnectDB(cfg.MongoURI, cfg.DBName)
+	defer client.Disconnect(context.Background())
+
+	// With REDIS_URL set, the import drops what it changes from the cache the
+	// servers share. Servers caching in memory cannot be reached from here and
+	// keep serving the old data until CACHE_TTL_SECONDS passes.
+	var cacheBackend cache.Backend = cache.NewMemory()
+	if cfg.RedisURL != "" {
+		redis, err := cache.NewRedis(cfg.RedisURL, "fanzone:")
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		cacheBackend = redis
+	} else {
+		log.Printf("REDIS_URL is not set; running servers may show cached leagues and clubs for up to %s", cfg.CacheTTL)
+	}
+	repo := repository.NewRepository(database)
+	cachedRepo := repository.NewCachedRepository(repo, cache.New(cacheBackend, cfg.CacheTTL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	plan, err := importer.Prepare(ctx, cachedRepo, *kind, rows)
+	if err != nil {
+		log.Fatalf("Failed to validate %s: %v", path, err)
+	}
+	if *dryRun {
+		out := json.NewEncoder(os.Stdout)
+		out.SetIndent("", "  ")
+		if err := out.Encode(plan); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		plan.Apply(ctx)
+		for _, key := range plan.Standings {
+			if _, err := repo.RecomputeStandings(ctx, key); err != nil {
+				log.Printf("Failed to recompute standings: %v", err)
+			}
+		}
+	}
+
+	s := plan.Summary
+	log.Printf("%d created, %d updated, %d unchanged, %d failed", s.Created, s.Updated, s.Unchanged, s.Failed)
+	for _, e := range plan.Errors {
+		log.Printf("row %d %s: %s", e.Row, e.Field, e.Message)
+	}
+	if *report != "" {
+		out, err := os.Create(*report)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := importer.WriteErrorReport(out, plan.Errors); err != nil {
+			log.Fatal(err)
+		}
+		if err := out.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(plan.Errors) > 0 {
+		os.Exit(1)
+	}
+}
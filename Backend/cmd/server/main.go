@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 
+	"fanzone/internal/cache"
 	"fanzone/internal/config"
 	"fanzone/internal/db"
 	"fanzone/internal/handlers"
@@ -24,14 +28,101 @@ func main() {
 	// 3. Initialize Repository
 	repo := repository.NewRepository(database)
 
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := repo.EnsureIndexes(indexCtx); err != nil {
+		log.Printf("Failed to ensure indexes: %v", err)
+	}
+	cancelIndex()
+
+	// Reference data is cached in Redis when configured, in memory otherwise
+	var cacheBackend cache.Backend = cache.NewMemory()
+	if cfg.RedisURL != "" {
+		redis, err := cache.NewRedis(cfg.RedisURL, "fanzone:")
+		if err != nil {
+			log.Fatalf("Failed to connect to Redis: %v", err)
+		}
+		cacheBackend = redis
+	}
+	cachedRepo := repository.NewCachedRepository(repo, cache.New(cacheBackend, cfg.CacheTTL))
+
 	// 4. Initialize Background Worker
 	//    Buffer size 100, 3 workers
 	w := worker.NewWorker(100)
+	w.Register("PURGE_TRASH", func(t worker.Task) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		purged, err := repo.PurgeTrash(ctx, time.Now().Add(-cfg.TrashRetention))
+		if err != nil {
+			log.Printf("[Trash] Purge failed: %v", err)
+			return
+		}
+		log.Printf("[Trash] Purged expired items: %v", purged)
+	})
+	w.Register("UNPIN_EXPIRED", func(t worker.Task) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		unpinned, err := repo.UnpinExpired(ctx, time.Now())
+		if err != nil {
+			log.Printf("[Pins] Unpin failed: %v", err)
+			return
+		}
+		log.Printf("[Pins] Unpinned expired items: %v", unpinned)
+	})
+	w.Register("REINDEX_SEARCH", func(t worker.Task) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		indexed, err := repo.ReindexSearch(ctx)
+		if err != nil {
+			log.Printf("[Search] Reindex failed: %v", err)
+			return
+		}
+		log.Printf("[Search] Reindexed documents: %v", indexed)
+	})
+	w.Register("RECOMPUTE_STANDINGS", func(t worker.Task) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		// Without a key, e.g. on the daily run, every table is rebuilt
+		key, _ := t.Payload.(repository.StandingsKey)
+		saved, err := repo.RecomputeStandings(ctx, key)
+		if err != nil {
+			log.Printf("[Standings] Recompute failed: %v", err)
+			return
+		}
+		log.Printf("[Standings] Recomputed %d table(s)", saved)
+	})
 	w.Start(3)
+	w.Every(time.Hour, worker.Task{Type: "PURGE_TRASH"})
+	w.Every(time.Minute, worker.Task{Type: "UNPIN_EXPIRED"})
+	w.Every(24*time.Hour, worker.Task{Type: "REINDEX_SEARCH"})
+	w.Every(24*time.Hour, worker.Task{Type: "RECOMPUTE_STANDINGS"})
 	defer w.Stop()
 
 	// 5. Initialize Handlers
-	h := handlers.NewHandler(repo, cfg, w)
+	h := handlers.NewHandler(cachedRepo, cfg, w)
+	w.Register("RUN_IMPORT", func(t worker.Task) {
+		ctx, cancel := context.WithTimeout(context.Background(), handlers.ImportTimeout)
+		defer cancel()
+
+		id, _ := t.Payload.(bson.ObjectID)
+		if err := h.RunImportJob(ctx, id); err != nil {
+			log.Printf("[Import] Import %s failed: %v", id.Hex(), err)
+		}
+	})
+	recoverCtx, cancelRecover := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := h.RecoverImportJobs(recoverCtx); err != nil {
+		log.Printf("[Import] Failed to recover imports: %v", err)
+	}
+	cancelRecover()
+
+	// Relay items published by other instances to this instance's streams
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go h.RelayFeedInserts(relayCtx)
+	go h.RelayMatchEvents(relayCtx)
 
 	// 6. Setup Router
 	r := gin.Default()
@@ -40,7 +131,8 @@ func main() {
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since, Last-Event-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -56,37 +148,68 @@ func main() {
 		authGroup.POST("/logout", h.Logout)
 	}
 
+	// Conditional GET support, with how long clients and CDNs may reuse a response
+	cacheReference := middleware.ConditionalGET(middleware.CacheReference)
+	cacheContent := middleware.ConditionalGET(middleware.CacheContent)
+
 	// Public endpoints (no authentication required for mobile users)
 	publicGroup := r.Group("/api")
 	{
 		// Clubs
-		publicGroup.GET("/clubs", h.GetClubs)
-		publicGroup.GET("/clubs/:id", h.GetClubByID)
+		publicGroup.GET("/clubs", cacheReference, h.GetClubs)
+		publicGroup.GET("/clubs/:id", cacheReference, h.GetClubByID)
+		publicGroup.GET("/clubs/:id/seasons", cacheReference, h.GetClubSeasons)
+		publicGroup.GET("/clubs/:id/squad", cacheReference, h.GetClubSquad)
+		publicGroup.GET("/clubs/:id/fixtures.ics", cacheContent, h.GetClubFixturesCalendar)
+		publicGroup.GET("/players", cacheReference, h.GetPlayers)
+		publicGroup.GET("/players/:id", cacheContent, h.GetPlayerByID)
 		
 		// Leagues
-		publicGroup.GET("/leagues", h.GetLeagues)
-		publicGroup.GET("/leagues/:id", h.GetLeagueByID)
+		publicGroup.GET("/leagues", cacheReference, h.GetLeagues)
+		publicGroup.GET("/leagues/:id", cacheReference, h.GetLeagueByID)
+		publicGroup.GET("/leagues/:id/standings", cacheContent, h.GetLeagueStandings)
+		publicGroup.GET("/leagues/:id/seasons", cacheReference, h.GetLeagueSeasons)
 		
 		// Languages
-		publicGroup.GET("/languages", h.GetLanguages)
+		publicGroup.GET("/languages", cacheReference, h.GetLanguages)
 		
 		// Content/News
-		publicGroup.GET("/content", h.GetContent)
-		publicGroup.GET("/content/:id", h.GetContentByID)
-		publicGroup.GET("/news/:id", h.GetContentByID) // Alias for content
+		publicGroup.GET("/content", cacheContent, h.GetContent)
+		publicGroup.GET("/content/:id", cacheContent, h.GetContentByID)
+		publicGroup.GET("/news/:id", cacheContent, h.GetContentByID) // Alias for content
 		
 		// Highlights
-		publicGroup.GET("/highlights", h.GetHighlights)
-		publicGroup.GET("/highlights/:id", h.GetHighlightByID)
+		publicGroup.GET("/highlights", cacheContent, h.GetHighlights)
+		publicGroup.GET("/highlights/:id", cacheContent, h.GetHighlightByID)
 		
 		// Watch Platforms
-		publicGroup.GET("/watch-links", h.GetWatchLinks)
-		publicGroup.GET("/watch-platforms", h.GetWatchLinks) // Alias for watch-links
-		publicGroup.GET("/watch-links/:id", h.GetWatchLinkByID)
+		publicGroup.GET("/watch-links", cacheReference, h.GetWatchLinks)
+		publicGroup.GET("/watch-platforms", cacheReference, h.GetWatchLinks) // Alias for watch-links
+		publicGroup.GET("/watch-links/:id", cacheReference, h.GetWatchLinkByID)
+
+		// Matches
+		publicGroup.GET("/matches", cacheContent, h.GetMatches)
+		publicGroup.GET("/matches/live", h.GetLiveMatches)
+		publicGroup.GET("/matches/live/stream", h.StreamLiveMatches)
+		publicGroup.GET("/matches/:id", cacheContent, h.GetMatchByID)
+		publicGroup.GET("/matches/:id/events", h.GetMatchEvents)
+		publicGroup.GET("/matches/:id/stream", h.StreamMatch)
 		
 		// Feed endpoints (public - no authentication needed)
-		publicGroup.GET("/feed/all", h.GetAllFeed)
-		publicGroup.GET("/feed/club/:id", h.GetClubFeed)
+		publicGroup.GET("/feed/all", cacheContent, h.GetAllFeed)
+		publicGroup.GET("/feed/club/:id", cacheContent, h.GetClubFeed)
+		publicGroup.GET("/feed/league/:id", cacheContent, h.GetLeagueFeed)
+		publicGroup.GET("/feed/stream", h.StreamFeed)
+
+		// Search
+		publicGroup.GET("/search", cacheContent, h.Search)
+		publicGroup.GET("/autocomplete", cacheReference, h.Autocomplete)
+
+		// Personal fixtures calendar, authenticated by the token in the URL
+		publicGroup.GET("/calendar/:token", middleware.ConditionalGET(middleware.CachePrivate), h.GetPersonalCalendar)
+
+		// Incremental sync for offline-first clients
+		publicGroup.GET("/sync", h.GetSyncChanges)
 	}
 
 	// Protected API endpoints (require authentication - for web dashboard)
@@ -94,7 +217,7 @@ func main() {
 	api.Use(middleware.AuthMiddleware(cfg.AccessSecret))
 	{
 		// Feed endpoint that requires user authentication
-		api.GET("/feed/my-club", h.GetMyClubFeed)
+		api.GET("/feed/my-club", middleware.ConditionalGET(middleware.CachePrivate), h.GetMyClubFeed)
 	}
 
 	userGroup := r.Group("/api/users")
@@ -104,6 +227,13 @@ func main() {
 		userGroup.PUT("/me", h.UpdateProfile)
 		userGroup.PATCH("/me/favorite-club", h.UpdateFavoriteClub)
 		userGroup.PATCH("/me/language", h.UpdateLanguage)
+		userGroup.GET("/me/follows", h.GetFollows)
+		userGroup.POST("/me/follows/clubs/:id", h.FollowClub)
+		userGroup.DELETE("/me/follows/clubs/:id", h.UnfollowClub)
+		userGroup.POST("/me/follows/leagues/:id", h.FollowLeague)
+		userGroup.DELETE("/me/follows/leagues/:id", h.UnfollowLeague)
+		userGroup.GET("/me/calendar", h.GetCalendarSubscription)
+		userGroup.DELETE("/me/calendar", h.RevokeCalendarSubscription)
 	}
 
 	// Legacy user routes for backward compatibility
@@ -122,19 +252,53 @@ func main() {
 		adminGroup.POST("/clubs", h.AdminAddClub)
 		adminGroup.PUT("/clubs/:id", h.AdminUpdateClub)
 		adminGroup.DELETE("/clubs/:id", h.AdminDeleteClub)
+		adminGroup.POST("/clubs/:id/squads", h.AdminAddSquad)
+		adminGroup.PUT("/clubs/:id/squads/:squadId", h.AdminUpdateSquad)
+		adminGroup.DELETE("/clubs/:id/squads/:squadId", h.AdminDeleteSquad)
+		adminGroup.POST("/players", h.AdminAddPlayer)
+		adminGroup.PUT("/players/:id", h.AdminUpdatePlayer)
+		adminGroup.DELETE("/players/:id", h.AdminDeletePlayer)
 		adminGroup.POST("/leagues", h.AdminAddLeague)
 		adminGroup.PUT("/leagues/:id", h.AdminUpdateLeague)
 		adminGroup.DELETE("/leagues/:id", h.AdminDeleteLeague)
+		adminGroup.POST("/leagues/:id/seasons", h.AdminAddSeason)
+		adminGroup.PUT("/leagues/:id/seasons/:seasonId", h.AdminUpdateSeason)
+		adminGroup.DELETE("/leagues/:id/seasons/:seasonId", h.AdminDeleteSeason)
 		adminGroup.POST("/content", h.AdminAddContent)
 		adminGroup.PUT("/content/:id", h.AdminUpdateContent)
 		adminGroup.DELETE("/content/:id", h.AdminDeleteContent)
+		adminGroup.POST("/content/:id/pin", h.AdminPinContent)
+		adminGroup.DELETE("/content/:id/pin", h.AdminUnpinContent)
+		adminGroup.GET("/content/:id/revisions", h.AdminGetContentRevisions)
+		adminGroup.GET("/content/:id/revisions/diff", h.AdminDiffContentRevisions)
+		adminGroup.POST("/content/:id/revisions/:revisionId/restore", h.AdminRestoreContentRevision)
 		adminGroup.POST("/highlights", h.AdminAddHighlight)
 		adminGroup.PUT("/highlights/:id", h.AdminUpdateHighlight)
 		adminGroup.DELETE("/highlights/:id", h.AdminDeleteHighlight)
+		adminGroup.POST("/highlights/:id/pin", h.AdminPinHighlight)
+		adminGroup.DELETE("/highlights/:id/pin", h.AdminUnpinHighlight)
+		adminGroup.GET("/highlights/:id/revisions", h.AdminGetHighlightRevisions)
+		adminGroup.GET("/highlights/:id/revisions/diff", h.AdminDiffHighlightRevisions)
+		adminGroup.POST("/highlights/:id/revisions/:revisionId/restore", h.AdminRestoreHighlightRevision)
 		adminGroup.POST("/watch-links", h.AdminAddWatchLink)
 		adminGroup.PUT("/watch-links/:id", h.AdminUpdateWatchLink)
 		adminGroup.DELETE("/watch-links/:id", h.AdminDeleteWatchLink)
+		adminGroup.POST("/matches", h.AdminAddMatch)
+		adminGroup.PUT("/matches/:id", h.AdminUpdateMatch)
+		adminGroup.DELETE("/matches/:id", h.AdminDeleteMatch)
+		adminGroup.POST("/matches/:id/events", h.AdminAddMatchEvent)
+		adminGroup.DELETE("/matches/:id/events/:eventId", h.AdminDeleteMatchEvent)
+		adminGroup.POST("/imports", h.AdminImport)
+		adminGroup.GET("/imports", h.AdminGetImports)
+		adminGroup.GET("/imports/:id", h.AdminGetImport)
+		adminGroup.GET("/imports/:id/errors", h.AdminGetImportErrors)
+		adminGroup.POST("/imports/:id/retry", h.AdminRetryImport)
+		adminGroup.GET("/trash", h.AdminGetTrash)
+		adminGroup.POST("/trash/:type/:id/restore", h.AdminRestoreFromTrash)
+		adminGroup.GET("/feed-ranking", h.AdminGetRankingWeights)
+		adminGroup.PUT("/feed-ranking", h.AdminUpdateRankingWeights)
 		adminGroup.GET("/stats", h.GetStats)
+		adminGroup.GET("/cache/stats", h.GetCacheStats)
 		adminGroup.GET("/analytics", h.GetAnalytics)
 		adminGroup.GET("/activities", h.GetActivityFeed)
 		adminGroup.GET("/users", h.GetAllUsers)
@@ -0,0 +1,132 @@
+// Package cache is a read-through cache for rarely changing data. Values are
+// opaque bytes so that they can live in process memory or in Redis.
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Backend stores cached values. Get reports whether the key was found.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Stats counts lookups for one key prefix
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"`
+}
+
+type counters struct {
+	hits, misses, errors atomic.Int64
+}
+
+type Cache struct {
+	backend Backend
+	ttl     time.Duration
+	group   singleflight.Group
+
+	mu    sync.Mutex
+	stats map[string]*counters
+	// generations change on every invalidation, so a load that started
+	// before one does not store what it read
+	generations map[string]uint64
+}
+
+func New(backend Backend, ttl time.Duration) *Cache {
+	return &Cache{
+		backend:     backend,
+		ttl:         ttl,
+		stats:       make(map[string]*counters),
+		generations: make(map[string]uint64),
+	}
+}
+
+// Fetch returns the cached value for key, calling load on a miss. Concurrent
+// misses for the same key share a single load. Backend errors are counted and
+// fall through to load, so a cache outage only costs performance.
+func (c *Cache) Fetch(ctx context.Context, key string, load func() ([]byte, error)) ([]byte, error) {
+	stats := c.counters(key)
+
+	value, found, err := c.backend.Get(ctx, key)
+	if err != nil {
+		stats.errors.Add(1)
+	}
+	if found {
+		stats.hits.Add(1)
+		return value, nil
+	}
+	stats.misses.Add(1)
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		generation := c.generation(key)
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		if c.generation(key) != generation {
+			return value, nil
+		}
+		if err := c.backend.Set(ctx, key, value, c.ttl); err != nil {
+			stats.errors.Add(1)
+		}
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// Invalidate drops keys so the next Fetch reloads them
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	// A load already in flight may have read the old data; don't store it
+	// or let new callers share it
+	c.mu.Lock()
+	for _, key := range keys {
+		c.generations[key]++
+		c.group.Forget(key)
+	}
+	c.mu.Unlock()
+	return c.backend.Delete(ctx, keys...)
+}
+
+func (c *Cache) generation(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[key]
+}
+
+// Stats returns hit and miss counts per key prefix (the part before ":")
+func (c *Cache) Stats() map[string]Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string]Stats, len(c.stats))
+	for prefix, s := range c.stats {
+		out[prefix] = Stats{Hits: s.hits.Load(), Misses: s.misses.Load(), Errors: s.errors.Load()}
+	}
+	return out
+}
+
+func (c *Cache) counters(key string) *counters {
+	prefix, _, _ := strings.Cut(key, ":")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stats[prefix]
+	if !ok {
+		s = &counters{}
+		c.stats[prefix] = s
+	}
+	return s
+}
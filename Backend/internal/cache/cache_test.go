@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// backends returns the backends to test: memory always, and Redis when
+// REDIS_TEST_URL points at a server the tests may write to
+func backends() map[string]func(t *testing.T) Backend {
+	out := map[string]func(t *testing.T) Backend{
+		"memory": func(t *testing.T) Backend { return NewMemory() },
+	}
+	out["redis"] = func(t *testing.T) Backend {
+		url := os.Getenv("REDIS_TEST_URL")
+		if url == "" {
+			t.Skip("REDIS_TEST_URL is not set")
+		}
+		// A prefix of its own keeps each run away from other data
+		r, err := NewRedis(url, "fanzone-test:"+strconv.FormatInt(time.Now().UnixNano(), 36)+":")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	return out
+}
+
+// loader counts its calls and returns a new value each time
+type loader struct {
+	calls atomic.Int64
+}
+
+func (l *loader) load() ([]byte, error) {
+	n := l.calls.Add(1)
+	return []byte("value " + strconv.FormatInt(n, 10)), nil
+}
+
+func TestFetch(t *testing.T) {
+	for name, backend := range backends() {
+		t.Run(name, func(t *testing.T) {
+			c := New(backend(t), time.Minute)
+			ctx := context.Background()
+			l := &loader{}
+
+			for i := 0; i < 3; i++ {
+				value, err := c.Fetch(ctx, "clubs:list", l.load)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(value) != "value 1" {
+					t.Fatalf("fetch %d = %q; want the first load", i, value)
+				}
+			}
+			if calls := l.calls.Load(); calls != 1 {
+				t.Errorf("loaded %d times; want 1", calls)
+			}
+			if stats := c.Stats()["clubs"]; stats.Hits != 2 || stats.Misses != 1 {
+				t.Errorf("stats = %+v; want 2 hits and 1 miss", stats)
+			}
+
+			// Failed loads are not cached
+			failure := errors.New("database down")
+			if _, err := c.Fetch(ctx, "clubs:item", func() ([]byte, error) { return nil, failure }); !errors.Is(err, failure) {
+				t.Errorf("error = %v; want the load's error", err)
+			}
+			if value, _ := c.Fetch(ctx, "clubs:item", l.load); string(value) != "value 2" {
+				t.Errorf("after a failed load got %q; want a fresh load", value)
+			}
+		})
+	}
+}
+
+func TestTTL(t *testing.T) {
+	for name, backend := range backends() {
+		t.Run(name, func(t *testing.T) {
+			c := New(backend(t), 50*time.Millisecond)
+			ctx := context.Background()
+			l := &loader{}
+
+			c.Fetch(ctx, "leagues:list", l.load)
+			c.Fetch(ctx, "leagues:list", l.load)
+			if calls := l.calls.Load(); calls != 1 {
+				t.Fatalf("loaded %d times before expiry; want 1", calls)
+			}
+			time.Sleep(100 * time.Millisecond)
+			if value, _ := c.Fetch(ctx, "leagues:list", l.load); string(value) != "value 2" {
+				t.Errorf("after expiry got %q; want a fresh load", value)
+			}
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	for name, backend := range backends() {
+		t.Run(name, func(t *testing.T) {
+			c := New(backend(t), time.Minute)
+			ctx := context.Background()
+			l := &loader{}
+
+			c.Fetch(ctx, "clubs:list", l.load)
+			c.Fetch(ctx, "clubs:a", l.load)
+			if err := c.Invalidate(ctx, "clubs:list", "clubs:missing"); err != nil {
+				t.Fatal(err)
+			}
+			if value, _ := c.Fetch(ctx, "clubs:list", l.load); string(value) != "value 3" {
+				t.Errorf("invalidated key = %q; want a fresh load", value)
+			}
+			if value, _ := c.Fetch(ctx, "clubs:a", l.load); string(value) != "value 2" {
+				t.Errorf("other key = %q; want it still cached", value)
+			}
+		})
+	}
+}
+
+// A load that read the data before an invalidation must not be stored
+func TestInvalidateDuringLoad(t *testing.T) {
+	for name, backend := range backends() {
+		t.Run(name, func(t *testing.T) {
+			c := New(backend(t), time.Minute)
+			ctx := context.Background()
+
+			value, err := c.Fetch(ctx, "clubs:list", func() ([]byte, error) {
+				c.Invalidate(ctx, "clubs:list") // a write lands mid-load
+				return []byte("stale"), nil
+			})
+			if err != nil || string(value) != "stale" {
+				t.Fatalf("fetch = %q, %v; want the loaded value for this caller", value, err)
+			}
+			if value, _ := c.Fetch(ctx, "clubs:list", func() ([]byte, error) { return []byte("fresh"), nil }); string(value) != "fresh" {
+				t.Errorf("next fetch = %q; want the stale value left out of the cache", value)
+			}
+		})
+	}
+}
+
+func TestConcurrentMissesShareOneLoad(t *testing.T) {
+	for name, backend := range backends() {
+		t.Run(name, func(t *testing.T) {
+			c := New(backend(t), time.Minute)
+			ctx := context.Background()
+			release := make(chan struct{})
+			var calls atomic.Int64
+			load := func() ([]byte, error) {
+				calls.Add(1)
+				<-release
+				return []byte("value"), nil
+			}
+
+			var wg sync.WaitGroup
+			results := make([]string, 10)
+			for i := range results {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					value, err := c.Fetch(ctx, "watch_links:list", load)
+					if err != nil {
+						t.Error(err)
+					}
+					results[i] = string(value)
+				}()
+			}
+			// Let every caller miss and join the load in flight
+			time.Sleep(100 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if n := calls.Load(); n != 1 {
+				t.Errorf("loaded %d times; want 1", n)
+			}
+			for i, value := range results {
+				if value != "value" {
+					t.Errorf("caller %d got %q", i, value)
+				}
+			}
+		})
+	}
+}
+
+// failing is a backend that is down
+type failing struct{}
+
+func (failing) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+func (failing) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+func (failing) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("connection refused")
+}
+
+func TestBackendOutageFallsThrough(t *testing.T) {
+	c := New(failing{}, time.Minute)
+	l := &loader{}
+	for i := 0; i < 2; i++ {
+		if value, err := c.Fetch(context.Background(), "clubs:list", l.load); err != nil || value == nil {
+			t.Fatalf("fetch = %q, %v; want the loaded value", value, err)
+		}
+	}
+	if stats := c.Stats()["clubs"]; stats.Errors != 4 || stats.Misses != 2 {
+		t.Errorf("stats = %+v; want 4 errors and 2 misses", stats)
+	}
+}
@@ -0,0 +1,91 @@
+// Package events is an in-process publish/subscribe bus. Events published on
+// one server instance reach the others through a relay (see the handlers'
+// change stream watcher), so the bus drops events it has already delivered.
+package events
+
+import (
+	"sync"
+)
+
+const (
+	// subscriberBuffer is how many events a slow subscriber may fall behind
+	// before further events are dropped for it
+	subscriberBuffer = 64
+	// recentIDs is how many event IDs are remembered for de-duplication
+	recentIDs = 1024
+)
+
+// Event is a single message on the bus
+type Event struct {
+	ID      string
+	Topic   string
+	Payload interface{}
+}
+
+// Subscription receives events until it is closed
+type Subscription struct {
+	C <-chan Event
+
+	bus *Bus
+	ch  chan Event
+}
+
+// Close stops delivery and releases the subscription
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if _, ok := s.bus.subscribers[s]; ok {
+		delete(s.bus.subscribers, s)
+		close(s.ch)
+	}
+}
+
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	seen        map[string]struct{}
+	order       []string
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+		seen:        make(map[string]struct{}),
+	}
+}
+
+func (b *Bus) Subscribe() *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: ch, bus: b, ch: ch}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Publish delivers an event to every subscriber without blocking. It reports
+// false if an event with the same ID was already published.
+func (b *Bus) Publish(event Event) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, dup := b.seen[event.ID]; dup {
+		return false
+	}
+	b.seen[event.ID] = struct{}{}
+	b.order = append(b.order, event.ID)
+	if len(b.order) > recentIDs {
+		delete(b.seen, b.order[0])
+		b.order = b.order[1:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// The subscriber is too slow; it can catch up with Last-Event-ID
+		}
+	}
+	return true
+}
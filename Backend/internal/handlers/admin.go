@@ -2,20 +2,26 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 
 	"fanzone/internal/models"
+	"fanzone/internal/repository"
 )
 
 func (h *Handler) AdminAddClub(c *gin.Context) {
 	var input struct {
-		Name     string `json:"name" binding:"required"`
-		LogoURL  string `json:"logo_url" binding:"required"`
-		LeagueID string `json:"league_id" binding:"required"`
+		Name     string   `json:"name" binding:"required"`
+		Aliases  []string `json:"aliases"`
+		LogoURL  string   `json:"logo_url" binding:"required"`
+		LeagueID string   `json:"league_id" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -23,6 +29,13 @@ func (h *Handler) AdminAddClub(c *gin.Context) {
 		return
 	}
 
+	errs := ValidationErrors{}
+	aliases := cleanAliases(errs, "aliases", input.Aliases)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
 	leagueObjID, err := bson.ObjectIDFromHex(input.LeagueID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid League ID"})
@@ -39,11 +52,16 @@ func (h *Handler) AdminAddClub(c *gin.Context) {
 		return
 	}
 
+	now := time.Now()
 	club := models.Club{
-		ID:       bson.NewObjectID(),
-		Name:     input.Name,
-		LogoURL:  input.LogoURL,
-		LeagueID: leagueObjID,
+		ID:        bson.NewObjectID(),
+		Name:      input.Name,
+		Aliases:   aliases,
+		LogoURL:   input.LogoURL,
+		LeagueID:  leagueObjID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
 	}
 
 	err = h.Repo.CreateClub(ctx, club)
@@ -64,32 +82,54 @@ func (h *Handler) AdminUpdateClub(c *gin.Context) {
 		return
 	}
 
-	var input map[string]interface{}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
 		return
 	}
 
-	if leagueIDStr, ok := input["league_id"].(string); ok && leagueIDStr != "" {
-		leagueObjID, err := bson.ObjectIDFromHex(leagueIDStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid League ID"})
-			return
-		}
-		input["league_id"] = leagueObjID
+	var input clubUpdate
+	if !bindStrict(c, &input) {
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.UpdateClub(ctx, objID, input)
+	update, errs := input.toUpdate(ctx, h)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	version, err := h.Repo.UpdateClub(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.Repo.FindClubByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Club not found")
+			return
+		}
+		respondVersionConflict(c, current, current.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
 		return
 	}
 
 	h.logActivity(c, "Updated Club", "club", id)
-	c.JSON(http.StatusOK, gin.H{"message": "Club updated successfully"})
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Club updated successfully",
+		"version": version,
+	})
 }
 
 func (h *Handler) AdminDeleteClub(c *gin.Context) {
@@ -103,7 +143,19 @@ func (h *Handler) AdminDeleteClub(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.DeleteClub(ctx, objID)
+	err = h.Repo.DeleteClub(ctx, objID, currentUserID(c))
+	var depErr *repository.DependentsError
+	if errors.As(err, &depErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Club is still referenced and cannot be deleted",
+			"dependents": depErr.Dependents,
+		})
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
 		return
@@ -119,7 +171,24 @@ func (h *Handler) AdminAddLeague(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+
+	errs := ValidationErrors{}
+	league.Aliases = cleanAliases(errs, "aliases", league.Aliases)
+	league.Colors = cleanColors(errs, "colors", league.Colors)
+	checkFoundedYear(errs, "founded_year", &league.FoundedYear)
+	league.Tiebreakers = cleanTiebreakers(errs, "tiebreakers", league.Tiebreakers)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	now := time.Now()
 	league.ID = bson.NewObjectID()
+	league.CreatedAt = now
+	league.UpdatedAt = now
+	league.Version = 1
+	league.DeletedAt = nil
+	league.DeletedBy = bson.ObjectID{}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -142,23 +211,57 @@ func (h *Handler) AdminUpdateLeague(c *gin.Context) {
 		return
 	}
 
-	var input map[string]interface{}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var input leagueUpdate
+	if !bindStrict(c, &input) {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.UpdateLeague(ctx, objID, input)
+	update, errs := input.toUpdate(ctx, h)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	version, err := h.Repo.UpdateLeague(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.Repo.FindLeagueByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "League not found")
+			return
+		}
+		respondVersionConflict(c, current, current.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
 		return
 	}
 
+	if _, ok := update["tiebreakers"]; ok {
+		h.queueStandings(objID, "")
+	}
 	h.logActivity(c, "Updated League", "league", id)
-	c.JSON(http.StatusOK, gin.H{"message": "League updated successfully"})
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "League updated successfully",
+		"version": version,
+	})
 }
 
 func (h *Handler) AdminDeleteLeague(c *gin.Context) {
@@ -172,7 +275,19 @@ func (h *Handler) AdminDeleteLeague(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.DeleteLeague(ctx, objID)
+	err = h.Repo.DeleteLeague(ctx, objID, currentUserID(c))
+	var depErr *repository.DependentsError
+	if errors.As(err, &depErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "League is still referenced and cannot be deleted",
+			"dependents": depErr.Dependents,
+		})
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
 		return
@@ -184,11 +299,15 @@ func (h *Handler) AdminDeleteLeague(c *gin.Context) {
 
 func (h *Handler) AdminAddContent(c *gin.Context) {
 	var input struct {
-		Title    models.MultiLangString `json:"title" binding:"required"`
-		Body     models.MultiLangString `json:"body" binding:"required"`
-		ImageURL string                 `json:"image_url" binding:"required"`
-		Category string                 `json:"category" binding:"required"`
-		ClubID   string                 `json:"club_id"`
+		Title     models.MultiLangString `json:"title" binding:"required"`
+		Body      models.MultiLangString `json:"body" binding:"required"`
+		ImageURL  string                 `json:"image_url" binding:"required"`
+		Category  string                 `json:"category" binding:"required"`
+		ClubID    string                 `json:"club_id"`
+		LeagueIDs []string               `json:"league_ids"`
+		MatchID   string                 `json:"match_id"`
+		PlayerIDs []string               `json:"player_ids"`
+		Breaking  bool                   `json:"breaking"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -196,11 +315,31 @@ func (h *Handler) AdminAddContent(c *gin.Context) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errs := ValidationErrors{}
+	checkMultiLang(errs, "title", &input.Title)
+	checkURL(errs, "image_url", &input.ImageURL)
+	if !isAllowedCategory(input.Category) {
+		errs.Add("category", "must be one of: "+strings.Join(models.ContentCategories, ", "))
+	}
 	var clubObjID bson.ObjectID
-	if input.ClubID != "" {
-		clubObjID, _ = bson.ObjectIDFromHex(input.ClubID)
+	if !isGeneralClubID(input.ClubID) {
+		clubObjID = h.resolveClubID(ctx, errs, "club_id", input.ClubID)
+	}
+	leagueObjIDs := h.resolveLeagueIDs(ctx, errs, "league_ids", input.LeagueIDs)
+	var matchObjID bson.ObjectID
+	if input.MatchID != "" {
+		matchObjID = h.resolveMatchID(ctx, errs, "match_id", input.MatchID)
+	}
+	playerObjIDs := h.resolvePlayerIDs(ctx, errs, "player_ids", input.PlayerIDs)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
 	}
 
+	now := time.Now()
 	content := models.Content{
 		ID:        bson.NewObjectID(),
 		Title:     input.Title,
@@ -208,18 +347,26 @@ func (h *Handler) AdminAddContent(c *gin.Context) {
 		ImageURL:  input.ImageURL,
 		Category:  input.Category,
 		ClubID:    clubObjID,
-		CreatedAt: time.Now(),
+		LeagueIDs: leagueObjIDs,
+		MatchID:   matchObjID,
+		PlayerIDs: playerObjIDs,
+		Breaking:  input.Breaking,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	err := h.Repo.CreateContent(ctx, content)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add content"})
 		return
 	}
 
+	if _, err := h.recordRevision(ctx, c, "content", content.ID, "create", nil); err != nil {
+		log.Printf("Failed to record revision for content %s: %v", content.ID.Hex(), err)
+	}
+
+	h.publishFeedEvent(newsEvent(content))
 	h.logActivity(c, "Added Content", "content", content.ID.Hex())
 	c.JSON(http.StatusCreated, content)
 }
@@ -232,29 +379,74 @@ func (h *Handler) AdminUpdateContent(c *gin.Context) {
 		return
 	}
 
-	var input map[string]interface{}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
 		return
 	}
 
-	// Convert club_id string to ObjectID if present
-	if clubIDStr, ok := input["club_id"].(string); ok && clubIDStr != "" {
-		clubObjID, _ := bson.ObjectIDFromHex(clubIDStr)
-		input["club_id"] = clubObjID
+	var input contentUpdate
+	if !bindStrict(c, &input) {
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.UpdateContent(ctx, objID, input)
+	current, err := h.Repo.FindContentByID(ctx, objID)
+	if err != nil {
+		respondLookupError(c, err, "Content not found")
+		return
+	}
+
+	update, errs := input.toUpdate(ctx, h, current)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	if err := h.ensureBaseRevision(ctx, c, "content", objID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+	before, err := h.loadSnapshot(ctx, "content", objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+
+	version, err := h.Repo.UpdateContent(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.Repo.FindContentByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Content not found")
+			return
+		}
+		respondVersionConflict(c, current, current.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
 		return
 	}
 
+	if _, err := h.recordRevision(ctx, c, "content", objID, "update", before); err != nil {
+		log.Printf("Failed to record revision for content %s: %v", id, err)
+	}
+
 	h.logActivity(c, "Updated Content", "content", id)
-	c.JSON(http.StatusOK, gin.H{"message": "Content updated successfully"})
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Content updated successfully",
+		"version": version,
+	})
 }
 
 func (h *Handler) AdminAddHighlight(c *gin.Context) {
@@ -262,6 +454,10 @@ func (h *Handler) AdminAddHighlight(c *gin.Context) {
 		MatchTitle string   `json:"match_title" binding:"required"`
 		YoutubeURL string   `json:"youtube_url" binding:"required"`
 		ClubIDs    []string `json:"club_ids" binding:"required"`
+		LeagueIDs  []string `json:"league_ids"`
+		MatchID    string   `json:"match_id"`
+		PlayerIDs  []string `json:"player_ids"`
+		Breaking   bool     `json:"breaking"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -269,30 +465,49 @@ func (h *Handler) AdminAddHighlight(c *gin.Context) {
 		return
 	}
 
-	var clubObjIDs []bson.ObjectID
-	for _, idStr := range input.ClubIDs {
-		if objID, err := bson.ObjectIDFromHex(idStr); err == nil {
-			clubObjIDs = append(clubObjIDs, objID)
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errs := ValidationErrors{}
+	checkURL(errs, "youtube_url", &input.YoutubeURL)
+	clubObjIDs := h.resolveClubIDs(ctx, errs, "club_ids", input.ClubIDs)
+	leagueObjIDs := h.resolveLeagueIDs(ctx, errs, "league_ids", input.LeagueIDs)
+	var matchObjID bson.ObjectID
+	if input.MatchID != "" {
+		matchObjID = h.resolveMatchID(ctx, errs, "match_id", input.MatchID)
+	}
+	playerObjIDs := h.resolvePlayerIDs(ctx, errs, "player_ids", input.PlayerIDs)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
 	}
 
+	now := time.Now()
 	highlight := models.Highlight{
 		ID:         bson.NewObjectID(),
 		MatchTitle: input.MatchTitle,
 		YoutubeURL: input.YoutubeURL,
 		ClubIDs:    clubObjIDs,
-		CreatedAt:  time.Now(),
+		LeagueIDs:  leagueObjIDs,
+		MatchID:    matchObjID,
+		PlayerIDs:  playerObjIDs,
+		Breaking:   input.Breaking,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Version:    1,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
 	err := h.Repo.CreateHighlight(ctx, highlight)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add highlight"})
 		return
 	}
 
+	if _, err := h.recordRevision(ctx, c, "highlight", highlight.ID, "create", nil); err != nil {
+		log.Printf("Failed to record revision for highlight %s: %v", highlight.ID.Hex(), err)
+	}
+
+	h.publishFeedEvent(highlightEvent(highlight))
 	h.logActivity(c, "Added Highlight", "highlight", highlight.MatchTitle)
 	c.JSON(http.StatusCreated, highlight)
 }
@@ -305,36 +520,68 @@ func (h *Handler) AdminUpdateHighlight(c *gin.Context) {
 		return
 	}
 
-	var input map[string]interface{}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
 		return
 	}
 
-	// Convert club_ids strings to ObjectIDs if present
-	if ids, ok := input["club_ids"].([]interface{}); ok {
-		var clubObjIDs []bson.ObjectID
-		for _, idAny := range ids {
-			if idStr, ok := idAny.(string); ok {
-				if oid, err := bson.ObjectIDFromHex(idStr); err == nil {
-					clubObjIDs = append(clubObjIDs, oid)
-				}
-			}
-		}
-		input["club_ids"] = clubObjIDs
+	var input highlightUpdate
+	if !bindStrict(c, &input) {
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.UpdateHighlight(ctx, objID, input)
+	update, errs := input.toUpdate(ctx, h)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	if err := h.ensureBaseRevision(ctx, c, "highlight", objID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Highlight not found"})
+		return
+	}
+	before, err := h.loadSnapshot(ctx, "highlight", objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Highlight not found"})
+		return
+	}
+
+	version, err := h.Repo.UpdateHighlight(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.Repo.FindHighlightByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Highlight not found")
+			return
+		}
+		respondVersionConflict(c, current, current.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Highlight not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
 		return
 	}
 
+	if _, err := h.recordRevision(ctx, c, "highlight", objID, "update", before); err != nil {
+		log.Printf("Failed to record revision for highlight %s: %v", id, err)
+	}
+
 	h.logActivity(c, "Updated Highlight", "highlight", id)
-	c.JSON(http.StatusOK, gin.H{"message": "Highlight updated successfully"})
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Highlight updated successfully",
+		"version": version,
+	})
 }
 
 func (h *Handler) AdminDeleteHighlight(c *gin.Context) {
@@ -348,7 +595,11 @@ func (h *Handler) AdminDeleteHighlight(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.DeleteHighlight(ctx, objID)
+	err = h.Repo.DeleteHighlight(ctx, objID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Highlight not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
 		return
@@ -359,16 +610,20 @@ func (h *Handler) AdminDeleteHighlight(c *gin.Context) {
 }
 
 func (h *Handler) AdminAddWatchLink(c *gin.Context) {
-	var link models.WatchLink
-	if err := c.ShouldBindJSON(&link); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var input watchLinkInput
+	if !bindStrict(c, &input) {
 		return
 	}
-	link.ID = bson.NewObjectID()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	link, errs := input.toWatchLink(ctx, h)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
 	err := h.Repo.CreateWatchLink(ctx, link)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add link"})
@@ -387,23 +642,59 @@ func (h *Handler) AdminUpdateWatchLink(c *gin.Context) {
 		return
 	}
 
-	var input map[string]interface{}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var input watchLinkUpdate
+	if !bindStrict(c, &input) {
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.UpdateWatchLink(ctx, objID, input)
+	current, err := h.Repo.FindWatchLinkByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Watch link not found"})
+		return
+	}
+	update, errs := input.toUpdate(ctx, h, current)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	version, err := h.Repo.UpdateWatchLink(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.Repo.FindWatchLinkByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Watch link not found")
+			return
+		}
+		respondVersionConflict(c, current, current.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Watch link not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
 		return
 	}
 
 	h.logActivity(c, "Updated Watch Link", "watch_link", id)
-	c.JSON(http.StatusOK, gin.H{"message": "Watch link updated successfully"})
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Watch link updated successfully",
+		"version": version,
+	})
 }
 
 func (h *Handler) AdminDeleteWatchLink(c *gin.Context) {
@@ -417,7 +708,11 @@ func (h *Handler) AdminDeleteWatchLink(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err = h.Repo.DeleteWatchLink(ctx, objID)
+	err = h.Repo.DeleteWatchLink(ctx, objID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Watch link not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
 		return
@@ -434,7 +729,11 @@ func (h *Handler) AdminDeleteContent(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	err := h.Repo.DeleteContent(ctx, objID)
+	err := h.Repo.DeleteContent(ctx, objID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete"})
 		return
@@ -457,6 +756,11 @@ func (h *Handler) GetStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetCacheStats reports reference data cache hits and misses per collection
+func (h *Handler) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Repo.Cache.Stats())
+}
+
 func (h *Handler) GetAllUsers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -512,13 +816,8 @@ func (h *Handler) GetActivityFeed(c *gin.Context) {
 }
 
 func (h *Handler) logActivity(c *gin.Context, action, entity, detail string) {
-	userIDStr, ok := c.Get("userID")
-	if !ok {
-		return
-	}
-
-	userID, err := bson.ObjectIDFromHex(userIDStr.(string))
-	if err != nil {
+	userID := currentUserID(c)
+	if userID.IsZero() {
 		return
 	}
 
@@ -533,3 +832,21 @@ func (h *Handler) logActivity(c *gin.Context, action, entity, detail string) {
 
 	_ = h.Repo.LogActivity(context.Background(), activity)
 }
+
+// currentUserID returns the authenticated caller's ID, or a zero ID when the
+// request carries none.
+func currentUserID(c *gin.Context) bson.ObjectID {
+	userIDStr, ok := c.Get("userID")
+	if !ok {
+		return bson.ObjectID{}
+	}
+	idStr, ok := userIDStr.(string)
+	if !ok {
+		return bson.ObjectID{}
+	}
+	userID, err := bson.ObjectIDFromHex(idStr)
+	if err != nil {
+		return bson.ObjectID{}
+	}
+	return userID
+}
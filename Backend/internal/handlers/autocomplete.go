@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"fanzone/internal/search"
+)
+
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 20
+	// maxAutocompleteLength caps the typed text, in characters
+	maxAutocompleteLength = 100
+)
+
+// Suggestion is one autocomplete match. Matched is the name, translation or
+// alias that matched, so clients can show "Man Utd → Manchester United".
+type Suggestion struct {
+	Type        string  `json:"type"` // "club" or "league"
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	DisplayName string  `json:"display_name"`
+	Matched     string  `json:"matched"`
+	LogoURL     string  `json:"logo_url"`
+	LeagueID    string  `json:"league_id,omitempty"`
+	Score       float64 `json:"score"`
+}
+
+// Autocomplete suggests clubs and leagues for what has been typed so far,
+// in either script, matching names, their translations and admin-managed
+// aliases. Display names are in the negotiated language.
+func (h *Handler) Autocomplete(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if utf8.RuneCountInString(q) > maxAutocompleteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be at most 100 characters"})
+		return
+	}
+
+	types := c.DefaultQuery("type", "club,league")
+	wantClubs, wantLeagues := false, false
+	for _, t := range strings.Split(types, ",") {
+		switch strings.TrimSpace(t) {
+		case "club":
+			wantClubs = true
+		case "league":
+			wantLeagues = true
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "type must be club, league or both"})
+			return
+		}
+	}
+
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultSuggestionLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		limit = min(n, maxSuggestionLimit)
+	}
+
+	suggestions := []Suggestion{}
+	if wantClubs {
+		clubs, err := h.Repo.GetClubs(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
+			return
+		}
+		for _, club := range clubs {
+			if score, matched := bestNameMatch(q, clubNames(club)); score > 0 {
+				suggestions = append(suggestions, Suggestion{
+					Type: "club", ID: club.ID.Hex(), Name: club.Name, DisplayName: localize(club.Names, lang, club.Name),
+					Matched: matched, LogoURL: club.LogoURL, LeagueID: club.LeagueID.Hex(), Score: score,
+				})
+			}
+		}
+	}
+	if wantLeagues {
+		leagues, err := h.Repo.GetLeagues(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching leagues"})
+			return
+		}
+		for _, league := range leagues {
+			if score, matched := bestNameMatch(q, leagueNames(league)); score > 0 {
+				suggestions = append(suggestions, Suggestion{
+					Type: "league", ID: league.ID.Hex(), Name: league.Name, DisplayName: localize(league.Names, lang, league.Name),
+					Matched: matched, LogoURL: league.LogoURL, Score: score,
+				})
+			}
+		}
+	}
+
+	// Best match first, then shorter and alphabetical names
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return a.Name < b.Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": q, "suggestions": suggestions})
+}
+
+// bestNameMatch scores every name and returns the best score and the name
+// it matched. Earlier names win ties.
+func bestNameMatch(q string, names []string) (float64, string) {
+	best, matched := 0.0, ""
+	for _, name := range names {
+		if score := search.NameScore(q, name); score > best {
+			best, matched = score, name
+		}
+	}
+	return best, matched
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/ical"
+	"fanzone/internal/models"
+)
+
+// Fixture calendars cover the last month's results and every match to come
+const (
+	calendarHistory    = 30 * 24 * time.Hour
+	calendarMaxMatches = 500
+	calendarRefresh    = time.Hour
+	// matchLength is how long a match blocks in the calendar
+	matchLength = 2 * time.Hour
+)
+
+// fixturesCalendar builds the calendar of the clubs' matches
+func (h *Handler) fixturesCalendar(ctx context.Context, name, description string, clubIDs []bson.ObjectID, lang string) (ical.Calendar, time.Time, error) {
+	cal := ical.Calendar{Name: name, Description: description, Refresh: calendarRefresh}
+	var stamp time.Time
+	if len(clubIDs) == 0 {
+		return cal, stamp, nil
+	}
+
+	filter := bson.M{
+		"$or":        bson.A{bson.M{"home_club_id": bson.M{"$in": clubIDs}}, bson.M{"away_club_id": bson.M{"$in": clubIDs}}},
+		"kickoff_at": bson.M{"$gte": time.Now().Add(-calendarHistory)},
+	}
+	matches, err := h.Repo.GetMatches(ctx, filter, true, calendarMaxMatches)
+	if err != nil {
+		return cal, stamp, err
+	}
+	views, err := h.matchViews(ctx, matches, lang)
+	if err != nil {
+		return cal, stamp, err
+	}
+	clubs, err := h.Repo.GetClubs(ctx)
+	if err != nil {
+		return cal, stamp, err
+	}
+	stadiums := make(map[bson.ObjectID]string, len(clubs))
+	for _, club := range clubs {
+		stadiums[club.ID] = localize(club.Stadium, lang, "")
+	}
+	links, err := h.Repo.GetWatchLinks(ctx)
+	if err != nil {
+		return cal, stamp, err
+	}
+
+	for _, view := range views {
+		if view.UpdatedAt.After(stamp) {
+			stamp = view.UpdatedAt
+		}
+		cal.Events = append(cal.Events, matchEvent(view, stadiums[view.HomeClubID], matchWatchLinks(links, &view.Match)))
+	}
+	for _, link := range links {
+		if link.UpdatedAt.After(stamp) {
+			stamp = link.UpdatedAt
+		}
+	}
+	return cal, stamp, nil
+}
+
+// matchEvent describes a match as a calendar event, with the links that
+// show it
+func matchEvent(view MatchView, stadium string, links []models.WatchLink) ical.Event {
+	home, away := "TBD", "TBD"
+	if view.HomeClub != nil {
+		home = view.HomeClub.DisplayName
+	}
+	if view.AwayClub != nil {
+		away = view.AwayClub.DisplayName
+	}
+
+	event := ical.Event{
+		UID:          view.ID.Hex() + "@fanzone",
+		Sequence:     view.Version,
+		Start:        view.KickoffAt,
+		End:          view.KickoffAt.Add(matchLength),
+		Summary:      home + " vs " + away,
+		Location:     view.Venue,
+		Status:       ical.StatusConfirmed,
+		LastModified: view.UpdatedAt,
+	}
+	if event.Location == "" {
+		event.Location = stadium
+	}
+	if view.Score != nil && view.Status != models.MatchScheduled {
+		event.Summary = fmt.Sprintf("%s %d–%d %s", home, view.Score.Home, view.Score.Away, away)
+	}
+
+	var description []string
+	if view.League != nil {
+		event.Categories = []string{view.League.DisplayName}
+		description = append(description, view.League.DisplayName+" "+view.Season)
+	}
+	switch view.Status {
+	case models.MatchPostponed:
+		event.Status = ical.StatusTentative
+		description = append(description, "Postponed; the new kickoff is not set yet.")
+	case models.MatchCancelled:
+		event.Status = ical.StatusCancelled
+	}
+	if len(links) > 0 && view.Status != models.MatchCancelled {
+		description = append(description, "", "Watch on:")
+		for _, link := range links {
+			name := link.Name
+			if link.Access != "" {
+				name += " (" + link.Access + ")"
+			}
+			description = append(description, name+": "+link.URL)
+		}
+		event.URL = links[0].URL
+	}
+	event.Description = strings.Join(description, "\n")
+	return event
+}
+
+func respondCalendar(c *gin.Context, fileName string, cal ical.Calendar, stamp time.Time) {
+	if stamp.IsZero() {
+		stamp = time.Unix(0, 0)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, fileName))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", cal.Write(stamp))
+}
+
+// GetClubFixturesCalendar returns a club's fixtures as an iCalendar feed
+func (h *Handler) GetClubFixturesCalendar(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	club, err := h.Repo.FindClubByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+	name := localizeClub(*club, lang).DisplayName
+	cal, stamp, err := h.fixturesCalendar(ctx, name+" fixtures", "Matches of "+name, []bson.ObjectID{club.ID}, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+		return
+	}
+	respondCalendar(c, "fixtures.ics", cal, stamp)
+}
+
+// GetPersonalCalendar returns the fixtures of every club a user follows. It
+// is authenticated by the secret token in its URL, since calendar apps
+// cannot send a bearer token.
+func (h *Handler) GetPersonalCalendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	if token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.Repo.FindUserByCalendarToken(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Calendar not found"})
+		return
+	}
+
+	// Calendar apps rarely send Accept-Language, so the profile language
+	// applies unless ?lang is given
+	lang := user.Language
+	if c.Query("lang") != "" || !isSupportedLanguage(lang) {
+		negotiated, ok := negotiateLanguage(c)
+		if !ok {
+			return
+		}
+		lang = negotiated
+	}
+	cal, stamp, err := h.fixturesCalendar(ctx, "My FanZone fixtures", "Matches of the clubs you follow", followedClubIDs(user), lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+		return
+	}
+	respondCalendar(c, "fanzone.ics", cal, stamp)
+}
+
+// calendarURLs returns the https and webcal URLs of a personal calendar
+func calendarURLs(c *gin.Context, token string) gin.H {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := c.Request.Host
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	path := host + "/api/calendar/" + token + ".ics"
+	return gin.H{"url": scheme + "://" + path, "webcal_url": "webcal://" + path}
+}
+
+// GetCalendarSubscription returns the user's personal calendar URL,
+// creating it on first use
+func (h *Handler) GetCalendarSubscription(c *gin.Context) {
+	userID := currentUserID(c)
+	if userID.IsZero() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.Repo.FindUserByID(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	token := user.CalendarToken
+	if token == "" {
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar URL"})
+			return
+		}
+		token = base64.RawURLEncoding.EncodeToString(secret)
+		if err := h.Repo.SetCalendarToken(ctx, userID, token); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create calendar URL"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, calendarURLs(c, token))
+}
+
+// RevokeCalendarSubscription disables the user's calendar URL; the next
+// request for it creates a new one
+func (h *Handler) RevokeCalendarSubscription(c *gin.Context) {
+	userID := currentUserID(c)
+	if userID.IsZero() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := h.Repo.SetCalendarToken(ctx, userID, "")
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke calendar URL"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar URL revoked"})
+}
@@ -7,18 +7,55 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
 )
 
+// ClubView is a club with its names resolved to the negotiated language
+type ClubView struct {
+	models.Club
+	Language         string `json:"language"`
+	DisplayName      string `json:"display_name"`
+	DisplayShortName string `json:"display_short_name"`
+	DisplayStadium   string `json:"display_stadium,omitempty"`
+}
+
+func localizeClub(club models.Club, lang string) ClubView {
+	name := localize(club.Names, lang, club.Name)
+	return ClubView{
+		Club:             club,
+		Language:         lang,
+		DisplayName:      name,
+		DisplayShortName: localize(club.ShortNames, lang, name),
+		DisplayStadium:   localize(club.Stadium, lang, ""),
+	}
+}
+
+// clubNames lists every name a club can be found by
+func clubNames(club models.Club) []string {
+	return append(translations(club.Name, club.Names, club.ShortNames), club.Aliases...)
+}
+
 func (h *Handler) GetClubs(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
 	clubs, err := h.Repo.GetClubs(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
 		return
 	}
-	c.JSON(http.StatusOK, clubs)
+
+	views := make([]ClubView, len(clubs))
+	for i, club := range clubs {
+		views[i] = localizeClub(club, lang)
+	}
+	c.JSON(http.StatusOK, views)
 }
 
 func (h *Handler) GetClubByID(c *gin.Context) {
@@ -28,6 +65,10 @@ func (h *Handler) GetClubByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -37,5 +78,7 @@ func (h *Handler) GetClubByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
 		return
 	}
-	c.JSON(http.StatusOK, club)
+	setLocalizedETag(c, club.Version, lang)
+	setLastModified(c, club.UpdatedAt)
+	c.JSON(http.StatusOK, localizeClub(*club, lang))
 }
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/repository"
+)
+
+// versionETag formats a document version as a strong entity tag
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func setVersionETag(c *gin.Context, version int64) {
+	c.Header("ETag", versionETag(version))
+}
+
+// setLocalizedETag tags a translated document with its version and language,
+// e.g. "3-am", so one language's copy never answers for another's. If-Match
+// accepts it like the plain version. negotiateLanguage has set Vary.
+func setLocalizedETag(c *gin.Context, version int64, lang string) {
+	c.Header("ETag", `"`+strconv.FormatInt(version, 10)+"-"+lang+`"`)
+}
+
+// setLastModified sets Last-Modified, which has second precision
+func setLastModified(c *gin.Context, t time.Time) {
+	if !t.IsZero() {
+		c.Header("Last-Modified", t.UTC().Truncate(time.Second).Format(http.TimeFormat))
+	}
+}
+
+// requireIfMatch reads the document version the client last saw from the
+// If-Match header; "*" matches any version. It writes the error response
+// itself and reports whether a version was found.
+func requireIfMatch(c *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the document version is required"})
+		return 0, false
+	}
+	if header == "*" {
+		return repository.AnyVersion, true
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	tag, _, _ = strings.Cut(tag, "-")
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+		return 0, false
+	}
+	return version, true
+}
+
+// respondVersionConflict rejects a stale write, sending back the current
+// document so the client can merge and retry.
+func respondVersionConflict(c *gin.Context, current interface{}, version int64) {
+	setVersionETag(c, version)
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":   "The document was modified by someone else",
+		"current": current,
+	})
+}
+
+// respondLookupError answers when the document a write was rejected for
+// cannot be read back, e.g. because it was deleted in the meantime
+func respondLookupError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/middleware"
+	"fanzone/internal/repository"
+)
+
+func TestLocalizedETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/clubs/:id", middleware.ConditionalGET(middleware.CacheReference), func(c *gin.Context) {
+		lang, ok := negotiateLanguage(c)
+		if !ok {
+			return
+		}
+		setLocalizedETag(c, 3, lang)
+		c.JSON(http.StatusOK, gin.H{"display_name": "Saint George (" + lang + ")"})
+	})
+	get := func(acceptLanguage, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/clubs/1", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	en := get("en", "")
+	if tag := en.Header().Get("ETag"); tag != `"3-en"` {
+		t.Fatalf("ETag = %s; want \"3-en\"", tag)
+	}
+	if vary := en.Header().Get("Vary"); vary != "Accept-Language" {
+		t.Errorf("Vary = %q; want Accept-Language", vary)
+	}
+	if w := get("en", `"3-en"`); w.Code != http.StatusNotModified {
+		t.Errorf("same language: status %d; want 304", w.Code)
+	}
+	if w := get("am", `"3-en"`); w.Code != http.StatusOK || w.Header().Get("ETag") != `"3-am"` {
+		t.Errorf("other language: status %d, ETag %s; want 200 with \"3-am\"", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestRequireIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		header  string
+		version int64
+		ok      bool
+	}{
+		{`"3"`, 3, true},
+		{`W/"3"`, 3, true},
+		{`"3-am"`, 3, true},
+		{"*", repository.AnyVersion, true},
+		{"", 0, false},
+		{`"abc"`, 0, false},
+		{`"-1"`, 0, false},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+		c.Request.Header.Set("If-Match", tt.header)
+		version, ok := requireIfMatch(c)
+		if ok != tt.ok || (ok && version != tt.version) {
+			t.Errorf("If-Match %s = %d, %v; want %d, %v", tt.header, version, ok, tt.version, tt.ok)
+		}
+	}
+}
+
+func TestRespondLookupError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{mongo.ErrNoDocuments, http.StatusNotFound},
+		{fmt.Errorf("decode: %w", mongo.ErrNoDocuments), http.StatusNotFound},
+		{context.DeadlineExceeded, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		respondLookupError(c, tt.err, "Club not found")
+		if w.Code != tt.code {
+			t.Errorf("%v: status %d; want %d", tt.err, w.Code, tt.code)
+		}
+	}
+}
@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
 )
 
 func (h *Handler) GetContent(c *gin.Context) {
@@ -49,6 +54,11 @@ func (h *Handler) GetContentByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
 		return
 	}
+	if err := h.Repo.IncrementViews(ctx, "content", objID); err != nil {
+		log.Printf("Failed to count view of content %s: %v", id, err)
+	}
+	setVersionETag(c, content.Version)
+	setLastModified(c, content.UpdatedAt)
 	c.JSON(http.StatusOK, content)
 }
 
@@ -88,33 +98,106 @@ func (h *Handler) GetHighlightByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Highlight not found"})
 		return
 	}
+	if err := h.Repo.IncrementViews(ctx, "highlights", objID); err != nil {
+		log.Printf("Failed to count view of highlight %s: %v", id, err)
+	}
+	setVersionETag(c, highlight.Version)
+	setLastModified(c, highlight.UpdatedAt)
 	c.JSON(http.StatusOK, highlight)
 }
 
+// GetWatchLinks lists where to watch, optionally only the links covering a
+// match, club or league, playing in a country, free or paid, or with
+// commentary in a language. Links for the match itself come first, then
+// those for its clubs, its league and every match. Expired links are left
+// out.
 func (h *Handler) GetWatchLinks(c *gin.Context) {
-	clubID := c.Query("club_id")
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	errs := ValidationErrors{}
+	country := strings.ToUpper(c.Query("country"))
+	if country != "" && !countryCode.MatchString(country) {
+		errs.Add("country", "must be an ISO 3166-1 alpha-2 country code, e.g. ET")
+	}
+	language := strings.ToLower(c.Query("language"))
+	if language != "" && !languageCode.MatchString(language) {
+		errs.Add("language", "must be an ISO 639-1 language code, e.g. am")
+	}
+	access := c.Query("access")
+	checkWatchAccess(errs, "access", &access)
+
+	var coverages []func(models.WatchLink) int
+	now := time.Now()
+	available := func(link models.WatchLink) bool { return availableDuring(link, now, now) }
+	if raw := c.Query("match_id"); raw != "" {
+		if id := h.resolveMatchID(ctx, errs, "match_id", raw); len(errs) == 0 {
+			match, err := h.Repo.FindMatchByID(ctx, id)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching match"})
+				return
+			}
+			available = func(link models.WatchLink) bool {
+				return availableDuring(link, match.KickoffAt, match.KickoffAt.Add(matchLength))
+			}
+			coverages = append(coverages, func(link models.WatchLink) int { return matchCoverage(link, match) })
+		}
+	}
+	if raw := c.Query("club_id"); raw != "" {
+		if id := h.resolveClubID(ctx, errs, "club_id", raw); len(errs) == 0 {
+			club, err := h.Repo.FindClubByID(ctx, id)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching club"})
+				return
+			}
+			filter := bson.M{
+				"$or":        bson.A{bson.M{"home_club_id": id}, bson.M{"away_club_id": id}},
+				"kickoff_at": bson.M{"$gte": now.Add(-matchLength)},
+			}
+			upcoming, err := h.Repo.GetMatches(ctx, filter, true, 50)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+				return
+			}
+			coverages = append(coverages, func(link models.WatchLink) int { return clubCoverage(link, club, upcoming) })
+			if c.Query("match_id") == "" {
+				available = func(link models.WatchLink) bool { return availableForClub(link, upcoming, now) }
+			}
+		}
+	}
+	if raw := c.Query("league_id"); raw != "" {
+		leagueID := h.resolveLeagueID(ctx, errs, "league_id", raw)
+		coverages = append(coverages, func(link models.WatchLink) int { return leagueCoverage(link, leagueID) })
+	}
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
 	links, err := h.Repo.GetWatchLinks(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching links"})
 		return
 	}
 
-	// If clubId is provided, filter by club or return all general platforms
-	// Note: Current WatchLink model doesn't have club_id field
-	// This is a placeholder for future enhancement when WatchLink model is updated
-	if clubID != "" {
-		// For now, return all links as the model doesn't support club filtering yet
-		// TODO: Add club_id field to WatchLink model for club-specific filtering
-		c.JSON(http.StatusOK, gin.H{
-			"links":   links,
-			"message": "Club-specific filtering not yet implemented in data model",
-		})
-		return
-	}
+	// A link has to cover every target asked for; the closest decides its rank
+	links = rankWatchLinks(links, func(link models.WatchLink) int {
+		if !available(link) ||
+			(country != "" && !availableIn(link, country)) ||
+			(access != "" && link.Access != access) ||
+			(language != "" && !slices.Contains(link.CommentaryLanguages, language)) {
+			return coverageNone
+		}
+		rank := coverageAll
+		for _, coverage := range coverages {
+			r := coverage(link)
+			if r == coverageNone {
+				return coverageNone
+			}
+			rank = max(rank, r)
+		}
+		return rank
+	})
 
 	c.JSON(http.StatusOK, links)
 }
@@ -135,5 +218,7 @@ func (h *Handler) GetWatchLinkByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Watch link not found"})
 		return
 	}
+	setVersionETag(c, link.Version)
+	setLastModified(c, link.UpdatedAt)
 	c.JSON(http.StatusOK, link)
 }
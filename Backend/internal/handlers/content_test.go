@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+// Saving a General article sends back the club_id it was read with
+func TestContentUpdateGeneralClub(t *testing.T) {
+	for _, raw := range []string{"", generalClubID, bson.NilObjectID.Hex()} {
+		update, errs := contentUpdate{ClubID: &raw}.toUpdate(context.Background(), nil, &models.Content{})
+		if len(errs) != 0 {
+			t.Errorf("club_id %q: errors %v; want none", raw, errs)
+		}
+		if value, ok := update["club_id"]; !ok || value != nil {
+			t.Errorf("club_id %q: update %v; want club_id removed", raw, update)
+		}
+	}
+}
+
+func TestContentUpdateCategory(t *testing.T) {
+	current := &models.Content{Category: "general"}
+	tests := []struct {
+		category string
+		ok       bool
+	}{
+		{"News", true},
+		{"injury_update", true},
+		{"general", true}, // legacy value sent back unchanged
+		{"gossip", false},
+	}
+	for _, tt := range tests {
+		_, errs := contentUpdate{Category: &tt.category}.toUpdate(context.Background(), nil, current)
+		if (errs["category"] == "") != tt.ok {
+			t.Errorf("category %q: errors %v; want ok %v", tt.category, errs, tt.ok)
+		}
+	}
+}
@@ -2,13 +2,26 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 
 	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+	// maxPinnedItems caps how many pinned items head the first page
+	maxPinnedItems = 10
 )
 
 // FeedItem represents a unified feed item (news or highlight)
@@ -22,6 +35,9 @@ type FeedItem struct {
 	Category  string                 `json:"category,omitempty"`
 	ClubID    string                 `json:"club_id,omitempty"`
 	ClubIDs   []string               `json:"club_ids,omitempty"`
+	LeagueIDs []string               `json:"league_ids,omitempty"`
+	MatchID   string                 `json:"match_id,omitempty"`
+	PlayerIDs []string               `json:"player_ids,omitempty"`
 	CreatedAt time.Time              `json:"created_at"`
 	Extra     map[string]interface{} `json:"extra,omitempty"`
 }
@@ -45,72 +61,65 @@ func (h *Handler) GetMyClubFeed(c *gin.Context) {
 		return
 	}
 
+	sortMode := c.DefaultQuery("sort", "latest")
+	if sortMode != "latest" && sortMode != "ranked" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be 'latest' or 'ranked'"})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// Get user's favorite club
 	user, err := h.Repo.FindUserByID(ctx, objID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	if user.FavClubID.IsZero() {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User has no favorite club set"})
-		return
+	// Followed leagues contribute the clubs that play in them
+	var leagueClubIDs []bson.ObjectID
+	if len(user.FollowedLeagueIDs) > 0 {
+		leagueClubIDs, err = h.Repo.GetClubIDsByLeagues(ctx, user.FollowedLeagueIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching followed leagues"})
+			return
+		}
+	}
+	clubIDs := append(followedClubIDs(user), leagueClubIDs...)
+
+	// Users who follow nothing yet get the general feed
+	newsFilter, highlightFilter := bson.M{}, bson.M{}
+	extra := gin.H{"mode": "general"}
+	if len(clubIDs) > 0 || len(user.FollowedLeagueIDs) > 0 {
+		newsFilter, highlightFilter = targetingFilters(clubIDs, user.FollowedLeagueIDs)
+		extra["mode"] = "personal"
+	}
+	if !user.FavClubID.IsZero() {
+		extra["club_id"] = user.FavClubID.Hex()
 	}
 
-	// Fetch news for the user's favorite club
-	newsFilter := bson.M{"club_id": user.FavClubID}
-	news, err := h.Repo.GetContent(ctx, newsFilter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching news"})
+	if sortMode == "ranked" {
+		h.respondRankedFeed(ctx, c, user, leagueClubIDs, newsFilter, highlightFilter, extra)
 		return
 	}
 
-	// Fetch highlights for the user's favorite club
-	highlightFilter := bson.M{"club_ids": user.FavClubID}
-	highlights, err := h.Repo.GetHighlights(ctx, highlightFilter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching highlights"})
+	limit, after, ok := parseFeedPage(c)
+	if !ok {
 		return
 	}
-
-	// Merge and sort by created_at
-	feed := mergeFeed(news, highlights)
-
-	c.JSON(http.StatusOK, gin.H{
-		"feed":        feed,
-		"club_id":     user.FavClubID.Hex(),
-		"total_items": len(feed),
-	})
+	h.respondFeedPage(ctx, c, newsFilter, highlightFilter, limit, after, extra)
 }
 
 func (h *Handler) GetAllFeed(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Fetch all news
-	news, err := h.Repo.GetContent(ctx, bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching news"})
-		return
-	}
-
-	// Fetch all highlights
-	highlights, err := h.Repo.GetHighlights(ctx, bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching highlights"})
+	limit, after, ok := parseFeedPage(c)
+	if !ok {
 		return
 	}
 
-	// Merge and sort by created_at
-	feed := mergeFeed(news, highlights)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	c.JSON(http.StatusOK, gin.H{
-		"feed":        feed,
-		"total_items": len(feed),
-	})
+	h.respondFeedPage(ctx, c, bson.M{}, bson.M{}, limit, after, nil)
 }
 
 func (h *Handler) GetClubFeed(c *gin.Context) {
@@ -130,6 +139,11 @@ func (h *Handler) GetClubFeed(c *gin.Context) {
 		return
 	}
 
+	limit, after, ok := parseFeedPage(c)
+	if !ok {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -140,81 +154,328 @@ func (h *Handler) GetClubFeed(c *gin.Context) {
 		return
 	}
 
-	// Fetch news for the specific club
+	// News and highlights for the specific club
 	newsFilter := bson.M{"club_id": clubObjID}
-	news, err := h.Repo.GetContent(ctx, newsFilter)
+	highlightFilter := bson.M{"club_ids": clubObjID}
+
+	h.respondFeedPage(ctx, c, newsFilter, highlightFilter, limit, after, gin.H{
+		"club_id": clubID,
+	})
+}
+
+// GetLeagueFeed returns league-wide items plus items for every club in the
+// league.
+func (h *Handler) GetLeagueFeed(c *gin.Context) {
+	leagueID := c.Param("id")
+	leagueObjID, err := bson.ObjectIDFromHex(leagueID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching news"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league ID"})
 		return
 	}
 
-	// Fetch highlights for the specific club
-	highlightFilter := bson.M{"club_ids": clubObjID}
-	highlights, err := h.Repo.GetHighlights(ctx, highlightFilter)
+	limit, after, ok := parseFeedPage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindLeagueByID(ctx, leagueObjID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
+
+	leagueIDs := []bson.ObjectID{leagueObjID}
+	extra := gin.H{"league_id": leagueID}
+
+	// A past season's feed follows that season's clubs and dates
+	if name := c.Query("season"); name != "" {
+		season, err := h.Repo.FindSeason(ctx, leagueObjID, name)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching season"})
+			return
+		}
+
+		newsFilter, highlightFilter := targetingFilters(season.ClubIDs, leagueIDs)
+		if period := seasonPeriod(season); len(period) > 0 {
+			newsFilter["created_at"] = period
+			highlightFilter["created_at"] = period
+		}
+		extra["season"] = season.Name
+		h.respondFeedPage(ctx, c, newsFilter, highlightFilter, limit, after, extra)
+		return
+	}
+
+	clubIDs, err := h.Repo.GetClubIDsByLeagues(ctx, leagueIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching highlights"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching league clubs"})
 		return
 	}
 
-	// Merge and sort by created_at
-	feed := mergeFeed(news, highlights)
+	newsFilter, highlightFilter := targetingFilters(clubIDs, leagueIDs)
+	h.respondFeedPage(ctx, c, newsFilter, highlightFilter, limit, after, extra)
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"feed":        feed,
-		"club_id":     clubID,
-		"total_items": len(feed),
-	})
+// seasonPeriod matches the dates a season ran between, as far as they are
+// known
+func seasonPeriod(season *models.Season) bson.M {
+	period := bson.M{}
+	if season.StartDate != nil {
+		period["$gte"] = *season.StartDate
+	}
+	if season.EndDate != nil {
+		// The end date is the last day of the season
+		period["$lt"] = season.EndDate.AddDate(0, 0, 1)
+	}
+	return period
 }
 
-// mergeFeed combines news and highlights into a unified feed sorted by creation time
-func mergeFeed(news []models.Content, highlights []models.Highlight) []FeedItem {
-	var feed []FeedItem
-
-	// Add news items
-	for _, n := range news {
-		item := FeedItem{
-			ID:        n.ID.Hex(),
-			Type:      "news",
-			Title:     n.Title,
-			Body:      n.Body,
-			ImageURL:  n.ImageURL,
-			Category:  n.Category,
-			CreatedAt: n.CreatedAt,
-		}
-		if !n.ClubID.IsZero() {
-			item.ClubID = n.ClubID.Hex()
+// targetingFilters matches items targeted at any of the clubs or leagues
+func targetingFilters(clubIDs, leagueIDs []bson.ObjectID) (bson.M, bson.M) {
+	// $in rejects a nil slice, which would encode as null
+	if clubIDs == nil {
+		clubIDs = []bson.ObjectID{}
+	}
+	if leagueIDs == nil {
+		leagueIDs = []bson.ObjectID{}
+	}
+	leagueMatch := bson.M{"league_ids": bson.M{"$in": leagueIDs}}
+	newsFilter := bson.M{"$or": bson.A{bson.M{"club_id": bson.M{"$in": clubIDs}}, leagueMatch}}
+	highlightFilter := bson.M{"$or": bson.A{bson.M{"club_ids": bson.M{"$in": clubIDs}}, leagueMatch}}
+	return newsFilter, highlightFilter
+}
+
+// parseFeedPage reads the limit and cursor query parameters. It writes the
+// error response itself and reports whether they were valid.
+func parseFeedPage(c *gin.Context) (int64, *repository.FeedCursor, bool) {
+	limit, ok := parseFeedLimit(c)
+	if !ok {
+		return 0, nil, false
+	}
+
+	var after *repository.FeedCursor
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := decodeFeedCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return 0, nil, false
 		}
-		feed = append(feed, item)
+		after = cursor
 	}
+	return limit, after, true
+}
 
-	// Add highlight items
-	for _, h := range highlights {
-		clubIDs := make([]string, len(h.ClubIDs))
-		for i, cid := range h.ClubIDs {
-			clubIDs[i] = cid.Hex()
+func parseFeedLimit(c *gin.Context) (int64, bool) {
+	limit := int64(defaultFeedLimit)
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return 0, false
 		}
+		limit = min(n, maxFeedLimit)
+	}
+	return limit, true
+}
 
-		item := FeedItem{
-			ID:        h.ID.Hex(),
-			Type:      "highlight",
-			Title:     h.MatchTitle,
-			VideoURL:  h.YoutubeURL,
-			ClubIDs:   clubIDs,
-			CreatedAt: h.CreatedAt,
+// respondFeedPage fetches one page of the merged feed and writes it along
+// with the cursor for the next page, if there is one. Pinned items head the
+// first page and are left out of the chronological pages.
+func (h *Handler) respondFeedPage(ctx context.Context, c *gin.Context, newsFilter, highlightFilter bson.M, limit int64, after *repository.FeedCursor, extra gin.H) {
+	now := time.Now()
+
+	// Ask for one extra entry to learn whether another page exists
+	entries, err := h.Repo.GetFeedPage(ctx, pinFilter(newsFilter, false, now), pinFilter(highlightFilter, false, now), after, limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching feed"})
+		return
+	}
+
+	hasMore := int64(len(entries)) > limit
+	if hasMore {
+		entries = entries[:limit]
+	}
+
+	feed, err := toFeedItems(entries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding feed"})
+		return
+	}
+
+	response := gin.H{
+		"feed":        feed,
+		"total_items": len(feed),
+		"next_cursor": nil,
+	}
+	if hasMore {
+		last := feed[len(feed)-1]
+		response["next_cursor"] = encodeFeedCursor(last.CreatedAt, last.ID)
+	}
+
+	if after == nil {
+		pinnedEntries, err := h.Repo.GetFeedPage(ctx, pinFilter(newsFilter, true, now), pinFilter(highlightFilter, true, now), nil, maxPinnedItems)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching pinned items"})
+			return
 		}
-		feed = append(feed, item)
+		pinned, err := toFeedItems(pinnedEntries)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding feed"})
+			return
+		}
+		response["feed"] = append(pinned, feed...)
+		response["total_items"] = len(pinned) + len(feed)
+	}
+	for k, v := range extra {
+		response[k] = v
 	}
 
-	// Sort by CreatedAt DESC (newest first)
-	for i := 0; i < len(feed)-1; i++ {
-		for j := i + 1; j < len(feed); j++ {
-			if feed[i].CreatedAt.Before(feed[j].CreatedAt) {
-				feed[i], feed[j] = feed[j], feed[i]
+	c.JSON(http.StatusOK, response)
+}
+
+// pinFilter narrows a feed filter to items pinned at now, or to the rest
+func pinFilter(filter bson.M, pinned bool, now time.Time) bson.M {
+	f := bson.M{}
+	for k, v := range filter {
+		f[k] = v
+	}
+	if pinned {
+		f["pinned_until"] = bson.M{"$gt": now}
+	} else {
+		f["pinned_until"] = bson.M{"$not": bson.M{"$gt": now}}
+	}
+	return f
+}
+
+// encodeFeedCursor builds the opaque next_cursor token for a feed item
+func encodeFeedCursor(createdAt time.Time, id string) string {
+	raw := strconv.FormatInt(createdAt.UnixMilli(), 10) + ":" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(token string) (*repository.FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	millis, hexID, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, errors.New("malformed cursor")
+	}
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := bson.ObjectIDFromHex(hexID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.FeedCursor{CreatedAt: time.UnixMilli(ms), ID: id}, nil
+}
+
+// toFeedItems converts raw feed entries into the unified feed format
+func toFeedItems(entries []repository.FeedEntry) ([]FeedItem, error) {
+	feed := make([]FeedItem, 0, len(entries))
+	for _, entry := range entries {
+		switch entry.Type {
+		case "news":
+			var n models.Content
+			if err := bson.Unmarshal(entry.Raw, &n); err != nil {
+				return nil, err
 			}
+			feed = append(feed, newsFeedItem(n))
+		case "highlight":
+			var hl models.Highlight
+			if err := bson.Unmarshal(entry.Raw, &hl); err != nil {
+				return nil, err
+			}
+			feed = append(feed, highlightFeedItem(hl))
 		}
 	}
+	return feed, nil
+}
+
+func newsFeedItem(n models.Content) FeedItem {
+	item := FeedItem{
+		ID:        n.ID.Hex(),
+		Type:      "news",
+		Title:     n.Title,
+		Body:      n.Body,
+		ImageURL:  n.ImageURL,
+		Category:  n.Category,
+		CreatedAt: n.CreatedAt,
+	}
+	if !n.ClubID.IsZero() {
+		item.ClubID = n.ClubID.Hex()
+	}
+	if len(n.LeagueIDs) > 0 {
+		item.LeagueIDs = hexIDs(n.LeagueIDs)
+	}
+	if !n.MatchID.IsZero() {
+		item.MatchID = n.MatchID.Hex()
+	}
+	if len(n.PlayerIDs) > 0 {
+		item.PlayerIDs = hexIDs(n.PlayerIDs)
+	}
+	item.Extra = editorialExtra(n.Breaking, n.PinnedUntil)
+	return item
+}
+
+func highlightFeedItem(h models.Highlight) FeedItem {
+	item := FeedItem{
+		ID:        h.ID.Hex(),
+		Type:      "highlight",
+		Title:     h.MatchTitle,
+		VideoURL:  h.YoutubeURL,
+		ClubIDs:   hexIDs(h.ClubIDs),
+		CreatedAt: h.CreatedAt,
+	}
+	if len(h.LeagueIDs) > 0 {
+		item.LeagueIDs = hexIDs(h.LeagueIDs)
+	}
+	if !h.MatchID.IsZero() {
+		item.MatchID = h.MatchID.Hex()
+	}
+	if len(h.PlayerIDs) > 0 {
+		item.PlayerIDs = hexIDs(h.PlayerIDs)
+	}
+	item.Extra = editorialExtra(h.Breaking, h.PinnedUntil)
+	return item
+}
 
-	return feed
+// editorialExtra flags breaking and currently pinned items; nil when neither
+func editorialExtra(breaking bool, pinnedUntil *time.Time) map[string]interface{} {
+	extra := map[string]interface{}{}
+	if breaking {
+		extra["breaking"] = true
+	}
+	if isPinned(pinnedUntil, time.Now()) {
+		extra["pinned"] = true
+		extra["pinned_until"] = *pinnedUntil
+	}
+	if len(extra) == 0 {
+		return nil
+	}
+	return extra
+}
+
+func isPinned(pinnedUntil *time.Time, now time.Time) bool {
+	return pinnedUntil != nil && pinnedUntil.After(now)
+}
+
+func hexIDs(ids []bson.ObjectID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.Hex()
+	}
+	return out
 }
 
 func (h *Handler) GetLanguages(c *gin.Context) {
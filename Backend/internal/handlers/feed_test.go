@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestFeedCursor(t *testing.T) {
+	id := bson.NewObjectID()
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 123456789, time.UTC)
+
+	cursor, err := decodeFeedCursor(encodeFeedCursor(createdAt, id.Hex()))
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	// Cursors keep milliseconds, like the stored timestamps
+	if !cursor.CreatedAt.Equal(createdAt.Truncate(time.Millisecond)) || cursor.ID != id {
+		t.Errorf("round trip = %v %s; want %v %s", cursor.CreatedAt, cursor.ID.Hex(), createdAt, id.Hex())
+	}
+
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for name, token := range map[string]string{
+		"not base64":    "not a cursor!",
+		"padded base64": base64.URLEncoding.EncodeToString([]byte("1705312800000:" + id.Hex())),
+		"no separator":  raw("1705312800000"),
+		"bad time":      raw("yesterday:" + id.Hex()),
+		"bad ID":        raw("1705312800000:42"),
+	} {
+		if _, err := decodeFeedCursor(token); err == nil {
+			t.Errorf("%s: %q accepted", name, token)
+		}
+	}
+}
+
+func TestParseFeedPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cursor := encodeFeedCursor(time.UnixMilli(1705312800000), bson.NewObjectID().Hex())
+
+	tests := []struct {
+		query  string
+		ok     bool
+		limit  int64
+		cursor bool
+	}{
+		{"", true, defaultFeedLimit, false},
+		{"limit=5", true, 5, false},
+		{"limit=500", true, maxFeedLimit, false},
+		{"limit=5&cursor=" + cursor, true, 5, true},
+		{"limit=0", false, 0, false},
+		{"limit=ten", false, 0, false},
+		{"cursor=nope", false, 0, false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/feed/all?"+tt.query, nil)
+
+		limit, after, ok := parseFeedPage(c)
+		if ok != tt.ok || limit != tt.limit || (after != nil) != tt.cursor {
+			t.Errorf("%q: limit %d, cursor %v, ok %v; want %d, %v, %v", tt.query, limit, after != nil, ok, tt.limit, tt.cursor, tt.ok)
+		}
+		if !tt.ok && w.Code != http.StatusBadRequest {
+			t.Errorf("%q: status %d; want 400", tt.query, w.Code)
+		}
+	}
+}
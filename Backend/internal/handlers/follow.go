@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+)
+
+func (h *Handler) GetFollows(c *gin.Context) {
+	userID := currentUserID(c)
+	if userID.IsZero() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.Repo.FindUserByID(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	leagueIDs := user.FollowedLeagueIDs
+	if leagueIDs == nil {
+		leagueIDs = []bson.ObjectID{}
+	}
+
+	response := gin.H{
+		"primary_club_id": nil,
+		"club_ids":        followedClubIDs(user),
+		"league_ids":      leagueIDs,
+	}
+	if !user.FavClubID.IsZero() {
+		response["primary_club_id"] = user.FavClubID.Hex()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// FollowClub follows a club; ?primary=true also makes it the primary club.
+func (h *Handler) FollowClub(c *gin.Context) {
+	userID, clubID, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindClubByID(ctx, clubID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+
+	err := h.Repo.FollowClub(ctx, userID, clubID, c.Query("primary") == "true")
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow club"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Club followed successfully"})
+}
+
+func (h *Handler) UnfollowClub(c *gin.Context) {
+	userID, clubID, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := h.Repo.UnfollowClub(ctx, userID, clubID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow club"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Club unfollowed successfully"})
+}
+
+func (h *Handler) FollowLeague(c *gin.Context) {
+	userID, leagueID, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindLeagueByID(ctx, leagueID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
+
+	err := h.Repo.FollowLeague(ctx, userID, leagueID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow league"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "League followed successfully"})
+}
+
+func (h *Handler) UnfollowLeague(c *gin.Context) {
+	userID, leagueID, ok := followParams(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := h.Repo.UnfollowLeague(ctx, userID, leagueID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow league"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "League unfollowed successfully"})
+}
+
+// followParams reads the caller and the followed entity ID from the path.
+// It writes the error response itself and reports whether both were valid.
+func followParams(c *gin.Context) (bson.ObjectID, bson.ObjectID, bool) {
+	userID := currentUserID(c)
+	if userID.IsZero() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return bson.ObjectID{}, bson.ObjectID{}, false
+	}
+
+	targetID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return bson.ObjectID{}, bson.ObjectID{}, false
+	}
+	return userID, targetID, true
+}
+
+// followedClubIDs returns the clubs a user follows, including the primary club
+func followedClubIDs(user *models.User) []bson.ObjectID {
+	ids := []bson.ObjectID{}
+	if !user.FavClubID.IsZero() {
+		ids = append(ids, user.FavClubID)
+	}
+	for _, id := range user.FollowedClubIDs {
+		if id != user.FavClubID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+func TestFollowedClubIDs(t *testing.T) {
+	primary, other, third := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	tests := []struct {
+		name string
+		user models.User
+		want []bson.ObjectID
+	}{
+		{"follows nothing", models.User{}, []bson.ObjectID{}},
+		{"primary only", models.User{FavClubID: primary}, []bson.ObjectID{primary}},
+		{"primary comes first", models.User{FavClubID: primary, FollowedClubIDs: []bson.ObjectID{other, primary, third}}, []bson.ObjectID{primary, other, third}},
+		{"no primary", models.User{FollowedClubIDs: []bson.ObjectID{other, third}}, []bson.ObjectID{other, third}},
+	}
+	for _, tt := range tests {
+		if got := followedClubIDs(&tt.user); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: followedClubIDs = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFollowParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, clubID := bson.NewObjectID(), bson.NewObjectID()
+
+	tests := []struct {
+		name   string
+		user   string
+		param  string
+		status int
+	}{
+		{"valid", userID.Hex(), clubID.Hex(), http.StatusOK},
+		{"signed out", "", clubID.Hex(), http.StatusUnauthorized},
+		{"bad user ID", "42", clubID.Hex(), http.StatusUnauthorized},
+		{"bad club ID", userID.Hex(), "buna", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.user != "" {
+			c.Set("userID", tt.user)
+		}
+		c.Params = gin.Params{{Key: "id", Value: tt.param}}
+
+		gotUser, gotTarget, ok := followParams(c)
+		if ok != (tt.status == http.StatusOK) {
+			t.Errorf("%s: ok = %v; want status %d", tt.name, ok, tt.status)
+			continue
+		}
+		if ok && (gotUser != userID || gotTarget != clubID) {
+			t.Errorf("%s: got %s %s", tt.name, gotUser.Hex(), gotTarget.Hex())
+		}
+		if !ok && w.Code != tt.status {
+			t.Errorf("%s: status %d; want %d", tt.name, w.Code, tt.status)
+		}
+	}
+}
+
+// A user following nothing gets filters that match nothing rather than nulls
+func TestTargetingFilters(t *testing.T) {
+	club, league := bson.NewObjectID(), bson.NewObjectID()
+	tests := []struct {
+		name    string
+		clubs   []bson.ObjectID
+		leagues []bson.ObjectID
+	}{
+		{"nothing", nil, nil},
+		{"clubs and leagues", []bson.ObjectID{club}, []bson.ObjectID{league}},
+	}
+	for _, tt := range tests {
+		news, highlights := targetingFilters(tt.clubs, tt.leagues)
+		wantClubs, wantLeagues := tt.clubs, tt.leagues
+		if wantClubs == nil {
+			wantClubs = []bson.ObjectID{}
+		}
+		if wantLeagues == nil {
+			wantLeagues = []bson.ObjectID{}
+		}
+		leagueMatch := bson.M{"league_ids": bson.M{"$in": wantLeagues}}
+		if want := (bson.M{"$or": bson.A{bson.M{"club_id": bson.M{"$in": wantClubs}}, leagueMatch}}); !reflect.DeepEqual(news, want) {
+			t.Errorf("%s: news filter %v; want %v", tt.name, news, want)
+		}
+		if want := (bson.M{"$or": bson.A{bson.M{"club_ids": bson.M{"$in": wantClubs}}, leagueMatch}}); !reflect.DeepEqual(highlights, want) {
+			t.Errorf("%s: highlight filter %v; want %v", tt.name, highlights, want)
+		}
+	}
+}
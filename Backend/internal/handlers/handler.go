@@ -2,20 +2,23 @@ package handlers
 
 import (
 	"fanzone/internal/config"
+	"fanzone/internal/events"
 	"fanzone/internal/repository"
 	"fanzone/pkg/worker"
 )
 
 type Handler struct {
-	Repo   *repository.Repository
+	Repo   *repository.CachedRepository
 	Config *config.Config
 	Worker *worker.Worker
+	Events *events.Bus
 }
 
-func NewHandler(repo *repository.Repository, cfg *config.Config, worker *worker.Worker) *Handler {
+func NewHandler(repo *repository.CachedRepository, cfg *config.Config, worker *worker.Worker) *Handler {
 	return &Handler{
 		Repo:   repo,
 		Config: cfg,
 		Worker: worker,
+		Events: events.NewBus(),
 	}
 }
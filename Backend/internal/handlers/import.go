@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/importer"
+	"fanzone/internal/models"
+	"fanzone/pkg/worker"
+)
+
+// maxImportBytes caps the size of an uploaded import file
+const maxImportBytes = 5 << 20
+
+// ImportTimeout is how long the worker gives one import. A job still running
+// after that has been abandoned, e.g. by a server that stopped.
+const ImportTimeout = 10 * time.Minute
+
+// importFormat works out whether an upload is CSV or JSON from the format
+// parameter, the file name or the content type
+func importFormat(format, fileName, contentType string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return "csv"
+	case ".json":
+		return "json"
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		return "json"
+	case strings.Contains(contentType, "csv"):
+		return "csv"
+	}
+	return ""
+}
+
+// readImportFile returns the uploaded file, sent either as the multipart
+// field "file" or as the raw request body, with its name and format
+func readImportFile(c *gin.Context) ([]byte, string, string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	var data []byte
+	var fileName, contentType string
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", "", errors.New("a file is required")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", "", err
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(file); err != nil {
+			return nil, "", "", err
+		}
+		fileName, contentType = header.Filename, header.Header.Get("Content-Type")
+	} else {
+		var err error
+		if data, err = io.ReadAll(c.Request.Body); err != nil {
+			return nil, "", "", fmt.Errorf("the file is larger than %d MB", maxImportBytes>>20)
+		}
+		fileName, contentType = c.Query("file_name"), c.ContentType()
+	}
+	if len(data) == 0 {
+		return nil, "", "", errors.New("the file is empty")
+	}
+
+	format := importFormat(c.Query("format"), fileName, contentType)
+	if format != "csv" && format != "json" {
+		return nil, "", "", errors.New("format must be csv or json")
+	}
+	return data, fileName, format, nil
+}
+
+// AdminImport validates an import file and either previews the changes it
+// makes (dry_run=true) or queues it to be applied in the background
+func (h *Handler) AdminImport(c *gin.Context) {
+	kind := c.Query("kind")
+	if !slices.Contains(importer.Kinds, kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be one of: " + strings.Join(importer.Kinds, ", ")})
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+		return
+	}
+	data, fileName, format, err := readImportFile(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rows, err := importer.Parse(bytes.NewReader(data), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if dryRun {
+		plan, err := importer.Prepare(ctx, h.Repo, kind, rows)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate import"})
+			return
+		}
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	stored := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		stored[i] = row
+	}
+	job := models.ImportJob{
+		ID:        bson.NewObjectID(),
+		Kind:      kind,
+		FileName:  fileName,
+		Status:    models.ImportPending,
+		Rows:      stored,
+		RowCount:  len(rows),
+		Errors:    []models.ImportError{},
+		CreatedBy: currentUserID(c),
+		CreatedAt: time.Now(),
+	}
+	if err := h.Repo.CreateImportJob(ctx, job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue import"})
+		return
+	}
+	h.Worker.AddTask(worker.Task{Type: "RUN_IMPORT", Payload: job.ID})
+	h.logActivity(c, "Imported "+kind, "import", job.ID.Hex())
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// RunImportJob applies a queued import. It is run by the worker.
+func (h *Handler) RunImportJob(ctx context.Context, id bson.ObjectID) error {
+	job, err := h.Repo.ClaimImportJob(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil // already run
+	}
+	if err != nil {
+		return err
+	}
+
+	rows := make([]importer.Row, len(job.Rows))
+	for i, row := range job.Rows {
+		rows[i] = row
+	}
+	plan, err := importer.Prepare(ctx, h.Repo, job.Kind, rows)
+	if err != nil {
+		return h.Repo.FinishImportJob(ctx, id, models.ImportSummary{}, []models.ImportError{}, err)
+	}
+	summary := plan.Apply(ctx)
+	for _, key := range plan.Standings {
+		h.queueStandings(key.LeagueID, key.Season)
+	}
+	log.Printf("[Import] %s import %s: %+v", job.Kind, id.Hex(), summary)
+	return h.Repo.FinishImportJob(ctx, id, summary, plan.Errors, nil)
+}
+
+// RecoverImportJobs queues the jobs left pending and fails the ones left
+// running when a server stopped, so they can be retried. It is run at
+// startup; jobs another instance is running now are left alone.
+func (h *Handler) RecoverImportJobs(ctx context.Context) error {
+	failed, err := h.Repo.FailStaleImportJobs(ctx, time.Now().Add(-ImportTimeout))
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		log.Printf("[Import] Marked %d interrupted import(s) as failed", failed)
+	}
+	ids, err := h.Repo.PendingImportJobIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		h.Worker.AddTask(worker.Task{Type: "RUN_IMPORT", Payload: id})
+	}
+	return nil
+}
+
+// AdminRetryImport queues a failed import again. Imports match existing
+// records by name, so rows applied before the failure are not duplicated.
+func (h *Handler) AdminRetryImport(c *gin.Context) {
+	current, ok := h.findImportJob(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.Repo.RetryImportJob(ctx, current.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only failed imports can be retried", "status": current.Status})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry import"})
+		return
+	}
+	h.Worker.AddTask(worker.Task{Type: "RUN_IMPORT", Payload: job.ID})
+	h.logActivity(c, "Retried Import", "import", job.ID.Hex())
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// AdminGetImports lists the latest import jobs
+func (h *Handler) AdminGetImports(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	jobs, err := h.Repo.GetImportJobs(ctx, 50)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch imports"})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *Handler) findImportJob(c *gin.Context) (*models.ImportJob, bool) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	job, err := h.Repo.FindImportJobByID(ctx, objID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch import"})
+		return nil, false
+	}
+	return job, true
+}
+
+// AdminGetImport returns an import job with its summary and errors
+func (h *Handler) AdminGetImport(c *gin.Context) {
+	job, ok := h.findImportJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// AdminGetImportErrors downloads the errors of an import job as CSV
+func (h *Handler) AdminGetImportErrors(c *gin.Context) {
+	job, ok := h.findImportJob(c)
+	if !ok {
+		return
+	}
+	var report bytes.Buffer
+	if err := importer.WriteErrorReport(&report, job.Errors); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write error report"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%s-errors.csv"`, job.ID.Hex()))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", report.Bytes())
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/importer"
+	"fanzone/internal/models"
+)
+
+// resultStore keeps what a results import reads and writes. The methods an
+// import of results does not use are left to the nil Store.
+type resultStore struct {
+	importer.Store
+	leagues []models.League
+	clubs   []models.Club
+	matches []models.Match
+	events  []models.MatchEvent
+}
+
+func (s *resultStore) GetLeagues(ctx context.Context) ([]models.League, error) { return s.leagues, nil }
+func (s *resultStore) GetClubs(ctx context.Context) ([]models.Club, error)     { return s.clubs, nil }
+
+func (s *resultStore) FindSeason(ctx context.Context, leagueID bson.ObjectID, name string) (*models.Season, error) {
+	return nil, mongo.ErrNoDocuments
+}
+
+func (s *resultStore) GetMatches(ctx context.Context, filter bson.M, ascending bool, limit int64) ([]models.Match, error) {
+	return s.matches, nil
+}
+
+func (s *resultStore) CreateMatch(ctx context.Context, match models.Match) error {
+	s.matches = append(s.matches, match)
+	return nil
+}
+
+func (s *resultStore) UpdateMatch(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	for i := range s.matches {
+		if s.matches[i].ID != id {
+			continue
+		}
+		if score, ok := update["score"].(*models.Score); ok {
+			s.matches[i].Score = score
+		}
+		if status, ok := update["status"].(string); ok {
+			s.matches[i].Status = status
+		}
+		if period, ok := update["period"].(string); ok {
+			s.matches[i].Period = period
+		}
+		s.matches[i].Version++
+		return s.matches[i].Version, nil
+	}
+	return 0, mongo.ErrNoDocuments
+}
+
+func (s *resultStore) CreateMatchEvent(ctx context.Context, event models.MatchEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+// An imported result must survive events posted to or removed from the
+// match afterwards
+func TestImportedResultKeepsThroughTimelineChanges(t *testing.T) {
+	league := models.League{ID: bson.NewObjectID(), Name: "Ethiopian Premier League"}
+	home := models.Club{ID: bson.NewObjectID(), Name: "Saint George", LeagueID: league.ID}
+	away := models.Club{ID: bson.NewObjectID(), Name: "Fasil Kenema", LeagueID: league.ID}
+
+	for _, existing := range []bool{false, true} {
+		store := &resultStore{leagues: []models.League{league}, clubs: []models.Club{home, away}}
+		if existing {
+			store.matches = []models.Match{{
+				ID: bson.NewObjectID(), LeagueID: league.ID, Season: "2024/25",
+				HomeClubID: home.ID, AwayClubID: away.ID, Status: models.MatchScheduled, Version: 1,
+			}}
+		}
+		rows := []importer.Row{{
+			"league": league.Name, "season": "2024/25", "home": home.Name, "away": away.Name,
+			"home_score": "2", "away_score": "1", "kickoff_at": "2024-11-02 16:00",
+		}}
+
+		plan, err := importer.Prepare(context.Background(), store, importer.KindResults, rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary := plan.Apply(context.Background()); summary.Failed > 0 || len(plan.Errors) > 0 {
+			t.Fatalf("existing=%v: import failed: %+v %+v", existing, summary, plan.Errors)
+		}
+		if len(store.matches) != 1 || len(store.events) != 1 || store.events[0].Type != models.EventFinalScore {
+			t.Fatalf("existing=%v: got %d matches and events %+v; want one match with a final_score event", existing, len(store.matches), store.events)
+		}
+		match := store.matches[0]
+		want := models.Score{Home: 2, Away: 1}
+		if match.Status != models.MatchFinished || match.Score == nil || *match.Score != want {
+			t.Fatalf("existing=%v: imported match = %s %v; want finished %v", existing, match.Status, match.Score, want)
+		}
+
+		// A late goal is posted, then taken back
+		goal := models.MatchEvent{ID: bson.NewObjectID(), MatchID: match.ID, Type: models.EventGoal, Minute: 30, ClubID: away.ID, CreatedAt: time.Now()}
+		changes := []struct {
+			name     string
+			timeline []models.MatchEvent
+			removed  bool
+		}{
+			{"after adding an event", []models.MatchEvent{goal, store.events[0]}, false},
+			{"after removing an event", store.events, true},
+		}
+		for _, change := range changes {
+			got := withLiveState(match, change.timeline, change.removed)
+			if got.Status != models.MatchFinished || got.Score == nil || *got.Score != want {
+				t.Errorf("existing=%v %s: match = %s %v; want finished %v", existing, change.name, got.Status, got.Score, want)
+			}
+		}
+	}
+}
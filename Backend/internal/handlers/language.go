@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"fanzone/internal/models"
+)
+
+// supportedLanguages are the languages content is translated into, the first
+// being the default
+var supportedLanguages = []string{"en", "am", "om"}
+
+func isSupportedLanguage(lang string) bool {
+	for _, supported := range supportedLanguages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateLanguage picks the response language from the lang query
+// parameter, then the Accept-Language header, then the default. It writes the
+// error response itself for an unsupported lang and reports whether it
+// succeeded.
+func negotiateLanguage(c *gin.Context) (string, bool) {
+	c.Header("Vary", "Accept-Language")
+
+	if lang := c.Query("lang"); lang != "" {
+		if !isSupportedLanguage(lang) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lang must be one of: en, am, om"})
+			return "", false
+		}
+		c.Header("Content-Language", lang)
+		return lang, true
+	}
+
+	lang := acceptedLanguage(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", lang)
+	return lang, true
+}
+
+// acceptedLanguage returns the supported language an Accept-Language header
+// ranks highest, e.g. "am" for "am-ET,am;q=0.9,en;q=0.8"
+func acceptedLanguage(header string) string {
+	best, bestQ := supportedLanguages[0], 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !isSupportedLanguage(primary) {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
+
+func translation(text models.MultiLangString, lang string) string {
+	switch lang {
+	case "am":
+		return text.AM
+	case "om":
+		return text.OM
+	}
+	return text.EN
+}
+
+// localize returns the translation of text into lang, falling back to
+// fallback and then to any translation, so something is always shown
+func localize(text *models.MultiLangString, lang, fallback string) string {
+	if text == nil {
+		return fallback
+	}
+	if t := strings.TrimSpace(translation(*text, lang)); t != "" {
+		return t
+	}
+	if fallback != "" {
+		return fallback
+	}
+	for _, l := range supportedLanguages {
+		if t := strings.TrimSpace(translation(*text, l)); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// translations lists a canonical value and its non-empty translations
+func translations(canonical string, texts ...*models.MultiLangString) []string {
+	out := []string{canonical}
+	for _, text := range texts {
+		if text == nil {
+			continue
+		}
+		for _, lang := range supportedLanguages {
+			if t := strings.TrimSpace(translation(*text, lang)); t != "" {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
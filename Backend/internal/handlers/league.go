@@ -7,18 +7,55 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
 )
 
+// LeagueView is a league with its names resolved to the negotiated language
+type LeagueView struct {
+	models.League
+	Language         string `json:"language"`
+	DisplayName      string `json:"display_name"`
+	DisplayShortName string `json:"display_short_name"`
+	DisplayCountry   string `json:"display_country"`
+}
+
+func localizeLeague(league models.League, lang string) LeagueView {
+	name := localize(league.Names, lang, league.Name)
+	return LeagueView{
+		League:           league,
+		Language:         lang,
+		DisplayName:      name,
+		DisplayShortName: localize(league.ShortNames, lang, name),
+		DisplayCountry:   localize(league.CountryNames, lang, league.Country),
+	}
+}
+
+// leagueNames lists every name a league can be found by
+func leagueNames(league models.League) []string {
+	return append(translations(league.Name, league.Names, league.ShortNames), league.Aliases...)
+}
+
 func (h *Handler) GetLeagues(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
 	leagues, err := h.Repo.GetLeagues(ctx)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching leagues"})
 		return
 	}
-	c.JSON(http.StatusOK, leagues)
+
+	views := make([]LeagueView, len(leagues))
+	for i, league := range leagues {
+		views[i] = localizeLeague(league, lang)
+	}
+	c.JSON(http.StatusOK, views)
 }
 
 func (h *Handler) GetLeagueByID(c *gin.Context) {
@@ -28,6 +65,10 @@ func (h *Handler) GetLeagueByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -37,5 +78,7 @@ func (h *Handler) GetLeagueByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
 		return
 	}
-	c.JSON(http.StatusOK, league)
+	setLocalizedETag(c, league.Version, lang)
+	setLastModified(c, league.UpdatedAt)
+	c.JSON(http.StatusOK, localizeLeague(*league, lang))
 }
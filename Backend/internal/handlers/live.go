@@ -0,0 +1,600 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/events"
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+const liveTopic = "live"
+
+// LiveScore is the running state of a match as pushed to live streams
+type LiveScore struct {
+	MatchID string        `json:"match_id"`
+	Status  string        `json:"status"`
+	Period  string        `json:"period,omitempty"`
+	Score   *models.Score `json:"score,omitempty"`
+}
+
+func liveScore(match models.Match) LiveScore {
+	return LiveScore{MatchID: match.ID.Hex(), Status: match.Status, Period: match.Period, Score: match.Score}
+}
+
+// matchChange is an event posted to or removed from a timeline, along with
+// the match state it results in
+type matchChange struct {
+	Event   models.MatchEvent
+	Removed bool
+	Match   models.Match
+}
+
+// matchChangeData is what streams send for a matchChange
+type matchChangeData struct {
+	Event models.MatchEvent `json:"event"`
+	Live  LiveScore         `json:"live"`
+}
+
+// withLiveState returns match with the score, period and status its timeline
+// adds up to. Goals count for ClubID and own goals for the other club, until
+// a final_score event sets the result. Any event makes a scheduled match
+// live; full time finishes it. A match that never had events keeps the state
+// it was given by an admin or an import; removed says an event was just taken
+// back, so an empty timeline then clears what the events had set.
+func withLiveState(match models.Match, timeline []models.MatchEvent, removed bool) models.Match {
+	if len(timeline) == 0 {
+		if removed {
+			match.Score = nil
+			match.Period = ""
+			if match.Status == models.MatchLive || match.Status == models.MatchFinished {
+				match.Status = models.MatchScheduled
+			}
+		}
+		return match
+	}
+
+	score := models.Score{}
+	period := ""
+	var final *models.Score
+	for _, event := range timeline {
+		home := event.ClubID == match.HomeClubID
+		switch event.Type {
+		case models.EventGoal, models.EventPenaltyGoal:
+			if home {
+				score.Home++
+			} else {
+				score.Away++
+			}
+		case models.EventOwnGoal:
+			if home {
+				score.Away++
+			} else {
+				score.Home++
+			}
+		case models.EventPeriod:
+			period = event.Period
+		case models.EventFinalScore:
+			final = event.Score
+		}
+	}
+	if final != nil {
+		score = *final
+		period = models.PeriodFullTime
+	}
+
+	match.Score = &score
+	match.Period = period
+	match.Status = models.MatchLive
+	if period == models.PeriodFullTime {
+		match.Status = models.MatchFinished
+	}
+	return match
+}
+
+// refreshLiveState recomputes a match's state from its timeline and stores it
+// so match lists show the running score. removed is true after an event is
+// taken off the timeline.
+func (h *Handler) refreshLiveState(ctx context.Context, match models.Match, removed bool) (models.Match, error) {
+	timeline, err := h.Repo.GetMatchEvents(ctx, match.ID)
+	if err != nil {
+		return match, err
+	}
+	updated := withLiveState(match, timeline, removed)
+
+	fields := bson.M{"status": updated.Status, "period": optionalValue(updated.Period), "score": nil}
+	if updated.Score != nil {
+		fields["score"] = *updated.Score
+	}
+	version, err := h.Repo.UpdateMatch(ctx, match.ID, fields, repository.AnyVersion)
+	if err != nil {
+		return match, err
+	}
+	updated.Version = version
+	if match.Status == models.MatchFinished || updated.Status == models.MatchFinished {
+		h.queueStandings(match.LeagueID, match.Season)
+	}
+	return updated, nil
+}
+
+func matchChangeID(change matchChange) string {
+	action := "added"
+	if change.Removed {
+		action = "removed"
+	}
+	return "match_event:" + change.Event.ID.Hex() + ":" + action
+}
+
+func (h *Handler) publishMatchChange(change matchChange) {
+	h.Events.Publish(events.Event{ID: matchChangeID(change), Topic: liveTopic, Payload: change})
+}
+
+// RelayMatchEvents publishes timeline changes made on other server instances
+// until ctx is done. Like RelayFeedInserts it needs a replica set.
+func (h *Handler) RelayMatchEvents(ctx context.Context) {
+	backoff := time.Second
+	for {
+		err := h.Repo.WatchMatchEvents(ctx, func(event models.MatchEvent) {
+			lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			match, err := h.Repo.FindMatchByID(lookupCtx, event.MatchID)
+			if err != nil {
+				// Events of a deleted match are removed with it
+				return
+			}
+			timeline, err := h.Repo.GetMatchEvents(lookupCtx, event.MatchID)
+			if err != nil {
+				log.Printf("[Live] Failed to load timeline of match %s: %v", event.MatchID.Hex(), err)
+				return
+			}
+			removed := event.DeletedAt != nil
+			h.publishMatchChange(matchChange{
+				Event:   event,
+				Removed: removed,
+				Match:   withLiveState(*match, timeline, removed),
+			})
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[Live] Change stream stopped, retrying in %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 5*time.Minute)
+	}
+}
+
+// GetMatchEvents returns a match timeline with the running score
+func (h *Handler) GetMatchEvents(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	timeline, err := h.Repo.GetMatchEvents(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching match events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"live":   liveScore(withLiveState(*match, timeline, false)),
+		"events": timeline,
+	})
+}
+
+// GetLiveMatches lists the matches in play, optionally for one club or league
+func (h *Handler) GetLiveMatches(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+	filter, ok := h.liveFilter(ctx, c)
+	if !ok {
+		return
+	}
+
+	matches, err := h.Repo.GetMatches(ctx, filter.query(), true, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+		return
+	}
+	views, err := h.matchViews(ctx, matches, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matches": views, "total": len(views)})
+}
+
+// liveFilter narrows live matches to a club and/or a league
+type liveFilter struct {
+	ClubID   bson.ObjectID
+	LeagueID bson.ObjectID
+}
+
+func (h *Handler) liveFilter(ctx context.Context, c *gin.Context) (liveFilter, bool) {
+	var filter liveFilter
+	errs := ValidationErrors{}
+	if raw := c.Query("club_id"); raw != "" {
+		filter.ClubID = h.resolveClubID(ctx, errs, "club_id", raw)
+	}
+	if raw := c.Query("league_id"); raw != "" {
+		filter.LeagueID = h.resolveLeagueID(ctx, errs, "league_id", raw)
+	}
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return filter, false
+	}
+	return filter, true
+}
+
+func (f liveFilter) query() bson.M {
+	query := bson.M{"status": models.MatchLive}
+	if !f.ClubID.IsZero() {
+		query["$or"] = bson.A{bson.M{"home_club_id": f.ClubID}, bson.M{"away_club_id": f.ClubID}}
+	}
+	if !f.LeagueID.IsZero() {
+		query["league_id"] = f.LeagueID
+	}
+	return query
+}
+
+func (f liveFilter) matches(match models.Match) bool {
+	if !f.ClubID.IsZero() && match.HomeClubID != f.ClubID && match.AwayClubID != f.ClubID {
+		return false
+	}
+	return f.LeagueID.IsZero() || match.LeagueID == f.LeagueID
+}
+
+// StreamMatch pushes a match's timeline as Server-Sent Events. It starts
+// with the whole timeline, so a reconnecting client needs no Last-Event-ID.
+func (h *Handler) StreamMatch(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	// Subscribe before loading the timeline so nothing posted meanwhile is lost
+	sub := h.Events.Subscribe()
+	defer sub.Close()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	match, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		cancel()
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	timeline, err := h.Repo.GetMatchEvents(ctx, objID)
+	cancel()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching match events"})
+		return
+	}
+
+	startStream(c)
+	c.Render(-1, sse.Event{Event: "timeline", Data: gin.H{
+		"live":   liveScore(withLiveState(*match, timeline, false)),
+		"events": timeline,
+	}})
+	c.Writer.Flush()
+
+	h.pumpMatchChanges(c, sub, func(change matchChange) bool {
+		return change.Match.ID == objID
+	})
+}
+
+// StreamLiveMatches pushes every timeline change of matches in play, for a
+// live scores page. It starts with the current scores.
+func (h *Handler) StreamLiveMatches(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	filter, ok := h.liveFilter(ctx, c)
+	if !ok {
+		return
+	}
+
+	sub := h.Events.Subscribe()
+	defer sub.Close()
+
+	matches, err := h.Repo.GetMatches(ctx, filter.query(), true, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+		return
+	}
+	scores := make([]LiveScore, len(matches))
+	for i, match := range matches {
+		scores[i] = liveScore(match)
+	}
+
+	startStream(c)
+	c.Render(-1, sse.Event{Event: "scores", Data: gin.H{"matches": scores}})
+	c.Writer.Flush()
+
+	h.pumpMatchChanges(c, sub, func(change matchChange) bool {
+		return filter.matches(change.Match)
+	})
+}
+
+// startStream sends the headers of a Server-Sent Events response
+func startStream(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+}
+
+// pumpMatchChanges writes the changes accepted by want until the client goes
+// away, with heartbeats in between
+func (h *Handler) pumpMatchChanges(c *gin.Context, sub *events.Subscription, want func(matchChange) bool) {
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			change, isChange := event.Payload.(matchChange)
+			if event.Topic != liveTopic || !isChange || !want(change) {
+				continue
+			}
+			name := "match_event"
+			if change.Removed {
+				name = "match_event_removed"
+			}
+			c.Render(-1, sse.Event{Id: event.ID, Event: name, Data: matchChangeData{
+				Event: change.Event,
+				Live:  liveScore(change.Match),
+			}})
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// --- Admin ---
+
+func (h *Handler) AdminAddMatchEvent(c *gin.Context) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input matchEventInput
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	if match.Status == models.MatchPostponed || match.Status == models.MatchCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Match is " + match.Status})
+		return
+	}
+
+	event, errs := input.toEvent(match)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	event.CreatedBy = currentUserID(c)
+
+	if err := h.Repo.CreateMatchEvent(ctx, event); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add match event"})
+		return
+	}
+	updated, err := h.refreshLiveState(ctx, *match, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update score"})
+		return
+	}
+
+	h.publishMatchChange(matchChange{Event: event, Match: updated})
+	h.logActivity(c, "Added Match Event", "match", id)
+	c.JSON(http.StatusCreated, matchChangeData{Event: event, Live: liveScore(updated)})
+}
+
+// AdminDeleteMatchEvent takes an event off the timeline, e.g. a goal ruled
+// out, and updates the score
+func (h *Handler) AdminDeleteMatchEvent(c *gin.Context) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	eventID, err := bson.ObjectIDFromHex(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	event, err := h.Repo.DeleteMatchEvent(ctx, objID, eventID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match event not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+	updated, err := h.refreshLiveState(ctx, *match, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update score"})
+		return
+	}
+
+	h.publishMatchChange(matchChange{Event: *event, Removed: true, Match: updated})
+	h.logActivity(c, "Deleted Match Event", "match", id)
+	c.JSON(http.StatusOK, matchChangeData{Event: *event, Live: liveScore(updated)})
+}
+
+const (
+	maxMatchMinute   = 150
+	maxStoppage      = 30
+	maxPlayerNameLen = 100
+	maxEventNoteLen  = 500
+)
+
+// matchEventInput is the body for posting an event to a match timeline
+type matchEventInput struct {
+	Type      string        `json:"type"`
+	Minute    int           `json:"minute"`
+	Stoppage  int           `json:"stoppage"`
+	ClubID    string        `json:"club_id"`
+	Player    string        `json:"player"`
+	PlayerOut string        `json:"player_out"`
+	Period    string        `json:"period"`
+	Score     *models.Score `json:"score"`
+	Note      string        `json:"note"`
+}
+
+// teamEvents are the event types that involve a player of one of the clubs
+var teamEvents = []string{
+	models.EventGoal, models.EventOwnGoal, models.EventPenaltyGoal,
+	models.EventYellowCard, models.EventRedCard, models.EventSubstitution,
+}
+
+func (in matchEventInput) toEvent(match *models.Match) (models.MatchEvent, ValidationErrors) {
+	errs := ValidationErrors{}
+
+	if !slices.Contains(models.MatchEventTypes, in.Type) {
+		errs.Add("type", "must be one of: "+strings.Join(models.MatchEventTypes, ", "))
+	}
+	if in.Minute < 0 || in.Minute > maxMatchMinute {
+		errs.Add("minute", "must be between 0 and 150")
+	}
+	if in.Stoppage < 0 || in.Stoppage > maxStoppage {
+		errs.Add("stoppage", "must be between 0 and 30")
+	}
+
+	player := strings.TrimSpace(in.Player)
+	playerOut := strings.TrimSpace(in.PlayerOut)
+	note := strings.TrimSpace(in.Note)
+	if utf8.RuneCountInString(player) > maxPlayerNameLen {
+		errs.Add("player", "must be at most 100 characters")
+	}
+	if utf8.RuneCountInString(playerOut) > maxPlayerNameLen {
+		errs.Add("player_out", "must be at most 100 characters")
+	}
+	if utf8.RuneCountInString(note) > maxEventNoteLen {
+		errs.Add("note", "must be at most 500 characters")
+	}
+
+	var clubID bson.ObjectID
+	if slices.Contains(teamEvents, in.Type) {
+		id, err := bson.ObjectIDFromHex(in.ClubID)
+		switch {
+		case in.ClubID == "":
+			errs.Add("club_id", "is required")
+		case err != nil || (id != match.HomeClubID && id != match.AwayClubID):
+			errs.Add("club_id", "must be one of the clubs playing")
+		}
+		clubID = id
+	} else if in.ClubID != "" {
+		errs.Add("club_id", "only applies to player events")
+	}
+
+	if in.Type == models.EventSubstitution {
+		if player == "" {
+			errs.Add("player", "is required")
+		}
+		if playerOut == "" {
+			errs.Add("player_out", "is required")
+		}
+	} else if playerOut != "" {
+		errs.Add("player_out", "only applies to substitutions")
+	}
+
+	if in.Type == models.EventPeriod {
+		if !slices.Contains(models.MatchPeriods, in.Period) {
+			errs.Add("period", "must be one of: "+strings.Join(models.MatchPeriods, ", "))
+		}
+	} else if in.Period != "" {
+		errs.Add("period", "only applies to period events")
+	}
+
+	if in.Type == models.EventFinalScore {
+		if in.Score == nil {
+			errs.Add("score", "is required")
+		} else if in.Score.Home < 0 || in.Score.Away < 0 {
+			errs.Add("score", "must not be negative")
+		}
+	} else if in.Score != nil {
+		errs.Add("score", "only applies to final_score events")
+	}
+
+	now := time.Now()
+	return models.MatchEvent{
+		ID:        bson.NewObjectID(),
+		MatchID:   match.ID,
+		Type:      in.Type,
+		Minute:    in.Minute,
+		Stoppage:  in.Stoppage,
+		ClubID:    clubID,
+		Player:    player,
+		PlayerOut: playerOut,
+		Period:    in.Period,
+		Score:     in.Score,
+		Note:      note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, errs
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+func TestWithLiveState(t *testing.T) {
+	home, away := bson.NewObjectID(), bson.NewObjectID()
+	goal := func(club bson.ObjectID) models.MatchEvent {
+		return models.MatchEvent{Type: models.EventGoal, ClubID: club}
+	}
+	period := func(p string) models.MatchEvent {
+		return models.MatchEvent{Type: models.EventPeriod, Period: p}
+	}
+
+	tests := []struct {
+		name     string
+		stored   models.Match
+		timeline []models.MatchEvent
+		removed  bool
+		status   string
+		period   string
+		score    *models.Score
+	}{
+		{
+			name:   "no events keeps a scheduled match",
+			stored: models.Match{Status: models.MatchScheduled},
+			status: models.MatchScheduled,
+		},
+		{
+			name:   "no events keeps a result set by an admin",
+			stored: models.Match{Status: models.MatchFinished, Period: models.PeriodFullTime, Score: &models.Score{Home: 2, Away: 1}},
+			status: models.MatchFinished,
+			period: models.PeriodFullTime,
+			score:  &models.Score{Home: 2, Away: 1},
+		},
+		{
+			name:    "removing the only goal clears the score",
+			stored:  models.Match{Status: models.MatchLive, Period: models.PeriodFirstHalf, Score: &models.Score{Home: 1, Away: 0}},
+			removed: true,
+			status:  models.MatchScheduled,
+		},
+		{
+			name:    "removing the last event keeps a postponement",
+			stored:  models.Match{Status: models.MatchPostponed},
+			removed: true,
+			status:  models.MatchPostponed,
+		},
+		{
+			name:     "removing one of several goals recounts",
+			stored:   models.Match{Status: models.MatchLive, Score: &models.Score{Home: 2, Away: 0}},
+			timeline: []models.MatchEvent{goal(home)},
+			removed:  true,
+			status:   models.MatchLive,
+			score:    &models.Score{Home: 1, Away: 0},
+		},
+		{
+			name:     "goals and own goals",
+			stored:   models.Match{Status: models.MatchScheduled},
+			timeline: []models.MatchEvent{period(models.PeriodFirstHalf), goal(home), {Type: models.EventOwnGoal, ClubID: home}, goal(away)},
+			status:   models.MatchLive,
+			period:   models.PeriodFirstHalf,
+			score:    &models.Score{Home: 1, Away: 2},
+		},
+		{
+			name:     "full time finishes the match",
+			stored:   models.Match{Status: models.MatchLive},
+			timeline: []models.MatchEvent{goal(home), period(models.PeriodFullTime)},
+			status:   models.MatchFinished,
+			period:   models.PeriodFullTime,
+			score:    &models.Score{Home: 1, Away: 0},
+		},
+		{
+			name:     "final score overrides the goals",
+			stored:   models.Match{Status: models.MatchLive},
+			timeline: []models.MatchEvent{goal(home), {Type: models.EventFinalScore, Score: &models.Score{Home: 3, Away: 3}}, goal(away)},
+			status:   models.MatchFinished,
+			period:   models.PeriodFullTime,
+			score:    &models.Score{Home: 3, Away: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stored.HomeClubID, tt.stored.AwayClubID = home, away
+			got := withLiveState(tt.stored, tt.timeline, tt.removed)
+			if got.Status != tt.status || got.Period != tt.period {
+				t.Errorf("status, period = %q, %q; want %q, %q", got.Status, got.Period, tt.status, tt.period)
+			}
+			if (got.Score == nil) != (tt.score == nil) || (got.Score != nil && *got.Score != *tt.score) {
+				t.Errorf("score = %v; want %v", got.Score, tt.score)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// matchDayZone is the zone match dates are given in. Ethiopia has no
+// daylight saving, so a fixed offset is exact.
+var matchDayZone = time.FixedZone("EAT", 3*60*60)
+
+// TeamSummary is what match lists show of a club or league
+type TeamSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	ShortName   string `json:"short_name"`
+	LogoURL     string `json:"logo_url"`
+}
+
+// MatchView is a match with its clubs and league resolved for display
+type MatchView struct {
+	models.Match
+	HomeClub *TeamSummary `json:"home_club"`
+	AwayClub *TeamSummary `json:"away_club"`
+	League   *TeamSummary `json:"league"`
+}
+
+func clubSummary(club models.Club, lang string) *TeamSummary {
+	view := localizeClub(club, lang)
+	return &TeamSummary{
+		ID:          club.ID.Hex(),
+		Name:        club.Name,
+		DisplayName: view.DisplayName,
+		ShortName:   view.DisplayShortName,
+		LogoURL:     club.LogoURL,
+	}
+}
+
+func leagueSummary(league models.League, lang string) *TeamSummary {
+	view := localizeLeague(league, lang)
+	return &TeamSummary{
+		ID:          league.ID.Hex(),
+		Name:        league.Name,
+		DisplayName: view.DisplayName,
+		ShortName:   view.DisplayShortName,
+		LogoURL:     league.LogoURL,
+	}
+}
+
+// matchViews resolves clubs and leagues from the cached lists. Deleted ones
+// are left nil rather than failing the whole list.
+func (h *Handler) matchViews(ctx context.Context, matches []models.Match, lang string) ([]MatchView, error) {
+	clubs, err := h.Repo.GetClubs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	leagues, err := h.Repo.GetLeagues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clubByID := make(map[bson.ObjectID]models.Club, len(clubs))
+	for _, club := range clubs {
+		clubByID[club.ID] = club
+	}
+	leagueByID := make(map[bson.ObjectID]models.League, len(leagues))
+	for _, league := range leagues {
+		leagueByID[league.ID] = league
+	}
+
+	views := make([]MatchView, len(matches))
+	for i, match := range matches {
+		views[i] = MatchView{Match: match}
+		if club, ok := clubByID[match.HomeClubID]; ok {
+			views[i].HomeClub = clubSummary(club, lang)
+		}
+		if club, ok := clubByID[match.AwayClubID]; ok {
+			views[i].AwayClub = clubSummary(club, lang)
+		}
+		if league, ok := leagueByID[match.LeagueID]; ok {
+			views[i].League = leagueSummary(league, lang)
+		}
+	}
+	return views, nil
+}
+
+// GetMatches lists upcoming or recent matches, optionally for one club,
+// league, season or match day. A league's matches default to its current
+// season.
+func (h *Handler) GetMatches(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+	limit, ok := parseFeedLimit(c)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{}
+	errs := ValidationErrors{}
+	if raw := c.Query("club_id"); raw != "" {
+		clubID := h.resolveClubID(ctx, errs, "club_id", raw)
+		filter["$or"] = bson.A{bson.M{"home_club_id": clubID}, bson.M{"away_club_id": clubID}}
+	}
+	if raw := c.Query("league_id"); raw != "" {
+		filter["league_id"] = h.resolveLeagueID(ctx, errs, "league_id", raw)
+	}
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	// A league's fixtures default to its current season; season=all lists
+	// every season
+	season := c.Query("season")
+	if leagueID, ok := filter["league_id"].(bson.ObjectID); ok && season == "" && c.Query("date") == "" {
+		current, err := h.currentSeasonName(ctx, leagueID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seasons"})
+			return
+		}
+		season = current
+	}
+	if season != "" && season != "all" {
+		filter["season"] = season
+	}
+
+	when, ascending, err := scheduleFilter(filter, c.DefaultQuery("when", "upcoming"), c.Query("date"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	matches, err := h.Repo.GetMatches(ctx, filter, ascending, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+		return
+	}
+	views, err := h.matchViews(ctx, matches, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": views,
+		"when":    when,
+		"total":   len(views),
+	})
+}
+
+// scheduleFilter narrows filter to one match day, or to the matches upcoming
+// or recent at now. It returns the window listed and whether matches are
+// listed oldest first.
+func scheduleFilter(filter bson.M, when, date string, now time.Time) (string, bool, error) {
+	if date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, matchDayZone)
+		if err != nil {
+			return "", false, errors.New("date must be YYYY-MM-DD")
+		}
+		filter["kickoff_at"] = bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)}
+		return "date", true, nil
+	}
+
+	switch when {
+	case "upcoming":
+		// Live matches stay upcoming until they finish
+		upcoming := bson.A{bson.M{"kickoff_at": bson.M{"$gte": now}}, bson.M{"status": models.MatchLive}}
+		addOr(filter, upcoming)
+		return when, true, nil
+	case "recent":
+		filter["kickoff_at"] = bson.M{"$lt": now}
+		filter["status"] = bson.M{"$ne": models.MatchLive}
+		return when, false, nil
+	default:
+		return "", false, errors.New("when must be upcoming or recent")
+	}
+}
+
+// addOr adds an $or clause, combining it with one already in the filter
+func addOr(filter bson.M, clause bson.A) {
+	existing, ok := filter["$or"]
+	if !ok {
+		filter["$or"] = clause
+		return
+	}
+	delete(filter, "$or")
+	filter["$and"] = bson.A{bson.M{"$or": existing}, bson.M{"$or": clause}}
+}
+
+// GetMatchByID returns a match page: the match with its clubs and league,
+// and the highlights and news linked to it.
+func (h *Handler) GetMatchByID(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	views, err := h.matchViews(ctx, []models.Match{*match}, lang)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
+		return
+	}
+
+	highlights, err := h.Repo.GetHighlights(ctx, bson.M{"match_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching highlights"})
+		return
+	}
+	news, err := h.Repo.GetContent(ctx, bson.M{"match_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching news"})
+		return
+	}
+
+	highlightItems := []FeedItem{}
+	for _, hl := range highlights {
+		highlightItems = append(highlightItems, highlightFeedItem(hl))
+	}
+	newsItems := []FeedItem{}
+	for _, n := range news {
+		newsItems = append(newsItems, newsFeedItem(n))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"match":      views[0],
+		"highlights": highlightItems,
+		"news":       newsItems,
+	})
+}
+
+// --- Admin ---
+
+func (h *Handler) AdminAddMatch(c *gin.Context) {
+	var input matchInput
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match, errs := input.toMatch(ctx, h)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	if err := h.Repo.CreateMatch(ctx, match); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add match"})
+		return
+	}
+
+	h.queueStandings(match.LeagueID, match.Season)
+	h.logActivity(c, "Added Match", "match", match.ID.Hex())
+	c.JSON(http.StatusCreated, match)
+}
+
+func (h *Handler) AdminUpdateMatch(c *gin.Context) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var input matchUpdate
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	update, errs := input.toUpdate(ctx, h, current)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	version, err := h.Repo.UpdateMatch(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		latest, err := h.Repo.FindMatchByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Match not found")
+			return
+		}
+		respondVersionConflict(c, latest, latest.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+		return
+	}
+
+	// A match moved to another league or season changes both tables
+	h.queueStandings(current.LeagueID, current.Season)
+	leagueID, _ := update["league_id"].(bson.ObjectID)
+	season, _ := update["season"].(string)
+	if (!leagueID.IsZero() && leagueID != current.LeagueID) || (season != "" && season != current.Season) {
+		h.queueStandings(cmp.Or(leagueID, current.LeagueID), cmp.Or(season, current.Season))
+	}
+	h.logActivity(c, "Updated Match", "match", id)
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Match updated successfully",
+		"version": version,
+	})
+}
+
+func (h *Handler) AdminDeleteMatch(c *gin.Context) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match, err := h.Repo.FindMatchByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	err = h.Repo.DeleteMatch(ctx, objID, currentUserID(c))
+	var depErr *repository.DependentsError
+	if errors.As(err, &depErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Match is still referenced and cannot be deleted",
+			"dependents": depErr.Dependents,
+		})
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+
+	h.queueStandings(match.LeagueID, match.Season)
+
+	h.logActivity(c, "Deleted Match", "match", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Match deleted successfully"})
+}
+
+func isMatchStatus(status string) bool {
+	for _, allowed := range models.MatchStatuses {
+		if status == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func checkMatchStatus(errs ValidationErrors, field string, status *string) {
+	if status != nil && !isMatchStatus(*status) {
+		errs.Add(field, "must be one of: "+strings.Join(models.MatchStatuses, ", "))
+	}
+}
+
+// matchInput is the body for creating a match
+type matchInput struct {
+	HomeClubID string    `json:"home_club_id"`
+	AwayClubID string    `json:"away_club_id"`
+	LeagueID   string    `json:"league_id"`
+	Season     string    `json:"season"`
+	KickoffAt  time.Time `json:"kickoff_at"`
+	Venue      string    `json:"venue"`
+	Status     string    `json:"status"`
+}
+
+func (in matchInput) toMatch(ctx context.Context, h *Handler) (models.Match, ValidationErrors) {
+	errs := ValidationErrors{}
+
+	checkSeason(errs, "season", &in.Season)
+	if in.KickoffAt.IsZero() {
+		errs.Add("kickoff_at", "is required")
+	}
+	if in.Status == "" {
+		in.Status = models.MatchScheduled
+	}
+	checkMatchStatus(errs, "status", &in.Status)
+
+	now := time.Now()
+	match := models.Match{
+		ID:         bson.NewObjectID(),
+		HomeClubID: h.resolveClubID(ctx, errs, "home_club_id", in.HomeClubID),
+		AwayClubID: h.resolveClubID(ctx, errs, "away_club_id", in.AwayClubID),
+		LeagueID:   h.resolveLeagueID(ctx, errs, "league_id", in.LeagueID),
+		Season:     in.Season,
+		KickoffAt:  in.KickoffAt,
+		Venue:      strings.TrimSpace(in.Venue),
+		Status:     in.Status,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Version:    1,
+	}
+	if !match.HomeClubID.IsZero() && match.HomeClubID == match.AwayClubID {
+		errs.Add("away_club_id", "must differ from home_club_id")
+	}
+	if len(errs) == 0 {
+		h.checkSeasonMembers(ctx, errs, match.LeagueID, match.Season, match.HomeClubID, match.AwayClubID)
+	}
+	return match, errs
+}
+
+type matchUpdate struct {
+	HomeClubID *string    `json:"home_club_id"`
+	AwayClubID *string    `json:"away_club_id"`
+	LeagueID   *string    `json:"league_id"`
+	Season     *string    `json:"season"`
+	KickoffAt  *time.Time `json:"kickoff_at"`
+	Venue      *string    `json:"venue"`
+	Status     *string    `json:"status"`
+}
+
+// toUpdate also needs the current match to check that the clubs still differ
+// when only one of them changes
+func (in matchUpdate) toUpdate(ctx context.Context, h *Handler, current *models.Match) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkSeason(errs, "season", in.Season)
+	checkMatchStatus(errs, "status", in.Status)
+
+	home, away := current.HomeClubID, current.AwayClubID
+	if in.HomeClubID != nil {
+		home = h.resolveClubID(ctx, errs, "home_club_id", *in.HomeClubID)
+		update["home_club_id"] = home
+	}
+	if in.AwayClubID != nil {
+		away = h.resolveClubID(ctx, errs, "away_club_id", *in.AwayClubID)
+		update["away_club_id"] = away
+	}
+	if home == away {
+		errs.Add("away_club_id", "must differ from home_club_id")
+	}
+	leagueID, season := current.LeagueID, current.Season
+	if in.LeagueID != nil {
+		leagueID = h.resolveLeagueID(ctx, errs, "league_id", *in.LeagueID)
+		update["league_id"] = leagueID
+	}
+	if in.Season != nil {
+		season = *in.Season
+		update["season"] = season
+	}
+	if len(errs) == 0 && (in.HomeClubID != nil || in.AwayClubID != nil || in.LeagueID != nil || in.Season != nil) {
+		h.checkSeasonMembers(ctx, errs, leagueID, season, home, away)
+	}
+	if in.KickoffAt != nil {
+		if in.KickoffAt.IsZero() {
+			errs.Add("kickoff_at", "must not be empty")
+		}
+		update["kickoff_at"] = *in.KickoffAt
+	}
+	if in.Venue != nil {
+		update["venue"] = optionalValue(strings.TrimSpace(*in.Venue))
+	}
+	if in.Status != nil {
+		update["status"] = *in.Status
+	}
+	return update, errs
+}
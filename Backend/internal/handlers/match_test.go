@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+func TestScheduleFilter(t *testing.T) {
+	now := time.Date(2024, 3, 2, 15, 0, 0, 0, time.UTC)
+	day := time.Date(2024, 3, 2, 0, 0, 0, 0, matchDayZone)
+	club := bson.NewObjectID()
+	upcoming := bson.A{bson.M{"kickoff_at": bson.M{"$gte": now}}, bson.M{"status": models.MatchLive}}
+	clubMatches := bson.A{bson.M{"home_club_id": club}, bson.M{"away_club_id": club}}
+
+	tests := []struct {
+		name      string
+		filter    bson.M
+		when      string
+		date      string
+		window    string
+		ascending bool
+		want      bson.M
+		invalid   bool
+	}{
+		{
+			name: "upcoming includes live matches", filter: bson.M{}, when: "upcoming",
+			window: "upcoming", ascending: true,
+			want: bson.M{"$or": upcoming},
+		},
+		{
+			name: "upcoming for a club", filter: bson.M{"$or": clubMatches}, when: "upcoming",
+			window: "upcoming", ascending: true,
+			want: bson.M{"$and": bson.A{bson.M{"$or": clubMatches}, bson.M{"$or": upcoming}}},
+		},
+		{
+			name: "recent leaves out live matches", filter: bson.M{"season": "2023/24"}, when: "recent",
+			window: "recent", ascending: false,
+			want: bson.M{"season": "2023/24", "kickoff_at": bson.M{"$lt": now}, "status": bson.M{"$ne": models.MatchLive}},
+		},
+		{
+			name: "a match day is an Addis Ababa day", filter: bson.M{}, when: "recent", date: "2024-03-02",
+			window: "date", ascending: true,
+			want: bson.M{"kickoff_at": bson.M{"$gte": day, "$lt": day.AddDate(0, 0, 1)}},
+		},
+		{name: "unknown window", filter: bson.M{}, when: "soon", invalid: true},
+		{name: "bad date", filter: bson.M{}, when: "upcoming", date: "02/03/2024", invalid: true},
+	}
+	for _, tt := range tests {
+		window, ascending, err := scheduleFilter(tt.filter, tt.when, tt.date, now)
+		if (err != nil) != tt.invalid {
+			t.Errorf("%s: error %v; want invalid %v", tt.name, err, tt.invalid)
+			continue
+		}
+		if tt.invalid {
+			continue
+		}
+		if window != tt.window || ascending != tt.ascending {
+			t.Errorf("%s: window %q ascending %v; want %q %v", tt.name, window, ascending, tt.window, tt.ascending)
+		}
+		if !reflect.DeepEqual(tt.filter, tt.want) {
+			t.Errorf("%s: filter %v; want %v", tt.name, tt.filter, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/repository"
+)
+
+// maxPinDuration stops items from being pinned indefinitely by mistake
+const maxPinDuration = 30 * 24 * time.Hour
+
+func (h *Handler) AdminPinContent(c *gin.Context) {
+	h.pin(c, "content")
+}
+
+func (h *Handler) AdminUnpinContent(c *gin.Context) {
+	h.unpin(c, "content")
+}
+
+func (h *Handler) AdminPinHighlight(c *gin.Context) {
+	h.pin(c, "highlight")
+}
+
+func (h *Handler) AdminUnpinHighlight(c *gin.Context) {
+	h.unpin(c, "highlight")
+}
+
+// pin keeps an item at the top of feeds until the given time, or for the
+// given number of hours. The worker unpins it once that passes. Pins beyond
+// maxPinnedItems are refused, as feeds would not show them.
+func (h *Handler) pin(c *gin.Context, entity string) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input struct {
+		Until *time.Time `json:"until"`
+		Hours *float64   `json:"hours"`
+	}
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	now := time.Now()
+	var until time.Time
+	switch {
+	case input.Until != nil && input.Hours != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide either 'until' or 'hours', not both"})
+		return
+	case input.Until != nil:
+		until = *input.Until
+	case input.Hours != nil:
+		until = now.Add(time.Duration(*input.Hours * float64(time.Hour)))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide 'until' or 'hours'"})
+		return
+	}
+	if !until.After(now) {
+		respondValidation(c, ValidationErrors{"until": "must be in the future"})
+		return
+	}
+	if until.Sub(now) > maxPinDuration {
+		respondValidation(c, ValidationErrors{"until": "must be within 30 days"})
+		return
+	}
+
+	// Feeds show at most maxPinnedItems pins, so more would be hidden
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pinned, err := h.Repo.CountPinned(ctx, now, objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count pinned items"})
+		return
+	}
+	if pinned >= maxPinnedItems {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("At most %d items can be pinned at once; unpin one first", maxPinnedItems)})
+		return
+	}
+
+	h.setPin(c, entity, objID, until)
+}
+
+func (h *Handler) unpin(c *gin.Context, entity string) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	h.setPin(c, entity, objID, nil)
+}
+
+// setPin stores pinnedUntil on the item, removing the pin when it is nil
+func (h *Handler) setPin(c *gin.Context, entity string, id bson.ObjectID, pinnedUntil interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"pinned_until": pinnedUntil}
+	var version int64
+	var err error
+	switch entity {
+	case "content":
+		version, err = h.Repo.UpdateContent(ctx, id, update, repository.AnyVersion)
+	case "highlight":
+		version, err = h.Repo.UpdateHighlight(ctx, id, update, repository.AnyVersion)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+		return
+	}
+
+	action := "Pinned"
+	if pinnedUntil == nil {
+		action = "Unpinned"
+	}
+	h.logActivity(c, action+" Item", entity, id.Hex())
+
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message":      action + " successfully",
+		"pinned_until": pinnedUntil,
+		"version":      version,
+	})
+}
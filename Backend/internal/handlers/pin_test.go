@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestIsPinned(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Minute), now.Add(-time.Minute)
+	tests := []struct {
+		name        string
+		pinnedUntil *time.Time
+		pinned      bool
+	}{
+		{"never pinned", nil, false},
+		{"pin running", &later, true},
+		{"pin expired", &earlier, false},
+		{"pin ends now", &now, false},
+	}
+	for _, tt := range tests {
+		if got := isPinned(tt.pinnedUntil, now); got != tt.pinned {
+			t.Errorf("%s: isPinned = %v; want %v", tt.name, got, tt.pinned)
+		}
+	}
+}
+
+// Expired pins fall back into the chronological pages without waiting for
+// the worker to clear them
+func TestPinFilter(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	base := bson.M{"club_id": "buna"}
+	tests := []struct {
+		name   string
+		pinned bool
+		want   bson.M
+	}{
+		{"pinned", true, bson.M{"club_id": "buna", "pinned_until": bson.M{"$gt": now}}},
+		{"chronological", false, bson.M{"club_id": "buna", "pinned_until": bson.M{"$not": bson.M{"$gt": now}}}},
+	}
+	for _, tt := range tests {
+		if got := pinFilter(base, tt.pinned, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: pinFilter = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+	if len(base) != 1 {
+		t.Errorf("pinFilter changed the filter it was given: %v", base)
+	}
+}
+
+func TestEditorialExtra(t *testing.T) {
+	later, earlier := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+	tests := []struct {
+		name        string
+		breaking    bool
+		pinnedUntil *time.Time
+		want        map[string]interface{}
+	}{
+		{"plain", false, nil, nil},
+		{"breaking", true, nil, map[string]interface{}{"breaking": true}},
+		{"pinned", false, &later, map[string]interface{}{"pinned": true, "pinned_until": later}},
+		{"expired pin", false, &earlier, nil},
+	}
+	for _, tt := range tests {
+		if got := editorialExtra(tt.breaking, tt.pinnedUntil); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: editorialExtra = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Bad pin requests are answered before anything is looked up
+func TestPinInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"nothing", `{}`, "Provide 'until' or 'hours'"},
+		{"both", `{"hours": 2, "until": "2099-01-01T00:00:00Z"}`, "not both"},
+		{"in the past", `{"until": "2000-01-01T00:00:00Z"}`, "must be in the future"},
+		{"no time at all", `{"hours": 0}`, "must be in the future"},
+		{"too long", `{"hours": 721}`, "must be within 30 days"},
+		{"unknown field", `{"days": 2}`, "unknown field"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+		c.Params = gin.Params{{Key: "id", Value: bson.NewObjectID().Hex()}}
+
+		(&Handler{}).pin(c, "content")
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), tt.want) {
+			t.Errorf("%s: %d %s; want 400 with %q", tt.name, w.Code, w.Body.String(), tt.want)
+		}
+	}
+}
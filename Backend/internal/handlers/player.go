@@ -0,0 +1,622 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// PlayerView is a player with its name resolved for the requested language
+type PlayerView struct {
+	models.Player
+	Language    string `json:"language"`
+	DisplayName string `json:"display_name"`
+}
+
+func localizePlayer(player models.Player, lang string) PlayerView {
+	return PlayerView{
+		Player:      player,
+		Language:    lang,
+		DisplayName: localize(player.Names, lang, player.Name),
+	}
+}
+
+// PlayerSquad is a club a player was registered with for a season
+type PlayerSquad struct {
+	Season string       `json:"season"`
+	ClubID string       `json:"club_id"`
+	Club   *TeamSummary `json:"club"`
+}
+
+// sortSquad orders players by position, then shirt number, then name
+func sortSquad(players []models.Player) {
+	slices.SortFunc(players, func(a, b models.Player) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(models.PlayerPositions, a.Position), slices.Index(models.PlayerPositions, b.Position)),
+			cmp.Compare(a.ShirtNumber, b.ShirtNumber),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// GetPlayers lists players, optionally for one position
+func (h *Handler) GetPlayers(c *gin.Context) {
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{}
+	if position := c.Query("position"); position != "" {
+		if !slices.Contains(models.PlayerPositions, position) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown position"})
+			return
+		}
+		filter["position"] = position
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	players, err := h.Repo.GetPlayers(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching players"})
+		return
+	}
+
+	views := make([]PlayerView, len(players))
+	for i, player := range players {
+		views[i] = localizePlayer(player, lang)
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+// GetPlayerByID returns a player page: the player, the clubs they were in
+// each season, and the highlights and news they are tagged in.
+func (h *Handler) GetPlayerByID(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	player, err := h.Repo.FindPlayerByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	squads, err := h.Repo.GetSquads(ctx, bson.M{"player_ids": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching squads"})
+		return
+	}
+	clubs, err := h.Repo.GetClubs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
+		return
+	}
+	clubByID := make(map[bson.ObjectID]models.Club, len(clubs))
+	for _, club := range clubs {
+		clubByID[club.ID] = club
+	}
+	history := make([]PlayerSquad, len(squads))
+	for i, squad := range squads {
+		history[i] = PlayerSquad{Season: squad.Season, ClubID: squad.ClubID.Hex()}
+		if club, ok := clubByID[squad.ClubID]; ok {
+			history[i].Club = clubSummary(club, lang)
+		}
+	}
+
+	highlights, err := h.Repo.GetHighlights(ctx, bson.M{"player_ids": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching highlights"})
+		return
+	}
+	news, err := h.Repo.GetContent(ctx, bson.M{"player_ids": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching news"})
+		return
+	}
+
+	highlightItems := []FeedItem{}
+	for _, hl := range highlights {
+		highlightItems = append(highlightItems, highlightFeedItem(hl))
+	}
+	newsItems := []FeedItem{}
+	for _, n := range news {
+		newsItems = append(newsItems, newsFeedItem(n))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":     localizePlayer(*player, lang),
+		"squads":     history,
+		"highlights": highlightItems,
+		"news":       newsItems,
+	})
+}
+
+// GetClubSquad returns a club's squad for a season. Without one it picks the
+// current season of the club's league, or else the latest squad.
+func (h *Handler) GetClubSquad(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+	season := c.Query("season")
+	if season != "" && !seasonPattern.MatchString(season) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "season must look like 2024 or 2024/25"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	club, err := h.Repo.FindClubByID(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+	squads, err := h.Repo.GetSquads(ctx, bson.M{"club_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching squads"})
+		return
+	}
+	seasons := make([]string, len(squads))
+	for i, squad := range squads {
+		seasons[i] = squad.Season
+	}
+
+	if season == "" && !club.LeagueID.IsZero() {
+		current, err := h.currentSeasonName(ctx, club.LeagueID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seasons"})
+			return
+		}
+		if slices.Contains(seasons, current) {
+			season = current
+		}
+	}
+	if season == "" && len(seasons) > 0 {
+		season = seasons[0]
+	}
+	i := slices.Index(seasons, season)
+	if i < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No squad for this season"})
+		return
+	}
+
+	players, err := h.Repo.GetPlayers(ctx, bson.M{"_id": bson.M{"$in": squads[i].PlayerIDs}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching players"})
+		return
+	}
+	sortSquad(players)
+	views := make([]PlayerView, len(players))
+	for j, player := range players {
+		views[j] = localizePlayer(player, lang)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"club_id":  objID.Hex(),
+		"season":   season,
+		"seasons":  seasons,
+		"squad_id": squads[i].ID.Hex(),
+		"players":  views,
+	})
+}
+
+// --- Admin ---
+
+func (h *Handler) AdminAddPlayer(c *gin.Context) {
+	var input playerInput
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	player, errs := input.toPlayer()
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := h.Repo.CreatePlayer(ctx, player); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add player"})
+		return
+	}
+
+	h.logActivity(c, "Added Player", "player", player.ID.Hex())
+	c.JSON(http.StatusCreated, player)
+}
+
+func (h *Handler) AdminUpdatePlayer(c *gin.Context) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var input playerUpdate
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	update, errs := input.toUpdate()
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := h.Repo.UpdatePlayer(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		latest, err := h.Repo.FindPlayerByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Player not found")
+			return
+		}
+		respondVersionConflict(c, latest, latest.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+		return
+	}
+
+	h.logActivity(c, "Updated Player", "player", id)
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Player updated successfully",
+		"version": version,
+	})
+}
+
+// AdminDeletePlayer removes a player from squads and untags them from news
+// and highlights
+func (h *Handler) AdminDeletePlayer(c *gin.Context) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = h.Repo.DeletePlayer(ctx, objID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+
+	h.logActivity(c, "Deleted Player", "player", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Player deleted successfully"})
+}
+
+func (h *Handler) AdminAddSquad(c *gin.Context) {
+	clubID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input squadInput
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindClubByID(ctx, clubID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+
+	squad, errs := input.toSquad(ctx, h, clubID)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	if err := h.Repo.CreateSquad(ctx, squad); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add squad"})
+		return
+	}
+
+	h.logActivity(c, "Added Squad", "squad", squad.ID.Hex())
+	c.JSON(http.StatusCreated, squad)
+}
+
+func (h *Handler) AdminUpdateSquad(c *gin.Context) {
+	clubID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	id := c.Param("squadId")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid squad ID"})
+		return
+	}
+
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var input squadUpdate
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.Repo.FindSquadByID(ctx, objID)
+	if err != nil || current.ClubID != clubID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Squad not found"})
+		return
+	}
+
+	update, errs := input.toUpdate(ctx, h)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	version, err := h.Repo.UpdateSquad(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		latest, err := h.Repo.FindSquadByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Squad not found")
+			return
+		}
+		respondVersionConflict(c, latest, latest.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Squad not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+		return
+	}
+
+	h.logActivity(c, "Updated Squad", "squad", id)
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Squad updated successfully",
+		"version": version,
+	})
+}
+
+func (h *Handler) AdminDeleteSquad(c *gin.Context) {
+	clubID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	id := c.Param("squadId")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid squad ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	squad, err := h.Repo.FindSquadByID(ctx, objID)
+	if err != nil || squad.ClubID != clubID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Squad not found"})
+		return
+	}
+
+	err = h.Repo.DeleteSquad(ctx, objID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Squad not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+
+	h.logActivity(c, "Deleted Squad", "squad", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Squad deleted successfully"})
+}
+
+func checkPlayerPosition(errs ValidationErrors, field string, position *string) {
+	if position != nil && !slices.Contains(models.PlayerPositions, *position) {
+		errs.Add(field, "must be one of: "+strings.Join(models.PlayerPositions, ", "))
+	}
+}
+
+// checkShirtNumber accepts 0, which leaves the player without a number
+func checkShirtNumber(errs ValidationErrors, field string, number *int) {
+	if number != nil && *number != 0 && (*number < models.MinShirtNumber || *number > models.MaxShirtNumber) {
+		errs.Add(field, fmt.Sprintf("must be between %d and %d, or 0 for no number", models.MinShirtNumber, models.MaxShirtNumber))
+	}
+}
+
+// playerInput is the body for creating a player
+type playerInput struct {
+	Name        string                  `json:"name"`
+	Names       *models.MultiLangString `json:"names"`
+	Position    string                  `json:"position"`
+	ShirtNumber int                     `json:"shirt_number"`
+	Nationality string                  `json:"nationality"`
+	PhotoURL    string                  `json:"photo_url"`
+}
+
+func (in playerInput) toPlayer() (models.Player, ValidationErrors) {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(in.Name) == "" {
+		errs.Add("name", "is required")
+	}
+	checkPlayerPosition(errs, "position", &in.Position)
+	checkShirtNumber(errs, "shirt_number", &in.ShirtNumber)
+	if in.PhotoURL != "" {
+		checkURL(errs, "photo_url", &in.PhotoURL)
+	}
+
+	now := time.Now()
+	player := models.Player{
+		ID:          bson.NewObjectID(),
+		Name:        strings.TrimSpace(in.Name),
+		Position:    in.Position,
+		ShirtNumber: in.ShirtNumber,
+		Nationality: strings.TrimSpace(in.Nationality),
+		PhotoURL:    in.PhotoURL,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Version:     1,
+	}
+	if in.Names != nil {
+		if names, ok := optionalText(in.Names).(models.MultiLangString); ok {
+			player.Names = &names
+		}
+	}
+	return player, errs
+}
+
+// squadInput is the body for registering a club's squad for a season
+type squadInput struct {
+	Season    string   `json:"season"`
+	PlayerIDs []string `json:"player_ids"`
+}
+
+func (in squadInput) toSquad(ctx context.Context, h *Handler, clubID bson.ObjectID) (models.Squad, ValidationErrors) {
+	errs := ValidationErrors{}
+
+	checkSeason(errs, "season", &in.Season)
+	switch _, err := h.Repo.FindSquad(ctx, clubID, in.Season); {
+	case err == nil:
+		errs.Add("season", "club already has a squad for this season")
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		errs.fail(err)
+	}
+
+	now := time.Now()
+	return models.Squad{
+		ID:        bson.NewObjectID(),
+		ClubID:    clubID,
+		Season:    in.Season,
+		PlayerIDs: h.resolvePlayerIDs(ctx, errs, "player_ids", in.PlayerIDs),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}, errs
+}
+
+type playerUpdate struct {
+	Name        *string                 `json:"name"`
+	Names       *models.MultiLangString `json:"names"`
+	Position    *string                 `json:"position"`
+	ShirtNumber *int                    `json:"shirt_number"`
+	Nationality *string                 `json:"nationality"`
+	PhotoURL    *string                 `json:"photo_url"`
+}
+
+func (in playerUpdate) toUpdate() (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkRequiredString(errs, "name", in.Name)
+	checkPlayerPosition(errs, "position", in.Position)
+	checkShirtNumber(errs, "shirt_number", in.ShirtNumber)
+	if in.PhotoURL != nil && *in.PhotoURL != "" {
+		checkURL(errs, "photo_url", in.PhotoURL)
+	}
+
+	if in.Name != nil {
+		update["name"] = strings.TrimSpace(*in.Name)
+	}
+	if in.Names != nil {
+		update["names"] = optionalText(in.Names)
+	}
+	if in.Position != nil {
+		update["position"] = *in.Position
+	}
+	if in.ShirtNumber != nil {
+		update["shirt_number"] = optionalValue(*in.ShirtNumber)
+	}
+	if in.Nationality != nil {
+		update["nationality"] = optionalValue(strings.TrimSpace(*in.Nationality))
+	}
+	if in.PhotoURL != nil {
+		update["photo_url"] = optionalValue(*in.PhotoURL)
+	}
+	return update, errs
+}
+
+// squadUpdate cannot move a squad to another season
+type squadUpdate struct {
+	PlayerIDs *[]string `json:"player_ids"`
+}
+
+func (in squadUpdate) toUpdate(ctx context.Context, h *Handler) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	if in.PlayerIDs != nil {
+		update["player_ids"] = h.resolvePlayerIDs(ctx, errs, "player_ids", *in.PlayerIDs)
+	}
+	return update, errs
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"testing"
+
+	"fanzone/internal/models"
+)
+
+func TestSortSquad(t *testing.T) {
+	players := []models.Player{
+		{Name: "Abel", Position: models.PositionForward, ShirtNumber: 9},
+		{Name: "Getaneh", Position: models.PositionDefender, ShirtNumber: 5},
+		{Name: "Bereket", Position: models.PositionDefender, ShirtNumber: 5},
+		{Name: "Dawit", Position: models.PositionGoalkeeper, ShirtNumber: 1},
+		{Name: "Amanuel", Position: models.PositionMidfielder},
+		{Name: "Shimelis", Position: models.PositionDefender, ShirtNumber: 2},
+	}
+	sortSquad(players)
+
+	want := []string{"Dawit", "Shimelis", "Bereket", "Getaneh", "Amanuel", "Abel"}
+	for i, player := range players {
+		if player.Name != want[i] {
+			t.Fatalf("squad order %d is %s; want %v", i, player.Name, want)
+		}
+	}
+}
+
+func TestPlayerInput(t *testing.T) {
+	player, errs := playerInput{
+		Name:        "  Getaneh Kebede ",
+		Names:       &models.MultiLangString{AM: " ጌታነህ ከበደ "},
+		Position:    models.PositionForward,
+		ShirtNumber: 9,
+		Nationality: " ET ",
+	}.toPlayer()
+	if len(errs) != 0 {
+		t.Fatalf("errors %v; want none", errs)
+	}
+	if player.Name != "Getaneh Kebede" || player.Nationality != "ET" || player.Version != 1 || player.ID.IsZero() {
+		t.Errorf("player = %+v; want trimmed fields, an ID and version 1", player)
+	}
+	if player.Names == nil || player.Names.AM != "ጌታነህ ከበደ" {
+		t.Errorf("names = %+v; want the trimmed Amharic name", player.Names)
+	}
+
+	_, errs = playerInput{Name: " ", Position: "winger", ShirtNumber: 100, PhotoURL: "not a url"}.toPlayer()
+	for _, field := range []string{"name", "position", "shirt_number", "photo_url"} {
+		if errs[field] == "" {
+			t.Errorf("missing error for %s in %v", field, errs)
+		}
+	}
+
+	// Without a number or translations the fields are left out
+	player, errs = playerInput{Name: "Abel", Position: models.PositionGoalkeeper, Names: &models.MultiLangString{}}.toPlayer()
+	if len(errs) != 0 || player.ShirtNumber != 0 || player.Names != nil {
+		t.Errorf("player = %+v, errors %v; want no number and no names", player, errs)
+	}
+}
+
+func TestPlayerUpdate(t *testing.T) {
+	name, position, number, empty := " Abel ", models.PositionMidfielder, 0, ""
+	update, errs := playerUpdate{Name: &name, Position: &position, ShirtNumber: &number, Nationality: &empty}.toUpdate()
+	if len(errs) != 0 {
+		t.Fatalf("errors %v; want none", errs)
+	}
+	// Clearing the number and nationality removes them
+	if update["name"] != "Abel" || update["position"] != position || update["shirt_number"] != nil || update["nationality"] != nil {
+		t.Errorf("update = %v", update)
+	}
+	if _, ok := update["shirt_number"]; !ok {
+		t.Errorf("update = %v; want shirt_number cleared", update)
+	}
+	if _, ok := update["photo_url"]; ok {
+		t.Errorf("update = %v; want photo_url untouched", update)
+	}
+
+	blank, negative := "", -1
+	if _, errs := (playerUpdate{Name: &blank, ShirtNumber: &negative}).toUpdate(); errs["name"] == "" || errs["shirt_number"] == "" {
+		t.Errorf("errors %v; want name and shirt_number", errs)
+	}
+}
+
+func TestLocalizePlayer(t *testing.T) {
+	player := models.Player{Name: "Getaneh Kebede", Names: &models.MultiLangString{AM: "ጌታነህ ከበደ"}}
+	for lang, want := range map[string]string{"am": "ጌታነህ ከበደ", "en": "Getaneh Kebede", "om": "Getaneh Kebede"} {
+		if got := localizePlayer(player, lang); got.DisplayName != want || got.Language != lang {
+			t.Errorf("%s: %s (%s); want %s", lang, got.DisplayName, got.Language, want)
+		}
+	}
+}
+
+func TestCheckShirtNumber(t *testing.T) {
+	for number, ok := range map[int]bool{0: true, 1: true, 99: true, -1: false, 100: false} {
+		errs := ValidationErrors{}
+		checkShirtNumber(errs, "shirt_number", &number)
+		if (len(errs) == 0) != ok {
+			t.Errorf("checkShirtNumber(%d) = %v; want ok %v", number, errs, ok)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/ranking"
+	"fanzone/internal/repository"
+)
+
+// rankingWindow is how many of the newest matching items are considered for
+// the ranked feed. Older items are only reachable in chronological order.
+const rankingWindow = 200
+
+// respondRankedFeed scores the newest candidates for the user and returns one
+// page of them in ranked order. The cursor is an offset into that order.
+func (h *Handler) respondRankedFeed(ctx context.Context, c *gin.Context, user *models.User, leagueClubIDs []bson.ObjectID, newsFilter, highlightFilter bson.M, extra gin.H) {
+	limit, offset, ok := parseRankedPage(c)
+	if !ok {
+		return
+	}
+
+	weights, err := h.rankingWeights(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading ranking weights"})
+		return
+	}
+
+	entries, err := h.Repo.GetFeedPage(ctx, newsFilter, highlightFilter, nil, rankingWindow)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching feed"})
+		return
+	}
+
+	now := time.Now()
+	feed, items, err := toRankingItems(entries, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding feed"})
+		return
+	}
+
+	order := ranking.Rank(items, ranking.WeightedScorer{Weights: *weights}, rankingProfile(user, leagueClubIDs), now)
+
+	page := []FeedItem{}
+	for i := offset; i < len(order) && int64(len(page)) < limit; i++ {
+		page = append(page, feed[order[i]])
+	}
+
+	response := gin.H{
+		"feed":        page,
+		"total_items": len(page),
+		"next_cursor": nil,
+		"sort":        "ranked",
+	}
+	if next := offset + len(page); next < len(order) {
+		response["next_cursor"] = encodeRankedCursor(next)
+	}
+	for k, v := range extra {
+		response[k] = v
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// rankingWeights returns the admin-tuned weights, or the defaults if none
+// have been saved
+func (h *Handler) rankingWeights(ctx context.Context) (*models.RankingWeights, error) {
+	weights, err := h.Repo.GetRankingWeights(ctx)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		defaults := ranking.DefaultWeights
+		return &defaults, nil
+	}
+	return weights, err
+}
+
+func rankingProfile(user *models.User, leagueClubIDs []bson.ObjectID) ranking.Profile {
+	profile := ranking.Profile{
+		PrimaryClubID: user.FavClubID,
+		ClubIDs:       map[bson.ObjectID]bool{},
+		LeagueIDs:     map[bson.ObjectID]bool{},
+		LeagueClubIDs: map[bson.ObjectID]bool{},
+		Categories:    map[string]bool{},
+		Language:      user.Language,
+	}
+	for _, id := range followedClubIDs(user) {
+		profile.ClubIDs[id] = true
+	}
+	for _, id := range user.FollowedLeagueIDs {
+		profile.LeagueIDs[id] = true
+	}
+	for _, id := range leagueClubIDs {
+		profile.LeagueClubIDs[id] = true
+	}
+	for _, category := range user.PreferredCategories {
+		profile.Categories[category] = true
+	}
+	return profile
+}
+
+// toRankingItems decodes feed entries into both their response form and the
+// form the scorer reads, index for index
+func toRankingItems(entries []repository.FeedEntry, now time.Time) ([]FeedItem, []ranking.Item, error) {
+	feed := make([]FeedItem, 0, len(entries))
+	items := make([]ranking.Item, 0, len(entries))
+	for _, entry := range entries {
+		switch entry.Type {
+		case "news":
+			var n models.Content
+			if err := bson.Unmarshal(entry.Raw, &n); err != nil {
+				return nil, nil, err
+			}
+			item := ranking.Item{
+				ID:        n.ID,
+				CreatedAt: n.CreatedAt,
+				Category:  n.Category,
+				LeagueIDs: n.LeagueIDs,
+				Views:     n.Views,
+				Languages: availableLanguages(n.Title),
+				Pinned:    isPinned(n.PinnedUntil, now),
+			}
+			if !n.ClubID.IsZero() {
+				item.ClubIDs = []bson.ObjectID{n.ClubID}
+			}
+			feed = append(feed, newsFeedItem(n))
+			items = append(items, item)
+		case "highlight":
+			var hl models.Highlight
+			if err := bson.Unmarshal(entry.Raw, &hl); err != nil {
+				return nil, nil, err
+			}
+			feed = append(feed, highlightFeedItem(hl))
+			items = append(items, ranking.Item{
+				ID:        hl.ID,
+				CreatedAt: hl.CreatedAt,
+				ClubIDs:   hl.ClubIDs,
+				LeagueIDs: hl.LeagueIDs,
+				Views:     hl.Views,
+				Pinned:    isPinned(hl.PinnedUntil, now),
+			})
+		}
+	}
+	return feed, items, nil
+}
+
+// availableLanguages lists the languages a text has been translated into
+func availableLanguages(text models.MultiLangString) []string {
+	languages := []string{}
+	if strings.TrimSpace(text.EN) != "" {
+		languages = append(languages, "en")
+	}
+	if strings.TrimSpace(text.AM) != "" {
+		languages = append(languages, "am")
+	}
+	if strings.TrimSpace(text.OM) != "" {
+		languages = append(languages, "om")
+	}
+	return languages
+}
+
+// parseRankedPage reads the limit and the ranked cursor. It writes the error
+// response itself and reports whether they were valid.
+func parseRankedPage(c *gin.Context) (int64, int, bool) {
+	limit, ok := parseFeedLimit(c)
+	if !ok {
+		return 0, 0, false
+	}
+
+	offset := 0
+	if raw := c.Query("cursor"); raw != "" {
+		n, err := decodeRankedCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return 0, 0, false
+		}
+		offset = n
+	}
+	return limit, offset, true
+}
+
+func encodeRankedCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("rank:" + strconv.Itoa(offset)))
+}
+
+func decodeRankedCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), "rank:"))
+	if err != nil || offset < 0 || !strings.HasPrefix(string(raw), "rank:") {
+		return 0, errors.New("invalid ranked cursor")
+	}
+	return offset, nil
+}
+
+// --- Admin ---
+
+func (h *Handler) AdminGetRankingWeights(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	weights, err := h.rankingWeights(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading ranking weights"})
+		return
+	}
+	c.JSON(http.StatusOK, weights)
+}
+
+func (h *Handler) AdminUpdateRankingWeights(c *gin.Context) {
+	var input struct {
+		Recency              *float64 `json:"recency"`
+		RecencyHalfLifeHours *float64 `json:"recency_half_life_hours"`
+		Follow               *float64 `json:"follow"`
+		Category             *float64 `json:"category"`
+		Engagement           *float64 `json:"engagement"`
+		Language             *float64 `json:"language"`
+	}
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.rankingWeights(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading ranking weights"})
+		return
+	}
+	weights := *current
+
+	errs := ValidationErrors{}
+	fields := []struct {
+		name  string
+		value *float64
+		dst   *float64
+	}{
+		{"recency", input.Recency, &weights.Recency},
+		{"recency_half_life_hours", input.RecencyHalfLifeHours, &weights.RecencyHalfLifeHours},
+		{"follow", input.Follow, &weights.Follow},
+		{"category", input.Category, &weights.Category},
+		{"engagement", input.Engagement, &weights.Engagement},
+		{"language", input.Language, &weights.Language},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		if *f.value < 0 {
+			errs.Add(f.name, "must not be negative")
+			continue
+		}
+		*f.dst = *f.value
+	}
+	if input.RecencyHalfLifeHours != nil && *input.RecencyHalfLifeHours == 0 {
+		errs.Add("recency_half_life_hours", "must be greater than zero")
+	}
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	weights.UpdatedAt = time.Now()
+	if err := h.Repo.SaveRankingWeights(ctx, weights); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save ranking weights"})
+		return
+	}
+
+	h.logActivity(c, "Updated Ranking Weights", "settings", "feed_ranking")
+	c.JSON(http.StatusOK, weights)
+}
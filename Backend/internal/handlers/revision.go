@@ -0,0 +1,382 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// revisionCollections maps a revisioned entity to the collection holding it
+var revisionCollections = map[string]string{
+	"content":   "content",
+	"highlight": "highlights",
+}
+
+// FieldChange describes a single field that differs between two snapshots.
+// Nested fields use dot notation, e.g. "title.en".
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+func (h *Handler) AdminGetContentRevisions(c *gin.Context) {
+	h.listRevisions(c, "content")
+}
+
+func (h *Handler) AdminDiffContentRevisions(c *gin.Context) {
+	h.diffRevisions(c, "content")
+}
+
+func (h *Handler) AdminRestoreContentRevision(c *gin.Context) {
+	h.restoreRevision(c, "content")
+}
+
+func (h *Handler) AdminGetHighlightRevisions(c *gin.Context) {
+	h.listRevisions(c, "highlight")
+}
+
+func (h *Handler) AdminDiffHighlightRevisions(c *gin.Context) {
+	h.diffRevisions(c, "highlight")
+}
+
+func (h *Handler) AdminRestoreHighlightRevision(c *gin.Context) {
+	h.restoreRevision(c, "highlight")
+}
+
+func (h *Handler) listRevisions(c *gin.Context, entity string) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revisions, err := h.Repo.GetRevisions(ctx, entity, objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching revisions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+}
+
+func (h *Handler) diffRevisions(c *gin.Context, entity string) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	fromID, err := bson.ObjectIDFromHex(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'from' revision ID"})
+		return
+	}
+	toID, err := bson.ObjectIDFromHex(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'to' revision ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	from, err := h.Repo.FindRevision(ctx, entity, objID, fromID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision 'from' not found"})
+		return
+	}
+	to, err := h.Repo.FindRevision(ctx, entity, objID, toID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision 'to' not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    from.Number,
+		"to":      to.Number,
+		"changes": diffSnapshots(from.Snapshot, to.Snapshot),
+	})
+}
+
+func (h *Handler) restoreRevision(c *gin.Context, entity string) {
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	revisionID, err := bson.ObjectIDFromHex(c.Param("revisionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return
+	}
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	revision, err := h.Repo.FindRevision(ctx, entity, objID, revisionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+
+	before, err := h.loadSnapshot(ctx, entity, objID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	version := snapshotVersion(before)
+	if expectedVersion != repository.AnyVersion && expectedVersion != version {
+		respondVersionConflict(c, before, version)
+		return
+	}
+
+	// The restored document is a new version, not a rewind of the counter
+	restoredDoc := bson.M{}
+	for k, v := range revision.Snapshot {
+		restoredDoc[k] = v
+	}
+	restoredDoc["version"] = version + 1
+	// Views and pins are not part of the revisioned content
+	restoredDoc["views"] = before["views"]
+	if pinnedUntil, ok := before["pinned_until"]; ok {
+		restoredDoc["pinned_until"] = pinnedUntil
+	} else {
+		delete(restoredDoc, "pinned_until")
+	}
+	restoredDoc["updated_at"] = time.Now()
+
+	// Clubs, leagues, matches or players the revision names may have been
+	// deleted since
+	collection := revisionCollections[entity]
+	err = h.Repo.CheckReferences(ctx, collection, objID, restoredDoc)
+	var missingErr *repository.MissingParentsError
+	if errors.As(err, &missingErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Revision refers to deleted items; restore them first",
+			"missing": missingErr.Missing,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Restore failed"})
+		return
+	}
+
+	err = h.Repo.ReplaceDocument(ctx, collection, objID, restoredDoc, version)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		current, err := h.loadSnapshot(ctx, entity, objID)
+		if err != nil {
+			respondLookupError(c, err, "Document not found")
+			return
+		}
+		respondVersionConflict(c, current, snapshotVersion(current))
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Restore failed"})
+		return
+	}
+
+	restored, err := h.recordRevision(ctx, c, entity, objID, "restore", before)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Restored, but failed to record revision"})
+		return
+	}
+
+	h.logActivity(c, "Restored Revision", entity, id)
+	setVersionETag(c, version+1)
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Revision restored successfully",
+		"revision": restored,
+		"version":  version + 1,
+	})
+}
+
+// maxRevisionAttempts bounds how often recording a revision is retried when
+// concurrent edits of a document race for the same number
+const maxRevisionAttempts = 5
+
+// recordRevision snapshots the current state of a document and stores it as
+// the next revision. previous is the state before the change (nil on create)
+// and is used to work out which fields changed.
+func (h *Handler) recordRevision(ctx context.Context, c *gin.Context, entity string, id bson.ObjectID, action string, previous bson.M) (*models.Revision, error) {
+	revision, err := h.newRevision(ctx, c, entity, id, action, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revision numbers are unique per document, so a number taken by a
+	// concurrent edit fails the insert and the next one is tried
+	for attempt := 1; ; attempt++ {
+		latest, err := h.Repo.LatestRevisionNumber(ctx, entity, id)
+		if err != nil {
+			return nil, err
+		}
+		revision.Number = latest + 1
+		err = h.Repo.CreateRevision(ctx, *revision)
+		if err == nil {
+			return revision, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == maxRevisionAttempts {
+			return nil, err
+		}
+	}
+}
+
+// newRevision snapshots a document as a revision without a number
+func (h *Handler) newRevision(ctx context.Context, c *gin.Context, entity string, id bson.ObjectID, action string, previous bson.M) (*models.Revision, error) {
+	snapshot, err := h.loadSnapshot(ctx, entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for _, change := range diffSnapshots(previous, snapshot) {
+		changed = append(changed, change.Field)
+	}
+
+	return &models.Revision{
+		ID:            bson.NewObjectID(),
+		Entity:        entity,
+		EntityID:      id,
+		Action:        action,
+		ChangedFields: changed,
+		Snapshot:      snapshot,
+		EditorID:      currentUserID(c),
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// ensureBaseRevision records the existing state of a document as its first
+// revision if it predates revision tracking. When two edits race to do so,
+// the one that loses finds revision 1 taken and leaves it.
+func (h *Handler) ensureBaseRevision(ctx context.Context, c *gin.Context, entity string, id bson.ObjectID) error {
+	latest, err := h.Repo.LatestRevisionNumber(ctx, entity, id)
+	if err != nil || latest > 0 {
+		return err
+	}
+	revision, err := h.newRevision(ctx, c, entity, id, "create", nil)
+	if err != nil {
+		return err
+	}
+	revision.Number = 1
+	if err := h.Repo.CreateRevision(ctx, *revision); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+func (h *Handler) loadSnapshot(ctx context.Context, entity string, id bson.ObjectID) (bson.M, error) {
+	var doc interface{}
+	var err error
+
+	switch entity {
+	case "content":
+		doc, err = h.Repo.FindContentByID(ctx, id)
+	case "highlight":
+		doc, err = h.Repo.FindHighlightByID(ctx, id)
+	default:
+		return nil, errors.New("unknown revision entity: " + entity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot bson.M
+	err = bson.Unmarshal(raw, &snapshot)
+	return snapshot, err
+}
+
+// diffSnapshots compares two snapshots field by field, returning the changes
+// sorted by field name.
+func diffSnapshots(from, to bson.M) []FieldChange {
+	fromFields := map[string]interface{}{}
+	toFields := map[string]interface{}{}
+	flattenSnapshot("", from, fromFields)
+	flattenSnapshot("", to, toFields)
+
+	changes := []FieldChange{}
+	for field, toValue := range toFields {
+		fromValue, ok := fromFields[field]
+		if !ok || !reflect.DeepEqual(fromValue, toValue) {
+			changes = append(changes, FieldChange{Field: field, From: fromValue, To: toValue})
+		}
+	}
+	for field, fromValue := range fromFields {
+		if _, ok := toFields[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, From: fromValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func flattenSnapshot(prefix string, doc bson.M, out map[string]interface{}) {
+	for key, value := range doc {
+		// Identity and bookkeeping fields are not content changes
+		if prefix == "" && (key == "_id" || key == "version" || key == "updated_at" || key == "views" || key == "pinned_until") {
+			continue
+		}
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+
+		switch nested := value.(type) {
+		case bson.M:
+			flattenSnapshot(field, nested, out)
+		case bson.D:
+			m := bson.M{}
+			for _, e := range nested {
+				m[e.Key] = e.Value
+			}
+			flattenSnapshot(field, m, out)
+		default:
+			out[field] = value
+		}
+	}
+}
+
+func snapshotVersion(snapshot bson.M) int64 {
+	switch v := snapshot["version"].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	}
+	return 0
+}
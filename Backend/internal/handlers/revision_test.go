@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Restoring a revision overwrites the document, so like any other admin
+// write it needs the version the admin last saw
+func TestRestoreRevisionRequiresIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Params = gin.Params{
+		{Key: "id", Value: bson.NewObjectID().Hex()},
+		{Key: "revisionId", Value: bson.NewObjectID().Hex()},
+	}
+
+	(&Handler{}).AdminRestoreContentRevision(c)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("status %d; want 428", w.Code)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	club := bson.NewObjectID()
+	from := bson.M{"_id": 1, "version": int64(3), "title": bson.M{"en": "Win", "am": "ድል"}, "club_id": club, "views": 10}
+	to := bson.M{"_id": 1, "version": int64(4), "title": bson.M{"en": "Big win"}, "tags": bson.A{"derby"}, "views": 12}
+
+	got := diffSnapshots(from, to)
+	want := []string{"club_id", "tags", "title.am", "title.en"}
+	if len(got) != len(want) {
+		t.Fatalf("changes = %+v; want fields %v", got, want)
+	}
+	for i, change := range got {
+		if change.Field != want[i] {
+			t.Errorf("change %d = %s; want %s", i, change.Field, want[i])
+		}
+	}
+	if got[0].To != nil || got[3].From != "Win" || got[3].To != "Big win" {
+		t.Errorf("changes = %+v", got)
+	}
+}
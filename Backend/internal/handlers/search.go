@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+	"fanzone/internal/search"
+)
+
+const (
+	// maxQueryLength caps the search string, in characters
+	maxQueryLength = 200
+	// searchCandidateLimit is how many indexed documents per collection are
+	// ranked for one query
+	searchCandidateLimit = 500
+)
+
+// searchTypes are the values the type filter accepts
+var searchTypes = []string{"news", "highlight", "club", "league"}
+
+// SearchResult is one ranked match. Item is the matched document.
+type SearchResult struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Score     float64         `json:"score"`
+	Snippet   *search.Snippet `json:"snippet"`
+	Item      interface{}     `json:"item"`
+	createdAt time.Time
+}
+
+// Search ranks news, highlights, clubs and leagues against q. Results are
+// paged with the same offset cursor as the ranked feed.
+func (h *Handler) Search(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	raw := strings.TrimSpace(c.Query("q"))
+	if utf8.RuneCountInString(raw) > maxQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be at most 200 characters"})
+		return
+	}
+	query := search.ParseQuery(raw)
+	if query.Empty() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must contain at least one word"})
+		return
+	}
+
+	types, ok := parseSearchTypes(c)
+	if !ok {
+		return
+	}
+	lang := c.Query("lang")
+	if lang != "" && lang != "en" && lang != "am" && lang != "om" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lang must be one of: en, am, om"})
+		return
+	}
+	limit, offset, ok := parseRankedPage(c)
+	if !ok {
+		return
+	}
+
+	var results []SearchResult
+	if types["news"] {
+		found, err := h.searchContent(ctx, query, lang)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching news"})
+			return
+		}
+		results = append(results, found...)
+	}
+	if types["highlight"] {
+		found, err := h.searchHighlights(ctx, query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching highlights"})
+			return
+		}
+		results = append(results, found...)
+	}
+	if types["club"] {
+		found, err := h.searchClubs(ctx, query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching clubs"})
+			return
+		}
+		results = append(results, found...)
+	}
+	if types["league"] {
+		found, err := h.searchLeagues(ctx, query)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching leagues"})
+			return
+		}
+		results = append(results, found...)
+	}
+
+	// Best match first; newer first among equals
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].createdAt.Equal(results[j].createdAt) {
+			return results[i].createdAt.After(results[j].createdAt)
+		}
+		return results[i].ID > results[j].ID
+	})
+
+	page := []SearchResult{}
+	for i := offset; i < len(results) && int64(len(page)) < limit; i++ {
+		page = append(page, results[i])
+	}
+
+	response := gin.H{
+		"query":       raw,
+		"terms":       query.Terms,
+		"results":     page,
+		"total":       len(results),
+		"next_cursor": nil,
+	}
+	if next := offset + len(page); next < len(results) {
+		response["next_cursor"] = encodeRankedCursor(next)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// parseSearchTypes reads the comma-separated type filter; all types are
+// searched by default. It writes the error response itself.
+func parseSearchTypes(c *gin.Context) (map[string]bool, bool) {
+	types := map[string]bool{}
+	raw := c.Query("type")
+	if raw == "" {
+		for _, t := range searchTypes {
+			types[t] = true
+		}
+		return types, true
+	}
+
+	for _, t := range strings.Split(raw, ",") {
+		t = strings.TrimSpace(t)
+		valid := false
+		for _, known := range searchTypes {
+			valid = valid || t == known
+		}
+		if !valid {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "type must be a comma-separated list of: " + strings.Join(searchTypes, ", ")})
+			return nil, false
+		}
+		types[t] = true
+	}
+	return types, true
+}
+
+// contentSearchFields weighs titles over bodies. The requested language gets
+// a slight boost so its snippet wins when several translations match.
+func contentSearchFields(content models.Content, lang string) []search.Field {
+	fields := []search.Field{}
+	for _, l := range []string{"en", "am", "om"} {
+		boost := 1.0
+		if l == lang {
+			boost = 1.2
+		}
+		fields = append(fields,
+			search.Field{Name: "title." + l, Text: translation(content.Title, l), Weight: 3 * boost},
+			search.Field{Name: "body." + l, Text: translation(content.Body, l), Weight: boost},
+		)
+	}
+	return fields
+}
+
+func (h *Handler) searchContent(ctx context.Context, query search.Query, lang string) ([]SearchResult, error) {
+	ids, err := h.Repo.SearchCandidates(ctx, "content", query.Terms, searchCandidateLimit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	contents, err := h.Repo.GetContent(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, content := range contents {
+		match := query.Match(contentSearchFields(content, lang))
+		if match.Score == 0 {
+			continue
+		}
+		results = append(results, SearchResult{
+			Type: "news", ID: content.ID.Hex(), Score: match.Score, Snippet: match.Snippet,
+			Item: content, createdAt: content.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
+func (h *Handler) searchHighlights(ctx context.Context, query search.Query) ([]SearchResult, error) {
+	ids, err := h.Repo.SearchCandidates(ctx, "highlights", query.Terms, searchCandidateLimit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	highlights, err := h.Repo.GetHighlights(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, highlight := range highlights {
+		match := query.Match([]search.Field{{Name: "match_title", Text: highlight.MatchTitle, Weight: 3}})
+		if match.Score == 0 {
+			continue
+		}
+		results = append(results, SearchResult{
+			Type: "highlight", ID: highlight.ID.Hex(), Score: match.Score, Snippet: match.Snippet,
+			Item: highlight, createdAt: highlight.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
+// nameFields makes the canonical name searchable, and its translations and
+// aliases a little below it
+func nameFields(names []string) []search.Field {
+	fields := make([]search.Field, len(names))
+	for i, name := range names {
+		fields[i] = search.Field{Name: "name", Text: name, Weight: 2}
+	}
+	fields[0].Weight = 3
+	return fields
+}
+
+// searchClubs matches the cached club list in memory
+func (h *Handler) searchClubs(ctx context.Context, query search.Query) ([]SearchResult, error) {
+	clubs, err := h.Repo.GetClubs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, club := range clubs {
+		match := query.Match(nameFields(clubNames(club)))
+		if match.Score == 0 {
+			continue
+		}
+		results = append(results, SearchResult{
+			Type: "club", ID: club.ID.Hex(), Score: match.Score, Snippet: match.Snippet,
+			Item: club, createdAt: club.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
+// searchLeagues matches the cached league list in memory
+func (h *Handler) searchLeagues(ctx context.Context, query search.Query) ([]SearchResult, error) {
+	leagues, err := h.Repo.GetLeagues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, league := range leagues {
+		fields := nameFields(leagueNames(league))
+		for _, country := range translations(league.Country, league.CountryNames) {
+			fields = append(fields, search.Field{Name: "country", Text: country, Weight: 1})
+		}
+		match := query.Match(fields)
+		if match.Score == 0 {
+			continue
+		}
+		results = append(results, SearchResult{
+			Type: "league", ID: league.ID.Hex(), Score: match.Score, Snippet: match.Snippet,
+			Item: league, createdAt: league.CreatedAt,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// currentSeasonName returns the name of a league's current season, or ""
+// for a league without seasons
+func (h *Handler) currentSeasonName(ctx context.Context, leagueID bson.ObjectID) (string, error) {
+	season, err := h.Repo.FindCurrentSeason(ctx, leagueID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return season.Name, nil
+}
+
+// applySeasonChange keeps a single current season per league, moves the
+// current season's clubs into the league and refreshes the season's table
+func (h *Handler) applySeasonChange(ctx context.Context, season models.Season) error {
+	if season.Current {
+		if err := h.Repo.ClearCurrentSeason(ctx, season.LeagueID, season.ID); err != nil {
+			return err
+		}
+	}
+	moved, err := h.Repo.ApplyCurrentSeason(ctx, season.LeagueID)
+	if err != nil {
+		return err
+	}
+	if len(moved) > 0 {
+		log.Printf("[Seasons] Moved %d club(s) into league %s", len(moved), season.LeagueID.Hex())
+	}
+	h.queueStandings(season.LeagueID, season.Name)
+	return nil
+}
+
+// GetLeagueSeasons lists a league's seasons, latest first
+func (h *Handler) GetLeagueSeasons(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindLeagueByID(ctx, objID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
+	seasons, err := h.Repo.GetSeasons(ctx, bson.M{"league_id": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seasons"})
+		return
+	}
+	current, err := h.currentSeasonName(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seasons"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"league_id":      objID.Hex(),
+		"current_season": current,
+		"seasons":        seasons,
+	})
+}
+
+// ClubSeason is a season a club played in, with its league
+type ClubSeason struct {
+	Season   string       `json:"season"`
+	LeagueID string       `json:"league_id"`
+	League   *TeamSummary `json:"league"`
+}
+
+// GetClubSeasons lists the leagues a club played in per season, latest
+// first, showing promotions and relegations
+func (h *Handler) GetClubSeasons(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindClubByID(ctx, objID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+		return
+	}
+	seasons, err := h.Repo.GetSeasons(ctx, bson.M{"club_ids": objID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seasons"})
+		return
+	}
+	leagues, err := h.Repo.GetLeagues(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching leagues"})
+		return
+	}
+	leagueByID := make(map[bson.ObjectID]models.League, len(leagues))
+	for _, league := range leagues {
+		leagueByID[league.ID] = league
+	}
+
+	history := make([]ClubSeason, len(seasons))
+	for i, season := range seasons {
+		history[i] = ClubSeason{Season: season.Name, LeagueID: season.LeagueID.Hex()}
+		if league, ok := leagueByID[season.LeagueID]; ok {
+			history[i].League = leagueSummary(league, lang)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"club_id": objID.Hex(), "seasons": history})
+}
+
+// --- Admin ---
+
+func (h *Handler) AdminAddSeason(c *gin.Context) {
+	leagueID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var input seasonInput
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindLeagueByID(ctx, leagueID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
+
+	season, errs := input.toSeason(ctx, h, leagueID)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+
+	if err := h.Repo.CreateSeason(ctx, season); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add season"})
+		return
+	}
+	if err := h.applySeasonChange(ctx, season); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update league clubs"})
+		return
+	}
+
+	h.logActivity(c, "Added Season", "season", season.ID.Hex())
+	c.JSON(http.StatusCreated, season)
+}
+
+func (h *Handler) AdminUpdateSeason(c *gin.Context) {
+	leagueID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	id := c.Param("seasonId")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	expectedVersion, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	var input seasonUpdate
+	if !bindStrict(c, &input) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := h.Repo.FindSeasonByID(ctx, objID)
+	if err != nil || current.LeagueID != leagueID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+
+	update, errs := input.toUpdate(ctx, h, current)
+	if len(errs) > 0 {
+		respondValidation(c, errs)
+		return
+	}
+	if len(update) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	version, err := h.Repo.UpdateSeason(ctx, objID, update, expectedVersion)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		latest, err := h.Repo.FindSeasonByID(ctx, objID)
+		if err != nil {
+			respondLookupError(c, err, "Season not found")
+			return
+		}
+		respondVersionConflict(c, latest, latest.Version)
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+		return
+	}
+
+	updated, err := h.Repo.FindSeasonByID(ctx, objID)
+	if err == nil {
+		err = h.applySeasonChange(ctx, *updated)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update league clubs"})
+		return
+	}
+
+	h.logActivity(c, "Updated Season", "season", id)
+	setVersionETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Season updated successfully",
+		"version": version,
+	})
+}
+
+// AdminDeleteSeason removes a season that has no matches. Clubs keep their
+// league.
+func (h *Handler) AdminDeleteSeason(c *gin.Context) {
+	leagueID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	id := c.Param("seasonId")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	season, err := h.Repo.FindSeasonByID(ctx, objID)
+	if err != nil || season.LeagueID != leagueID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	matches, err := h.Repo.GetMatches(ctx, bson.M{"league_id": leagueID, "season": season.Name}, true, 1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching matches"})
+		return
+	}
+	if len(matches) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Season has matches and cannot be deleted"})
+		return
+	}
+
+	err = h.Repo.DeleteSeason(ctx, objID, currentUserID(c))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Season not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Delete failed"})
+		return
+	}
+
+	// Another season may have become current
+	season.Current = false
+	if err := h.applySeasonChange(ctx, *season); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update league clubs"})
+		return
+	}
+
+	h.logActivity(c, "Deleted Season", "season", id)
+	c.JSON(http.StatusOK, gin.H{"message": "Season deleted successfully"})
+}
+
+// maxSeasonClubs caps how many clubs a season can list
+const maxSeasonClubs = 64
+
+// seasonClubIDs resolves a season's clubs, dropping repeats
+func (h *Handler) seasonClubIDs(ctx context.Context, errs ValidationErrors, raw []string) []bson.ObjectID {
+	if len(raw) > maxSeasonClubs {
+		errs.Add("club_ids", "must have at most 64 clubs")
+		return nil
+	}
+	ids := []bson.ObjectID{}
+	for _, id := range h.resolveClubIDs(ctx, errs, "club_ids", raw) {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func checkSeasonDates(errs ValidationErrors, start, end *time.Time) {
+	if start != nil && end != nil && !end.After(*start) {
+		errs.Add("end_date", "must be after start_date")
+	}
+}
+
+// seasonInput is the body for adding a season to a league
+type seasonInput struct {
+	Name      string     `json:"name"`
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	Current   bool       `json:"current"`
+	ClubIDs   []string   `json:"club_ids"`
+}
+
+func (in seasonInput) toSeason(ctx context.Context, h *Handler, leagueID bson.ObjectID) (models.Season, ValidationErrors) {
+	errs := ValidationErrors{}
+
+	checkSeason(errs, "name", &in.Name)
+	switch _, err := h.Repo.FindSeason(ctx, leagueID, in.Name); {
+	case err == nil:
+		errs.Add("name", "season already exists in this league")
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		errs.fail(err)
+	}
+	checkSeasonDates(errs, in.StartDate, in.EndDate)
+
+	now := time.Now()
+	return models.Season{
+		ID:        bson.NewObjectID(),
+		LeagueID:  leagueID,
+		Name:      in.Name,
+		StartDate: in.StartDate,
+		EndDate:   in.EndDate,
+		Current:   in.Current,
+		ClubIDs:   h.seasonClubIDs(ctx, errs, in.ClubIDs),
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}, errs
+}
+
+// checkSeasonMembers checks that both clubs of a match play in the league
+// that season, when the season's clubs are known
+func (h *Handler) checkSeasonMembers(ctx context.Context, errs ValidationErrors, leagueID bson.ObjectID, season string, home, away bson.ObjectID) {
+	record, err := h.Repo.FindSeason(ctx, leagueID, season)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			errs.fail(err)
+		}
+		return
+	}
+	if len(record.ClubIDs) == 0 {
+		return
+	}
+	if !slices.Contains(record.ClubIDs, home) {
+		errs.Add("home_club_id", "club is not in this league for the season")
+	}
+	if !slices.Contains(record.ClubIDs, away) {
+		errs.Add("away_club_id", "club is not in this league for the season")
+	}
+}
+
+// seasonUpdate cannot rename a season, as matches refer to it by name
+type seasonUpdate struct {
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+	Current   *bool      `json:"current"`
+	ClubIDs   *[]string  `json:"club_ids"`
+}
+
+func (in seasonUpdate) toUpdate(ctx context.Context, h *Handler, current *models.Season) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	start, end := current.StartDate, current.EndDate
+	if in.StartDate != nil {
+		start = in.StartDate
+		update["start_date"] = *in.StartDate
+	}
+	if in.EndDate != nil {
+		end = in.EndDate
+		update["end_date"] = *in.EndDate
+	}
+	checkSeasonDates(errs, start, end)
+
+	if in.Current != nil {
+		update["current"] = *in.Current
+	}
+	if in.ClubIDs != nil {
+		update["club_ids"] = h.seasonClubIDs(ctx, errs, *in.ClubIDs)
+	}
+	return update, errs
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fanzone/internal/models"
+)
+
+func TestCheckSeason(t *testing.T) {
+	for season, ok := range map[string]bool{
+		"2024":      true,
+		"2024/25":   true,
+		"2024/2025": false,
+		"24/25":     false,
+		"2024-25":   false,
+		"":          false,
+	} {
+		errs := ValidationErrors{}
+		checkSeason(errs, "season", &season)
+		if (len(errs) == 0) != ok {
+			t.Errorf("checkSeason(%q) = %v; want ok %v", season, errs, ok)
+		}
+	}
+	errs := ValidationErrors{}
+	if checkSeason(errs, "season", nil); len(errs) != 0 {
+		t.Errorf("a missing season is left to the caller, got %v", errs)
+	}
+}
+
+func TestCheckSeasonDates(t *testing.T) {
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	later, same := start.AddDate(0, 9, 0), start
+	tests := []struct {
+		name       string
+		start, end *time.Time
+		ok         bool
+	}{
+		{"end after start", &start, &later, true},
+		{"end on start", &start, &same, false},
+		{"end before start", &later, &start, false},
+		{"open end", &start, nil, true},
+		{"no dates", nil, nil, true},
+	}
+	for _, tt := range tests {
+		errs := ValidationErrors{}
+		checkSeasonDates(errs, tt.start, tt.end)
+		if (len(errs) == 0) != tt.ok || (!tt.ok && errs["end_date"] == "") {
+			t.Errorf("%s: errors %v; want ok %v", tt.name, errs, tt.ok)
+		}
+	}
+}
+
+// Updates check the dates they leave in place, not only the ones they send
+func TestSeasonUpdateDates(t *testing.T) {
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	current := &models.Season{Name: "2024/25", StartDate: &start, EndDate: &end}
+
+	tooLate := end.AddDate(0, 1, 0)
+	if _, errs := (seasonUpdate{StartDate: &tooLate}).toUpdate(context.Background(), nil, current); errs["end_date"] == "" {
+		t.Errorf("start after the stored end: errors %v; want end_date", errs)
+	}
+
+	earlier := start.AddDate(0, -1, 0)
+	yes := true
+	update, errs := (seasonUpdate{StartDate: &earlier, Current: &yes}).toUpdate(context.Background(), nil, current)
+	if len(errs) != 0 {
+		t.Fatalf("errors %v; want none", errs)
+	}
+	if len(update) != 2 || update["start_date"] != earlier || update["current"] != true {
+		t.Errorf("update = %v; want start_date and current only", update)
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+	"fanzone/pkg/worker"
+)
+
+// StandingView is a table row with its club resolved for display
+type StandingView struct {
+	models.StandingRow
+	Club *TeamSummary `json:"club"`
+}
+
+// queueStandings asks the worker to recompute the tables a match change may
+// affect
+func (h *Handler) queueStandings(leagueID bson.ObjectID, season string) {
+	h.Worker.AddTask(worker.Task{
+		Type:    "RECOMPUTE_STANDINGS",
+		Payload: repository.StandingsKey{LeagueID: leagueID, Season: season},
+	})
+}
+
+// GetLeagueStandings returns a league table, for the current season unless
+// one is given
+func (h *Handler) GetLeagueStandings(c *gin.Context) {
+	objID, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	lang, ok := negotiateLanguage(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.Repo.FindLeagueByID(ctx, objID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+		return
+	}
+
+	seasons, err := h.Repo.GetStandingsSeasons(ctx, objID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching standings"})
+		return
+	}
+	season := c.Query("season")
+	if season != "" && !seasonPattern.MatchString(season) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "season must look like 2024 or 2024/25"})
+		return
+	}
+	if season == "" {
+		if season, err = h.currentSeasonName(ctx, objID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seasons"})
+			return
+		}
+	}
+	if season == "" && len(seasons) > 0 {
+		season = seasons[0]
+	}
+	if season == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No standings for this league yet"})
+		return
+	}
+
+	table, err := h.Repo.GetStandings(ctx, objID, season)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// The worker may not have run since the first match was added
+		if _, err = h.Repo.RecomputeStandings(ctx, repository.StandingsKey{LeagueID: objID, Season: season}); err == nil {
+			table, err = h.Repo.GetStandings(ctx, objID, season)
+		}
+		if err == nil {
+			seasons, err = h.Repo.GetStandingsSeasons(ctx, objID)
+		}
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No standings for this season"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching standings"})
+		return
+	}
+
+	clubs, err := h.Repo.GetClubs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching clubs"})
+		return
+	}
+	clubByID := make(map[bson.ObjectID]models.Club, len(clubs))
+	for _, club := range clubs {
+		clubByID[club.ID] = club
+	}
+	rows := make([]StandingView, len(table.Rows))
+	for i, row := range table.Rows {
+		rows[i] = StandingView{StandingRow: row}
+		if club, ok := clubByID[row.ClubID]; ok {
+			rows[i].Club = clubSummary(club, lang)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"league_id":   objID.Hex(),
+		"season":      table.Season,
+		"seasons":     seasons,
+		"tiebreakers": table.Tiebreakers,
+		"rows":        rows,
+		"computed_at": table.ComputedAt,
+	})
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/events"
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+const (
+	feedTopic = "feed"
+	// streamCatchUpLimit caps how many missed items are replayed on resume.
+	// Clients that missed more are told to reload the feed instead.
+	streamCatchUpLimit = 100
+	streamHeartbeat    = 25 * time.Second
+)
+
+// feedEvent is a newly published feed item along with what stream filters
+// need to know about it
+type feedEvent struct {
+	Item FeedItem
+	// Languages the item is readable in; empty means language-neutral
+	Languages []string
+}
+
+func newsEvent(n models.Content) feedEvent {
+	return feedEvent{Item: newsFeedItem(n), Languages: availableLanguages(n.Title)}
+}
+
+func highlightEvent(hl models.Highlight) feedEvent {
+	return feedEvent{Item: highlightFeedItem(hl)}
+}
+
+func entryEvent(entry repository.FeedEntry) (feedEvent, error) {
+	if entry.Type == "highlight" {
+		var hl models.Highlight
+		err := bson.Unmarshal(entry.Raw, &hl)
+		return highlightEvent(hl), err
+	}
+	var n models.Content
+	err := bson.Unmarshal(entry.Raw, &n)
+	return newsEvent(n), err
+}
+
+// streamEventID identifies an item the same way on every instance, so the
+// bus can drop the copy relayed from the change stream
+func streamEventID(item FeedItem) string {
+	return encodeFeedCursor(item.CreatedAt, item.ID)
+}
+
+func (h *Handler) publishFeedEvent(ev feedEvent) {
+	h.Events.Publish(events.Event{ID: streamEventID(ev.Item), Topic: feedTopic, Payload: ev})
+}
+
+// RelayFeedInserts publishes items inserted by other server instances until
+// ctx is done. Without a replica set change streams are unavailable and
+// streams only see items published on this instance.
+func (h *Handler) RelayFeedInserts(ctx context.Context) {
+	backoff := time.Second
+	for {
+		err := h.Repo.WatchFeedInserts(ctx, func(entry repository.FeedEntry) {
+			ev, err := entryEvent(entry)
+			if err != nil {
+				log.Printf("[Stream] Failed to decode inserted %s: %v", entry.Type, err)
+				return
+			}
+			h.publishFeedEvent(ev)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[Stream] Change stream stopped, retrying in %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 5*time.Minute)
+	}
+}
+
+// streamFilter narrows a stream to a club, a league and/or a language
+type streamFilter struct {
+	ClubID        bson.ObjectID
+	LeagueID      bson.ObjectID
+	LeagueClubIDs []bson.ObjectID
+	Language      string
+}
+
+func (f streamFilter) matches(ev feedEvent) bool {
+	item := ev.Item
+	if !f.ClubID.IsZero() {
+		club := f.ClubID.Hex()
+		if item.ClubID != club && !slices.Contains(item.ClubIDs, club) {
+			return false
+		}
+	}
+	if !f.LeagueID.IsZero() {
+		inLeague := slices.Contains(item.LeagueIDs, f.LeagueID.Hex())
+		for _, id := range f.LeagueClubIDs {
+			club := id.Hex()
+			if item.ClubID == club || slices.Contains(item.ClubIDs, club) {
+				inLeague = true
+			}
+		}
+		if !inLeague {
+			return false
+		}
+	}
+	if f.Language != "" && len(ev.Languages) > 0 && !slices.Contains(ev.Languages, f.Language) {
+		return false
+	}
+	return true
+}
+
+// query builds the database filters for catching up; language is checked by
+// matches afterwards
+func (f streamFilter) query() (bson.M, bson.M) {
+	var news, highlights []bson.M
+	if !f.ClubID.IsZero() {
+		n, hl := targetingFilters([]bson.ObjectID{f.ClubID}, nil)
+		news, highlights = append(news, n), append(highlights, hl)
+	}
+	if !f.LeagueID.IsZero() {
+		n, hl := targetingFilters(f.LeagueClubIDs, []bson.ObjectID{f.LeagueID})
+		news, highlights = append(news, n), append(highlights, hl)
+	}
+	if len(news) == 0 {
+		return bson.M{}, bson.M{}
+	}
+	return bson.M{"$and": news}, bson.M{"$and": highlights}
+}
+
+// StreamFeed pushes new feed items as Server-Sent Events. A client that
+// reconnects with Last-Event-ID first receives what it missed.
+func (h *Handler) StreamFeed(c *gin.Context) {
+	filter, ok := h.parseStreamFilter(c)
+	if !ok {
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var since *repository.FeedCursor
+	if lastEventID != "" {
+		cursor, err := decodeFeedCursor(lastEventID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+		since = cursor
+	}
+
+	// Subscribe before catching up so nothing published meanwhile is lost
+	sub := h.Events.Subscribe()
+	defer sub.Close()
+
+	startStream(c)
+
+	sent := map[string]bool{}
+	if since != nil {
+		if !h.catchUpStream(c, filter, *since, sent) {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			ev, isFeed := event.Payload.(feedEvent)
+			if event.Topic != feedTopic || !isFeed || sent[event.ID] || !filter.matches(ev) {
+				continue
+			}
+			writeFeedEvent(c, event.ID, ev.Item)
+		case <-heartbeat.C:
+			c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// catchUpStream replays items published after since, recording what it sent.
+// It reports false if the stream should end.
+func (h *Handler) catchUpStream(c *gin.Context, filter streamFilter, since repository.FeedCursor, sent map[string]bool) bool {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	newsFilter, highlightFilter := filter.query()
+	entries, err := h.Repo.GetFeedSince(ctx, newsFilter, highlightFilter, since, streamCatchUpLimit+1)
+	if err != nil {
+		c.Render(-1, sse.Event{Event: "error", Data: gin.H{"error": "Error fetching missed items"}})
+		return false
+	}
+	replayMissed(c, filter, entries, sent)
+	return true
+}
+
+// replayMissed writes the missed entries the filter accepts, or a resync
+// event when there are more than streamCatchUpLimit of them
+func replayMissed(c *gin.Context, filter streamFilter, entries []repository.FeedEntry, sent map[string]bool) {
+	// Too much was missed to replay; the client should reload the feed
+	if len(entries) > streamCatchUpLimit {
+		c.Render(-1, sse.Event{Event: "resync", Data: gin.H{"reason": "too many missed items"}})
+		return
+	}
+
+	for _, entry := range entries {
+		ev, err := entryEvent(entry)
+		if err != nil || !filter.matches(ev) {
+			continue
+		}
+		id := streamEventID(ev.Item)
+		sent[id] = true
+		writeFeedEvent(c, id, ev.Item)
+	}
+}
+
+func writeFeedEvent(c *gin.Context, id string, item FeedItem) {
+	c.Render(-1, sse.Event{Id: id, Event: "feed_item", Data: item})
+	c.Writer.Flush()
+}
+
+func (h *Handler) parseStreamFilter(c *gin.Context) (streamFilter, bool) {
+	var filter streamFilter
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if raw := c.Query("club_id"); raw != "" {
+		id, err := bson.ObjectIDFromHex(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid club ID"})
+			return filter, false
+		}
+		if _, err := h.Repo.FindClubByID(ctx, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+			return filter, false
+		}
+		filter.ClubID = id
+	}
+
+	if raw := c.Query("league_id"); raw != "" {
+		id, err := bson.ObjectIDFromHex(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league ID"})
+			return filter, false
+		}
+		if _, err := h.Repo.FindLeagueByID(ctx, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+			return filter, false
+		}
+		clubIDs, err := h.Repo.GetClubIDsByLeagues(ctx, []bson.ObjectID{id})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching league clubs"})
+			return filter, false
+		}
+		filter.LeagueID = id
+		filter.LeagueClubIDs = clubIDs
+	}
+
+	if lang := c.Query("lang"); lang != "" {
+		if lang != "en" && lang != "am" && lang != "om" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lang must be one of: en, am, om"})
+			return filter, false
+		}
+		filter.Language = lang
+	}
+	return filter, true
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+func feedEntry(t *testing.T, kind string, doc interface{}) repository.FeedEntry {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repository.FeedEntry{Type: kind, Raw: raw}
+}
+
+func TestStreamFilterMatches(t *testing.T) {
+	club, leagueClub, league := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	news := func(clubID bson.ObjectID, title models.MultiLangString) feedEvent {
+		return newsEvent(models.Content{ID: bson.NewObjectID(), ClubID: clubID, Title: title})
+	}
+	english := models.MultiLangString{EN: "Derby day"}
+
+	tests := []struct {
+		name   string
+		filter streamFilter
+		event  feedEvent
+		want   bool
+	}{
+		{"no filter", streamFilter{}, news(club, english), true},
+		{"club news", streamFilter{ClubID: club}, news(club, english), true},
+		{"other club", streamFilter{ClubID: club}, news(leagueClub, english), false},
+		{"club highlight", streamFilter{ClubID: club}, highlightEvent(models.Highlight{ClubIDs: []bson.ObjectID{leagueClub, club}}), true},
+		{"league-wide news", streamFilter{LeagueID: league}, newsEvent(models.Content{LeagueIDs: []bson.ObjectID{league}, Title: english}), true},
+		{"news of a club in the league", streamFilter{LeagueID: league, LeagueClubIDs: []bson.ObjectID{leagueClub}}, news(leagueClub, english), true},
+		{"news outside the league", streamFilter{LeagueID: league, LeagueClubIDs: []bson.ObjectID{leagueClub}}, news(club, english), false},
+		{"untranslated news", streamFilter{Language: "am"}, news(club, english), false},
+		{"translated news", streamFilter{Language: "am"}, news(club, models.MultiLangString{EN: "Derby day", AM: "የደርቢ ቀን"}), true},
+		{"highlights have no language", streamFilter{Language: "am"}, highlightEvent(models.Highlight{}), true},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(tt.event); got != tt.want {
+			t.Errorf("%s: matches = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReplayMissed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	club, other := bson.NewObjectID(), bson.NewObjectID()
+	at := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	entries := func(n int, clubID bson.ObjectID) []repository.FeedEntry {
+		out := make([]repository.FeedEntry, n)
+		for i := range out {
+			out[i] = feedEntry(t, "news", models.Content{
+				ID:        bson.NewObjectID(),
+				ClubID:    clubID,
+				Title:     models.MultiLangString{EN: "News"},
+				CreatedAt: at.Add(time.Duration(i) * time.Minute),
+			})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		entries []repository.FeedEntry
+		items   int
+		resync  bool
+	}{
+		{"nothing missed", nil, 0, false},
+		{"replays what was missed", entries(3, club), 3, false},
+		{"skips other clubs", append(entries(2, club), entries(2, other)...), 2, false},
+		{"replays up to the limit", entries(streamCatchUpLimit, club), streamCatchUpLimit, false},
+		{"too many missed", entries(streamCatchUpLimit+1, club), 0, true},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		sent := map[string]bool{}
+
+		replayMissed(c, streamFilter{ClubID: club}, tt.entries, sent)
+		body := w.Body.String()
+		if got := strings.Count(body, "event:feed_item"); got != tt.items || len(sent) != tt.items {
+			t.Errorf("%s: %d items written, %d recorded; want %d", tt.name, got, len(sent), tt.items)
+		}
+		if got := strings.Contains(body, "event:resync"); got != tt.resync {
+			t.Errorf("%s: resync %v; want %v", tt.name, got, tt.resync)
+		}
+	}
+}
+
+// Event IDs are feed cursors, so a reconnecting client's Last-Event-ID
+// resumes right after the last item it saw
+func TestStreamEventID(t *testing.T) {
+	item := newsFeedItem(models.Content{ID: bson.NewObjectID(), CreatedAt: time.UnixMilli(1705312800000)})
+	cursor, err := decodeFeedCursor(streamEventID(item))
+	if err != nil || cursor.ID.Hex() != item.ID || !cursor.CreatedAt.Equal(item.CreatedAt) {
+		t.Errorf("event ID decodes to %+v, %v; want %s at %v", cursor, err, item.ID, item.CreatedAt)
+	}
+}
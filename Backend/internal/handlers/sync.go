@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/repository"
+)
+
+// syncSafetyWindow is subtracted from the issued sync token so that writes
+// committing while a sync runs are picked up by the next one. Clients apply
+// changes as upserts, so the overlap is harmless.
+const syncSafetyWindow = 5 * time.Second
+
+// syncCollections maps the keys of the sync response to their collections
+var syncCollections = map[string]string{
+	"content":     "content",
+	"highlights":  "highlights",
+	"clubs":       "clubs",
+	"leagues":     "leagues",
+	"watch_links": "watch_links",
+	"matches":     "matches",
+	"seasons":     "seasons",
+	"players":     "players",
+	"squads":      "squads",
+}
+
+// syncPageSize caps the documents a single sync response returns per
+// collection. Larger syncs continue with the next cursor.
+const syncPageSize = 200
+
+// syncCursor carries a paged sync to its next page: the sync it belongs to,
+// the token to hand out once it is done and where each unfinished
+// collection stopped.
+type syncCursor struct {
+	Since int64                `json:"since"`
+	Full  bool                 `json:"full"`
+	Token int64                `json:"token"`
+	After map[string]syncAfter `json:"after"`
+}
+
+type syncAfter struct {
+	UpdatedAt int64  `json:"t"`
+	ID        string `json:"id"`
+}
+
+// GetSyncChanges returns everything created, updated and deleted since the
+// client's last sync token. Without a token, or when the token is older than
+// the trash retention (so tombstones may be gone), it returns a full sync and
+// the client should replace its cache. Each collection returns at most
+// syncPageSize documents; while has_more is set the client fetches the rest
+// with the next cursor and only then stores the sync token.
+func (h *Handler) GetSyncChanges(c *gin.Context) {
+	var (
+		since    time.Time
+		fullSync bool
+		issued   time.Time
+		after    map[string]*repository.SyncPosition
+	)
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := decodeSyncCursor(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sync cursor"})
+			return
+		}
+		if cursor.Since > 0 {
+			since = time.UnixMilli(cursor.Since)
+		}
+		fullSync = cursor.Full
+		issued = time.UnixMilli(cursor.Token)
+		after = make(map[string]*repository.SyncPosition, len(cursor.After))
+		for key, position := range cursor.After {
+			id, err := bson.ObjectIDFromHex(position.ID)
+			if _, known := syncCollections[key]; !known || err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sync cursor"})
+				return
+			}
+			after[key] = &repository.SyncPosition{UpdatedAt: time.UnixMilli(position.UpdatedAt), ID: id}
+		}
+	} else {
+		startedAt := time.Now()
+		if token := c.Query("since"); token != "" {
+			t, err := decodeSyncToken(token)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sync token"})
+				return
+			}
+			since = t
+		}
+
+		fullSync = isFullSync(since, startedAt, h.Config.TrashRetention)
+		if fullSync {
+			since = time.Time{}
+		}
+		issued = startedAt.Add(-syncSafetyWindow)
+		after = make(map[string]*repository.SyncPosition, len(syncCollections))
+		for key := range syncCollections {
+			after[key] = nil
+		}
+	}
+	firstPage := c.Query("cursor") == ""
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	changes := gin.H{}
+	next := map[string]syncAfter{}
+	for key, collection := range syncCollections {
+		created := []bson.M{}
+		updated := []bson.M{}
+		deleted := []bson.ObjectID{}
+		changes[key] = gin.H{
+			"created": created,
+			"updated": updated,
+			"deleted": deleted,
+		}
+
+		position, pending := after[key]
+		if !pending {
+			continue
+		}
+
+		// One extra document tells whether the collection has another page
+		docs, err := h.Repo.GetChangedSince(ctx, collection, since, position, syncPageSize+1)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching changes"})
+			return
+		}
+		if len(docs) > syncPageSize {
+			docs = docs[:syncPageSize]
+			last := docs[len(docs)-1]
+			updatedAt, _ := last["updated_at"].(bson.DateTime)
+			id, _ := last["_id"].(bson.ObjectID)
+			next[key] = syncAfter{UpdatedAt: int64(updatedAt), ID: id.Hex()}
+		}
+
+		for _, doc := range docs {
+			// Match the "id" key the rest of the API uses
+			doc["id"] = doc["_id"]
+			delete(doc, "_id")
+
+			if fullSync || createdAfter(doc, since) {
+				created = append(created, doc)
+			} else {
+				updated = append(updated, doc)
+			}
+		}
+
+		// Deletions are IDs only and are sent whole with the first page
+		if !fullSync && firstPage {
+			deleted, err = h.Repo.GetDeletedSince(ctx, collection, since)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching deletions"})
+				return
+			}
+		}
+
+		changes[key] = gin.H{
+			"created": created,
+			"updated": updated,
+			"deleted": deleted,
+		}
+	}
+
+	response := gin.H{
+		"full_sync": fullSync,
+		"has_more":  len(next) > 0,
+		"changes":   changes,
+	}
+	if len(next) > 0 {
+		cursor := syncCursor{Full: fullSync, Token: issued.UnixMilli(), After: next}
+		if !since.IsZero() {
+			cursor.Since = since.UnixMilli()
+		}
+		response["next"] = encodeSyncCursor(cursor)
+	} else {
+		response["sync_token"] = encodeSyncToken(issued)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// isFullSync reports whether a sync from since has to start over, either
+// because the client has no token or because tombstones it needs may have
+// been purged from the trash.
+func isFullSync(since, now time.Time, retention time.Duration) bool {
+	return since.IsZero() || since.Before(now.Add(-retention))
+}
+
+func createdAfter(doc bson.M, since time.Time) bool {
+	createdAt, ok := doc["created_at"].(bson.DateTime)
+	return ok && createdAt.Time().After(since)
+}
+
+func encodeSyncToken(t time.Time) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(t.UnixMilli(), 10)))
+}
+
+func decodeSyncToken(token string) (time.Time, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ms, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+func encodeSyncCursor(cursor syncCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSyncCursor(token string) (syncCursor, error) {
+	var cursor syncCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return cursor, err
+	}
+	if len(cursor.After) == 0 {
+		return cursor, errors.New("sync cursor has nothing left to fetch")
+	}
+	return cursor, nil
+}
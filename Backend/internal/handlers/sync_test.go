@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestSyncCursor(t *testing.T) {
+	cursor := syncCursor{
+		Since: 1705312000000,
+		Token: 1705398400000,
+		After: map[string]syncAfter{"content": {UpdatedAt: 1705312500000, ID: "507f1f77bcf86cd799439011"}},
+	}
+	got, err := decodeSyncCursor(encodeSyncCursor(cursor))
+	if err != nil || !reflect.DeepEqual(got, cursor) {
+		t.Errorf("round trip = %+v, %v; want %+v", got, err, cursor)
+	}
+
+	done := encodeSyncCursor(syncCursor{Token: 1705398400000})
+	for _, token := range []string{"", "not base64!", "bm90IGpzb24", done} {
+		if _, err := decodeSyncCursor(token); err == nil {
+			t.Errorf("decodeSyncCursor(%q) accepted", token)
+		}
+	}
+}
+
+func TestIsFullSync(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+	tests := []struct {
+		name  string
+		since time.Time
+		full  bool
+	}{
+		{"first launch", time.Time{}, true},
+		{"recent token", now.Add(-time.Hour), false},
+		{"token at the retention edge", now.Add(-retention), false},
+		{"token older than the trash", now.Add(-retention - time.Minute), true},
+	}
+	for _, tt := range tests {
+		if got := isFullSync(tt.since, now, retention); got != tt.full {
+			t.Errorf("%s: isFullSync = %v; want %v", tt.name, got, tt.full)
+		}
+	}
+}
+
+func TestSyncToken(t *testing.T) {
+	issued := time.UnixMilli(1705312800000)
+	got, err := decodeSyncToken(encodeSyncToken(issued))
+	if err != nil || !got.Equal(issued) {
+		t.Errorf("round trip = %v, %v; want %v", got, err, issued)
+	}
+	for _, token := range []string{"not base64!", "bm90IGEgbnVtYmVy"} {
+		if _, err := decodeSyncToken(token); err == nil {
+			t.Errorf("decodeSyncToken(%q) accepted", token)
+		}
+	}
+}
+
+func TestCreatedAfter(t *testing.T) {
+	since := time.UnixMilli(1705312800000)
+	tests := []struct {
+		name    string
+		doc     bson.M
+		created bool
+	}{
+		{"created after the token", bson.M{"created_at": bson.NewDateTimeFromTime(since.Add(time.Minute))}, true},
+		{"created before, updated after", bson.M{"created_at": bson.NewDateTimeFromTime(since.Add(-time.Minute))}, false},
+		{"created at the token", bson.M{"created_at": bson.NewDateTimeFromTime(since)}, false},
+		{"no created_at", bson.M{}, false},
+	}
+	for _, tt := range tests {
+		if got := createdAfter(tt.doc, since); got != tt.created {
+			t.Errorf("%s: createdAfter = %v; want %v", tt.name, got, tt.created)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/repository"
+)
+
+// trashCollections maps the entity types accepted by the trash endpoints to
+// their collections
+var trashCollections = map[string]string{
+	"clubs":       "clubs",
+	"leagues":     "leagues",
+	"content":     "content",
+	"highlights":  "highlights",
+	"watch-links": "watch_links",
+	"matches":     "matches",
+	"seasons":     "seasons",
+	"players":     "players",
+	"squads":      "squads",
+}
+
+func (h *Handler) AdminGetTrash(c *gin.Context) {
+	entityType := c.Query("type")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if entityType != "" {
+		collection, ok := trashCollections[entityType]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown type. Supported: clubs, leagues, content, highlights, watch-links, matches, seasons, players, squads"})
+			return
+		}
+
+		items, err := h.Repo.GetTrash(ctx, collection)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching trash"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"type":  entityType,
+			"items": items,
+			"total": len(items),
+		})
+		return
+	}
+
+	trash := gin.H{}
+	total := 0
+	for entity, collection := range trashCollections {
+		items, err := h.Repo.GetTrash(ctx, collection)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching trash"})
+			return
+		}
+		if items == nil {
+			items = []bson.M{}
+		}
+		trash[entity] = items
+		total += len(items)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trash": trash,
+		"total": total,
+	})
+}
+
+func (h *Handler) AdminRestoreFromTrash(c *gin.Context) {
+	entityType := c.Param("type")
+	collection, ok := trashCollections[entityType]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown type. Supported: clubs, leagues, content, highlights, watch-links, matches, seasons, players, squads"})
+		return
+	}
+
+	id := c.Param("id")
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = h.Repo.RestoreFromTrash(ctx, collection, objID)
+	var missingErr *repository.MissingParentsError
+	if errors.As(err, &missingErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Item refers to deleted items; restore them first",
+			"missing": missingErr.Missing,
+		})
+		return
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Restore failed"})
+		return
+	}
+
+	if collection == "matches" {
+		if match, err := h.Repo.FindMatchByID(ctx, objID); err == nil {
+			h.queueStandings(match.LeagueID, match.Season)
+		}
+	}
+	if collection == "seasons" {
+		if season, err := h.Repo.FindSeasonByID(ctx, objID); err == nil {
+			if err := h.applySeasonChange(ctx, *season); err != nil {
+				log.Printf("[Trash] Failed to apply restored season %s: %v", id, err)
+			}
+		}
+	}
+
+	h.logActivity(c, "Restored From Trash", entityType, id)
+	c.JSON(http.StatusOK, gin.H{"message": "Item restored successfully"})
+}
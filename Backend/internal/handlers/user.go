@@ -69,10 +69,11 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 	}
 
 	var input struct {
-		Name            *string `json:"name"`
-		Language        *string `json:"language"`
-		FavClubID       *string `json:"fav_club_id"`
-		ProfileImageURL *string `json:"profile_image_url"`
+		Name                *string   `json:"name"`
+		Language            *string   `json:"language"`
+		FavClubID           *string   `json:"fav_club_id"`
+		ProfileImageURL     *string   `json:"profile_image_url"`
+		PreferredCategories *[]string `json:"preferred_categories"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -103,6 +104,15 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		}
 		updateFields["fav_club_id"] = clubObjID
 	}
+	if input.PreferredCategories != nil {
+		for _, category := range *input.PreferredCategories {
+			if !isAllowedCategory(category) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category: " + category})
+				return
+			}
+		}
+		updateFields["preferred_categories"] = *input.PreferredCategories
+	}
 
 	if len(updateFields) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
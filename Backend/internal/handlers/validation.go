@@ -0,0 +1,572 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/standings"
+)
+
+// generalClubID is what the dashboard sends for content not tied to a club
+const generalClubID = "GENERAL"
+
+// isGeneralClubID reports whether a club_id means no club. Besides "" and
+// GENERAL this takes the zero ID, which is how General content is written
+// out and so what the dashboard sends back when it is edited.
+func isGeneralClubID(raw string) bool {
+	return raw == "" || raw == generalClubID || raw == bson.NilObjectID.Hex()
+}
+
+// ValidationErrors maps a request field to what is wrong with it
+type ValidationErrors map[string]string
+
+// lookupFailed is the key a failed database lookup is recorded under. It is
+// not a field, so respondValidation answers it with a server error.
+const lookupFailed = ""
+
+func (v ValidationErrors) Add(field, message string) {
+	if _, exists := v[field]; !exists {
+		v[field] = message
+	}
+}
+
+// fail records a lookup that could not be answered
+func (v ValidationErrors) fail(err error) {
+	v.Add(lookupFailed, err.Error())
+}
+
+// lookup records the result of loading a referenced document: a missing one
+// is a field error, any other failure is recorded with fail
+func (v ValidationErrors) lookup(field, missing string, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, mongo.ErrNoDocuments):
+		v.Add(field, missing)
+	default:
+		v.fail(err)
+	}
+}
+
+func respondValidation(c *gin.Context, errs ValidationErrors) {
+	if reason, failed := errs[lookupFailed]; failed {
+		log.Printf("Validation lookup failed: %s", reason)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating request"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":  "Validation failed",
+		"fields": errs,
+	})
+}
+
+// bindStrict decodes the JSON body into dst, rejecting fields dst does not
+// declare. It writes the error response itself and reports whether binding
+// succeeded.
+func bindStrict(c *gin.Context, dst interface{}) bool {
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(dst)
+	if err == nil {
+		return true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request body is required"})
+	case errors.As(err, &typeErr):
+		respondValidation(c, ValidationErrors{typeErr.Field: "must be of type " + typeErr.Type.String()})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		respondValidation(c, ValidationErrors{field: "unknown field"})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+	return false
+}
+
+func isValidURL(raw string) bool {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isAllowedCategory(category string) bool {
+	for _, allowed := range models.ContentCategories {
+		if category == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRequiredString(errs ValidationErrors, field string, value *string) {
+	if value != nil && strings.TrimSpace(*value) == "" {
+		errs.Add(field, "must not be empty")
+	}
+}
+
+func checkURL(errs ValidationErrors, field string, value *string) {
+	if value != nil && !isValidURL(*value) {
+		errs.Add(field, "must be a valid http(s) URL")
+	}
+}
+
+func checkMultiLang(errs ValidationErrors, field string, value *models.MultiLangString) {
+	if value != nil && strings.TrimSpace(value.EN+value.AM+value.OM) == "" {
+		errs.Add(field, "must have at least one translation")
+	}
+}
+
+const (
+	maxAliases     = 20
+	maxAliasLength = 100
+)
+
+// cleanAliases trims aliases and drops blank and repeated ones
+func cleanAliases(errs ValidationErrors, field string, aliases []string) []string {
+	if len(aliases) > maxAliases {
+		errs.Add(field, "must have at most 20 aliases")
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var cleaned []string
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		if len([]rune(alias)) > maxAliasLength {
+			errs.Add(field, "aliases must be at most 100 characters")
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		cleaned = append(cleaned, alias)
+	}
+	return cleaned
+}
+
+// listUpdate is the update value for a list; an empty list removes it
+func listUpdate(values []string) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// cleanTiebreakers checks a league's tiebreak rules, which apply in order
+func cleanTiebreakers(errs ValidationErrors, field string, rules []string) []string {
+	var cleaned []string
+	for _, rule := range rules {
+		if !slices.Contains(standings.Tiebreakers, rule) {
+			errs.Add(field, "must only contain: "+strings.Join(standings.Tiebreakers, ", "))
+			return nil
+		}
+		if slices.Contains(cleaned, rule) {
+			errs.Add(field, "must not repeat a rule")
+			return nil
+		}
+		cleaned = append(cleaned, rule)
+	}
+	return cleaned
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+const maxColors = 4
+
+// cleanColors upper-cases "#rrggbb" colours and rejects anything else
+func cleanColors(errs ValidationErrors, field string, colors []string) []string {
+	if len(colors) > maxColors {
+		errs.Add(field, "must have at most 4 colours")
+		return nil
+	}
+	cleaned := make([]string, 0, len(colors))
+	for _, color := range colors {
+		color = strings.TrimSpace(color)
+		if !hexColor.MatchString(color) {
+			errs.Add(field, "colours must look like #RRGGBB")
+			continue
+		}
+		cleaned = append(cleaned, strings.ToUpper(color))
+	}
+	return cleaned
+}
+
+func checkFoundedYear(errs ValidationErrors, field string, year *int) {
+	if year != nil && *year != 0 && (*year < 1850 || *year > time.Now().Year()) {
+		errs.Add(field, "must be a year between 1850 and now")
+	}
+}
+
+// optionalText is the update value for optional translated metadata; a
+// value with no translation removes the field
+func optionalText(value *models.MultiLangString) interface{} {
+	trimmed := models.MultiLangString{
+		EN: strings.TrimSpace(value.EN),
+		AM: strings.TrimSpace(value.AM),
+		OM: strings.TrimSpace(value.OM),
+	}
+	if trimmed.EN+trimmed.AM+trimmed.OM == "" {
+		return nil
+	}
+	return trimmed
+}
+
+// optionalValue is the update value for optional metadata; a zero value
+// removes the field
+func optionalValue[T comparable](value T) interface{} {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return value
+}
+
+// resolveLeagueID parses a league ID and checks that the league exists
+func (h *Handler) resolveLeagueID(ctx context.Context, errs ValidationErrors, field, raw string) bson.ObjectID {
+	id, err := bson.ObjectIDFromHex(raw)
+	if err != nil {
+		errs.Add(field, "must be a valid league ID")
+		return bson.ObjectID{}
+	}
+	_, err = h.Repo.FindLeagueByID(ctx, id)
+	errs.lookup(field, "league does not exist", err)
+	return id
+}
+
+func (h *Handler) resolveLeagueIDs(ctx context.Context, errs ValidationErrors, field string, raw []string) []bson.ObjectID {
+	ids := make([]bson.ObjectID, 0, len(raw))
+	for _, idStr := range raw {
+		ids = append(ids, h.resolveLeagueID(ctx, errs, field, idStr))
+	}
+	return ids
+}
+
+// resolveClubID parses a club ID and checks that the club exists
+func (h *Handler) resolveClubID(ctx context.Context, errs ValidationErrors, field, raw string) bson.ObjectID {
+	id, err := bson.ObjectIDFromHex(raw)
+	if err != nil {
+		errs.Add(field, "must be a valid club ID")
+		return bson.ObjectID{}
+	}
+	_, err = h.Repo.FindClubByID(ctx, id)
+	errs.lookup(field, "club does not exist", err)
+	return id
+}
+
+func (h *Handler) resolveClubIDs(ctx context.Context, errs ValidationErrors, field string, raw []string) []bson.ObjectID {
+	ids := make([]bson.ObjectID, 0, len(raw))
+	for _, idStr := range raw {
+		ids = append(ids, h.resolveClubID(ctx, errs, field, idStr))
+	}
+	return ids
+}
+
+// resolveMatchID parses a match ID and checks that the match exists
+func (h *Handler) resolveMatchID(ctx context.Context, errs ValidationErrors, field, raw string) bson.ObjectID {
+	id, err := bson.ObjectIDFromHex(raw)
+	if err != nil {
+		errs.Add(field, "must be a valid match ID")
+		return bson.ObjectID{}
+	}
+	_, err = h.Repo.FindMatchByID(ctx, id)
+	errs.lookup(field, "match does not exist", err)
+	return id
+}
+
+var seasonPattern = regexp.MustCompile(`^\d{4}(/\d{2})?$`)
+
+func checkSeason(errs ValidationErrors, field string, season *string) {
+	if season != nil && !seasonPattern.MatchString(*season) {
+		errs.Add(field, "must look like 2024 or 2024/25")
+	}
+}
+
+// resolvePlayerID parses a player ID and checks that the player exists
+func (h *Handler) resolvePlayerID(ctx context.Context, errs ValidationErrors, field, raw string) bson.ObjectID {
+	id, err := bson.ObjectIDFromHex(raw)
+	if err != nil {
+		errs.Add(field, "must be a valid player ID")
+		return bson.ObjectID{}
+	}
+	_, err = h.Repo.FindPlayerByID(ctx, id)
+	errs.lookup(field, "player does not exist", err)
+	return id
+}
+
+// maxPlayerTags caps how many players one item or squad can list
+const maxPlayerTags = 60
+
+// resolvePlayerIDs resolves a list of players, dropping repeats
+func (h *Handler) resolvePlayerIDs(ctx context.Context, errs ValidationErrors, field string, raw []string) []bson.ObjectID {
+	if len(raw) > maxPlayerTags {
+		errs.Add(field, "must have at most 60 players")
+		return nil
+	}
+	ids := []bson.ObjectID{}
+	for _, idStr := range raw {
+		id := h.resolvePlayerID(ctx, errs, field, idStr)
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// --- Partial update DTOs ---
+//
+// Nil fields are left untouched. toUpdate validates the present fields and
+// returns the document for the repository's Update methods, where a nil value
+// removes the field. Matches, seasons, players, squads and watch links keep
+// theirs next to their handlers.
+
+type clubUpdate struct {
+	Name        *string                 `json:"name"`
+	Names       *models.MultiLangString `json:"names"`
+	ShortNames  *models.MultiLangString `json:"short_names"`
+	Aliases     *[]string               `json:"aliases"`
+	LogoURL     *string                 `json:"logo_url"`
+	LeagueID    *string                 `json:"league_id"`
+	Colors      *[]string               `json:"colors"`
+	FoundedYear *int                    `json:"founded_year"`
+	Stadium     *models.MultiLangString `json:"stadium"`
+	Website     *string                 `json:"website"`
+}
+
+func (in clubUpdate) toUpdate(ctx context.Context, h *Handler) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkRequiredString(errs, "name", in.Name)
+	checkURL(errs, "logo_url", in.LogoURL)
+
+	if in.Name != nil {
+		update["name"] = strings.TrimSpace(*in.Name)
+	}
+	if in.Aliases != nil {
+		update["aliases"] = listUpdate(cleanAliases(errs, "aliases", *in.Aliases))
+	}
+	if in.LogoURL != nil {
+		update["logo_url"] = *in.LogoURL
+	}
+	if in.LeagueID != nil {
+		update["league_id"] = h.resolveLeagueID(ctx, errs, "league_id", *in.LeagueID)
+	}
+	if in.Stadium != nil {
+		update["stadium"] = optionalText(in.Stadium)
+	}
+	in.metadata().apply(errs, update)
+	return update, errs
+}
+
+type leagueUpdate struct {
+	Name         *string                 `json:"name"`
+	Names        *models.MultiLangString `json:"names"`
+	ShortNames   *models.MultiLangString `json:"short_names"`
+	Aliases      *[]string               `json:"aliases"`
+	LogoURL      *string                 `json:"logo_url"`
+	Country      *string                 `json:"country"`
+	CountryNames *models.MultiLangString `json:"country_names"`
+	Colors       *[]string               `json:"colors"`
+	FoundedYear  *int                    `json:"founded_year"`
+	Website      *string                 `json:"website"`
+	Tiebreakers  *[]string               `json:"tiebreakers"`
+}
+
+func (in leagueUpdate) toUpdate(ctx context.Context, h *Handler) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkRequiredString(errs, "name", in.Name)
+	checkURL(errs, "logo_url", in.LogoURL)
+
+	if in.Name != nil {
+		update["name"] = strings.TrimSpace(*in.Name)
+	}
+	if in.Aliases != nil {
+		update["aliases"] = listUpdate(cleanAliases(errs, "aliases", *in.Aliases))
+	}
+	if in.LogoURL != nil {
+		update["logo_url"] = *in.LogoURL
+	}
+	if in.Country != nil {
+		update["country"] = strings.TrimSpace(*in.Country)
+	}
+	if in.CountryNames != nil {
+		update["country_names"] = optionalText(in.CountryNames)
+	}
+	if in.Tiebreakers != nil {
+		update["tiebreakers"] = listUpdate(cleanTiebreakers(errs, "tiebreakers", *in.Tiebreakers))
+	}
+	in.metadata().apply(errs, update)
+	return update, errs
+}
+
+// metadataUpdate holds the descriptive fields clubs and leagues share
+type metadataUpdate struct {
+	Names       *models.MultiLangString
+	ShortNames  *models.MultiLangString
+	Colors      *[]string
+	FoundedYear *int
+	Website     *string
+}
+
+func (in clubUpdate) metadata() metadataUpdate {
+	return metadataUpdate{in.Names, in.ShortNames, in.Colors, in.FoundedYear, in.Website}
+}
+
+func (in leagueUpdate) metadata() metadataUpdate {
+	return metadataUpdate{in.Names, in.ShortNames, in.Colors, in.FoundedYear, in.Website}
+}
+
+// apply validates the present fields and adds them to update. Empty values
+// remove the field.
+func (in metadataUpdate) apply(errs ValidationErrors, update bson.M) {
+	checkFoundedYear(errs, "founded_year", in.FoundedYear)
+	if in.Website != nil && *in.Website != "" {
+		checkURL(errs, "website", in.Website)
+	}
+
+	if in.Names != nil {
+		update["names"] = optionalText(in.Names)
+	}
+	if in.ShortNames != nil {
+		update["short_names"] = optionalText(in.ShortNames)
+	}
+	if in.Colors != nil {
+		update["colors"] = listUpdate(cleanColors(errs, "colors", *in.Colors))
+	}
+	if in.FoundedYear != nil {
+		update["founded_year"] = optionalValue(*in.FoundedYear)
+	}
+	if in.Website != nil {
+		update["website"] = optionalValue(strings.TrimSpace(*in.Website))
+	}
+}
+
+type contentUpdate struct {
+	Title     *models.MultiLangString `json:"title"`
+	Body      *models.MultiLangString `json:"body"`
+	ImageURL  *string                 `json:"image_url"`
+	Category  *string                 `json:"category"`
+	ClubID    *string                 `json:"club_id"`
+	LeagueIDs *[]string               `json:"league_ids"`
+	MatchID   *string                 `json:"match_id"`
+	PlayerIDs *[]string               `json:"player_ids"`
+	Breaking  *bool                   `json:"breaking"`
+}
+
+// toUpdate also needs the current article so that a category from before the
+// whitelist can be sent back unchanged
+func (in contentUpdate) toUpdate(ctx context.Context, h *Handler, current *models.Content) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkMultiLang(errs, "title", in.Title)
+	checkMultiLang(errs, "body", in.Body)
+	checkURL(errs, "image_url", in.ImageURL)
+	if in.Category != nil && !isAllowedCategory(*in.Category) && *in.Category != current.Category {
+		errs.Add("category", "must be one of: "+strings.Join(models.ContentCategories, ", "))
+	}
+
+	if in.Title != nil {
+		update["title"] = *in.Title
+	}
+	if in.Body != nil {
+		update["body"] = *in.Body
+	}
+	if in.ImageURL != nil {
+		update["image_url"] = *in.ImageURL
+	}
+	if in.Category != nil {
+		update["category"] = *in.Category
+	}
+	if in.ClubID != nil {
+		if isGeneralClubID(*in.ClubID) {
+			update["club_id"] = nil
+		} else {
+			update["club_id"] = h.resolveClubID(ctx, errs, "club_id", *in.ClubID)
+		}
+	}
+	if in.LeagueIDs != nil {
+		update["league_ids"] = h.resolveLeagueIDs(ctx, errs, "league_ids", *in.LeagueIDs)
+	}
+	if in.MatchID != nil {
+		update["match_id"] = h.matchIDUpdate(ctx, errs, *in.MatchID)
+	}
+	if in.PlayerIDs != nil {
+		update["player_ids"] = h.resolvePlayerIDs(ctx, errs, "player_ids", *in.PlayerIDs)
+	}
+	if in.Breaking != nil {
+		update["breaking"] = *in.Breaking
+	}
+	return update, errs
+}
+
+// matchIDUpdate is the update value for match_id; an empty ID unlinks
+func (h *Handler) matchIDUpdate(ctx context.Context, errs ValidationErrors, raw string) interface{} {
+	if raw == "" {
+		return nil
+	}
+	return h.resolveMatchID(ctx, errs, "match_id", raw)
+}
+
+type highlightUpdate struct {
+	MatchTitle *string   `json:"match_title"`
+	YoutubeURL *string   `json:"youtube_url"`
+	ClubIDs    *[]string `json:"club_ids"`
+	LeagueIDs  *[]string `json:"league_ids"`
+	MatchID    *string   `json:"match_id"`
+	PlayerIDs  *[]string `json:"player_ids"`
+	Breaking   *bool     `json:"breaking"`
+}
+
+func (in highlightUpdate) toUpdate(ctx context.Context, h *Handler) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkRequiredString(errs, "match_title", in.MatchTitle)
+	checkURL(errs, "youtube_url", in.YoutubeURL)
+
+	if in.MatchTitle != nil {
+		update["match_title"] = strings.TrimSpace(*in.MatchTitle)
+	}
+	if in.YoutubeURL != nil {
+		update["youtube_url"] = *in.YoutubeURL
+	}
+	if in.ClubIDs != nil {
+		update["club_ids"] = h.resolveClubIDs(ctx, errs, "club_ids", *in.ClubIDs)
+	}
+	if in.LeagueIDs != nil {
+		update["league_ids"] = h.resolveLeagueIDs(ctx, errs, "league_ids", *in.LeagueIDs)
+	}
+	if in.MatchID != nil {
+		update["match_id"] = h.matchIDUpdate(ctx, errs, *in.MatchID)
+	}
+	if in.PlayerIDs != nil {
+		update["player_ids"] = h.resolvePlayerIDs(ctx, errs, "player_ids", *in.PlayerIDs)
+	}
+	if in.Breaking != nil {
+		update["breaking"] = *in.Breaking
+	}
+	return update, errs
+}
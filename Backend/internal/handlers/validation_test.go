@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+)
+
+func TestBindStrict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		ok     bool
+		error  string
+		fields map[string]string
+	}{
+		{name: "known fields", body: `{"name": "Buna", "short_name": "BUN"}`, ok: true},
+		{name: "unknown field", body: `{"name": "Buna", "nickname": "Coffee"}`, error: "Validation failed", fields: map[string]string{"nickname": "unknown field"}},
+		{name: "wrong type", body: `{"name": 7}`, error: "Validation failed", fields: map[string]string{"name": "must be of type string"}},
+		{name: "no body", body: ``, error: "Request body is required"},
+		{name: "malformed", body: `{"name": `, error: "unexpected EOF"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+		var dst struct {
+			Name      string `json:"name"`
+			ShortName string `json:"short_name"`
+		}
+		if ok := bindStrict(c, &dst); ok != tt.ok {
+			t.Errorf("%s: bindStrict = %v; want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if tt.ok {
+			if dst.Name != "Buna" || dst.ShortName != "BUN" {
+				t.Errorf("%s: decoded %+v", tt.name, dst)
+			}
+			continue
+		}
+
+		var body struct {
+			Error  string            `json:"error"`
+			Fields map[string]string `json:"fields"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: body %q: %v", tt.name, w.Body.String(), err)
+		}
+		if w.Code != http.StatusBadRequest || body.Error != tt.error {
+			t.Errorf("%s: %d %q; want 400 %q", tt.name, w.Code, body.Error, tt.error)
+		}
+		for field, message := range tt.fields {
+			if body.Fields[field] != message {
+				t.Errorf("%s: fields %v; want %s: %s", tt.name, body.Fields, field, message)
+			}
+		}
+	}
+}
+
+func TestValidationLookup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{"found", nil, 0, ""},
+		{"missing", mongo.ErrNoDocuments, http.StatusBadRequest, "club_id"},
+		{"database down", errors.New("server selection timeout"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		errs := ValidationErrors{}
+		errs.lookup("club_id", "club does not exist", tt.err)
+		if tt.status == 0 {
+			if len(errs) != 0 {
+				t.Errorf("%s: errors %v; want none", tt.name, errs)
+			}
+			continue
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		respondValidation(c, errs)
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d; want %d", tt.name, w.Code, tt.status)
+		}
+		if tt.field != "" && errs[tt.field] != "club does not exist" {
+			t.Errorf("%s: errors %v; want %s", tt.name, errs, tt.field)
+		}
+		if strings.Contains(w.Body.String(), "server selection") {
+			t.Errorf("%s: body leaks the database error: %s", tt.name, w.Body.String())
+		}
+	}
+}
+
+func TestFieldChecks(t *testing.T) {
+	text := func(s string) *string { return &s }
+	tests := []struct {
+		name  string
+		check func(ValidationErrors)
+		ok    bool
+	}{
+		{"https URL", func(e ValidationErrors) { checkURL(e, "f", text("https://example.com/a.jpg")) }, true},
+		{"relative URL", func(e ValidationErrors) { checkURL(e, "f", text("/a.jpg")) }, false},
+		{"ftp URL", func(e ValidationErrors) { checkURL(e, "f", text("ftp://example.com/a.jpg")) }, false},
+		{"missing URL", func(e ValidationErrors) { checkURL(e, "f", nil) }, true},
+		{"blank string", func(e ValidationErrors) { checkRequiredString(e, "f", text("  ")) }, false},
+		{"one translation", func(e ValidationErrors) { checkMultiLang(e, "f", &models.MultiLangString{AM: "ዜና"}) }, true},
+		{"no translation", func(e ValidationErrors) { checkMultiLang(e, "f", &models.MultiLangString{EN: " "}) }, false},
+		{"founded year", func(e ValidationErrors) { year := 1935; checkFoundedYear(e, "f", &year) }, true},
+		{"future founded year", func(e ValidationErrors) { year := 3000; checkFoundedYear(e, "f", &year) }, false},
+	}
+	for _, tt := range tests {
+		errs := ValidationErrors{}
+		tt.check(errs)
+		if (len(errs) == 0) != tt.ok {
+			t.Errorf("%s: errors %v; want ok %v", tt.name, errs, tt.ok)
+		}
+	}
+}
+
+func TestCleanAliases(t *testing.T) {
+	errs := ValidationErrors{}
+	got := cleanAliases(errs, "aliases", []string{" Buna ", "buna", "", "Ethiopian Coffee"})
+	if len(errs) != 0 || strings.Join(got, "|") != "Buna|Ethiopian Coffee" {
+		t.Errorf("cleanAliases = %q, %v", got, errs)
+	}
+
+	errs = ValidationErrors{}
+	cleanAliases(errs, "aliases", []string{strings.Repeat("x", maxAliasLength+1)})
+	if errs["aliases"] == "" {
+		t.Errorf("an alias over %d characters was accepted", maxAliasLength)
+	}
+
+	errs = ValidationErrors{}
+	if got := cleanAliases(errs, "aliases", make([]string, maxAliases+1)); got != nil || errs["aliases"] == "" {
+		t.Errorf("%d aliases: %q, %v; want rejected", maxAliases+1, got, errs)
+	}
+}
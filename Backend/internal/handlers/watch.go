@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+// How closely a watch link covers what a fan wants to watch, closest first
+const (
+	coverageMatch  = 3
+	coverageClub   = 2
+	coverageLeague = 1
+	coverageAll    = 0 // a link for every match
+	coverageNone   = -1
+)
+
+func isGeneralLink(link models.WatchLink) bool {
+	return len(link.LeagueIDs) == 0 && len(link.ClubIDs) == 0 && len(link.MatchIDs) == 0
+}
+
+func matchCoverage(link models.WatchLink, match *models.Match) int {
+	switch {
+	case slices.Contains(link.MatchIDs, match.ID):
+		return coverageMatch
+	case slices.Contains(link.ClubIDs, match.HomeClubID), slices.Contains(link.ClubIDs, match.AwayClubID):
+		return coverageClub
+	case slices.Contains(link.LeagueIDs, match.LeagueID):
+		return coverageLeague
+	case isGeneralLink(link):
+		return coverageAll
+	}
+	return coverageNone
+}
+
+// clubCoverage also counts links for one of the club's upcoming matches
+func clubCoverage(link models.WatchLink, club *models.Club, upcoming []models.Match) int {
+	for _, match := range upcoming {
+		if slices.Contains(link.MatchIDs, match.ID) {
+			return coverageMatch
+		}
+	}
+	switch {
+	case slices.Contains(link.ClubIDs, club.ID):
+		return coverageClub
+	case slices.Contains(link.LeagueIDs, club.LeagueID):
+		return coverageLeague
+	case isGeneralLink(link):
+		return coverageAll
+	}
+	return coverageNone
+}
+
+func leagueCoverage(link models.WatchLink, leagueID bson.ObjectID) int {
+	switch {
+	case slices.Contains(link.LeagueIDs, leagueID):
+		return coverageLeague
+	case isGeneralLink(link):
+		return coverageAll
+	}
+	return coverageNone
+}
+
+// availableDuring reports whether the link's window overlaps from..until
+func availableDuring(link models.WatchLink, from, until time.Time) bool {
+	if link.AvailableFrom != nil && !link.AvailableFrom.Before(until) {
+		return false
+	}
+	return link.AvailableUntil == nil || link.AvailableUntil.After(from)
+}
+
+// availableForClub reports whether a link can be watched now or during one of
+// the club's upcoming matches that it shows, such as a stream that opens at
+// kickoff
+func availableForClub(link models.WatchLink, upcoming []models.Match, now time.Time) bool {
+	if availableDuring(link, now, now) {
+		return true
+	}
+	for i := range upcoming {
+		match := &upcoming[i]
+		if matchCoverage(link, match) != coverageNone && availableDuring(link, match.KickoffAt, match.KickoffAt.Add(matchLength)) {
+			return true
+		}
+	}
+	return false
+}
+
+func availableIn(link models.WatchLink, country string) bool {
+	return len(link.Countries) == 0 || slices.Contains(link.Countries, country)
+}
+
+// rankWatchLinks keeps the links a coverage function accepts, closest first
+func rankWatchLinks(links []models.WatchLink, coverage func(models.WatchLink) int) []models.WatchLink {
+	type ranked struct {
+		link models.WatchLink
+		rank int
+	}
+	var kept []ranked
+	for _, link := range links {
+		if rank := coverage(link); rank != coverageNone {
+			kept = append(kept, ranked{link, rank})
+		}
+	}
+	slices.SortStableFunc(kept, func(a, b ranked) int { return b.rank - a.rank })
+
+	out := make([]models.WatchLink, len(kept))
+	for i, k := range kept {
+		out[i] = k.link
+	}
+	return out
+}
+
+// matchWatchLinks returns the links that show a match, closest first
+func matchWatchLinks(links []models.WatchLink, match *models.Match) []models.WatchLink {
+	return rankWatchLinks(links, func(link models.WatchLink) int {
+		if !availableDuring(link, match.KickoffAt, match.KickoffAt.Add(matchLength)) {
+			return coverageNone
+		}
+		return matchCoverage(link, match)
+	})
+}
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+var languageCode = regexp.MustCompile(`^[a-z]{2}$`)
+
+// maxWatchTargets caps how many leagues, clubs or matches one link lists
+const maxWatchTargets = 100
+
+// cleanCodes normalises country or language codes, dropping repeats
+func cleanCodes(errs ValidationErrors, field string, codes []string, pattern *regexp.Regexp, normalise func(string) string, message string) []string {
+	cleaned := []string{}
+	for _, code := range codes {
+		code = normalise(strings.TrimSpace(code))
+		if !pattern.MatchString(code) {
+			errs.Add(field, message)
+			continue
+		}
+		if !slices.Contains(cleaned, code) {
+			cleaned = append(cleaned, code)
+		}
+	}
+	return cleaned
+}
+
+func cleanCountries(errs ValidationErrors, field string, codes []string) []string {
+	return cleanCodes(errs, field, codes, countryCode, strings.ToUpper, "must be ISO 3166-1 alpha-2 country codes, e.g. ET")
+}
+
+func cleanLanguages(errs ValidationErrors, field string, codes []string) []string {
+	return cleanCodes(errs, field, codes, languageCode, strings.ToLower, "must be ISO 639-1 language codes, e.g. am")
+}
+
+func checkWatchAccess(errs ValidationErrors, field string, access *string) {
+	if access != nil && *access != "" && !slices.Contains(models.WatchAccess, *access) {
+		errs.Add(field, "must be free or paid")
+	}
+}
+
+func checkAvailability(errs ValidationErrors, from, until *time.Time) {
+	if from != nil && until != nil && !until.After(*from) {
+		errs.Add("available_until", "must be after available_from")
+	}
+}
+
+// watchTargets resolves the leagues, clubs or matches a link covers,
+// dropping repeats
+func watchTargets(errs ValidationErrors, field string, raw []string, resolve func(string) bson.ObjectID) []bson.ObjectID {
+	if len(raw) > maxWatchTargets {
+		errs.Add(field, "must have at most 100 entries")
+		return nil
+	}
+	ids := []bson.ObjectID{}
+	for _, idStr := range raw {
+		id := resolve(idStr)
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (h *Handler) watchLeagues(ctx context.Context, errs ValidationErrors, raw []string) []bson.ObjectID {
+	return watchTargets(errs, "league_ids", raw, func(id string) bson.ObjectID {
+		return h.resolveLeagueID(ctx, errs, "league_ids", id)
+	})
+}
+
+func (h *Handler) watchClubs(ctx context.Context, errs ValidationErrors, raw []string) []bson.ObjectID {
+	return watchTargets(errs, "club_ids", raw, func(id string) bson.ObjectID {
+		return h.resolveClubID(ctx, errs, "club_ids", id)
+	})
+}
+
+func (h *Handler) watchMatches(ctx context.Context, errs ValidationErrors, raw []string) []bson.ObjectID {
+	return watchTargets(errs, "match_ids", raw, func(id string) bson.ObjectID {
+		return h.resolveMatchID(ctx, errs, "match_ids", id)
+	})
+}
+
+// idsUpdate is the update value for a list of IDs; an empty list removes it
+func idsUpdate(ids []bson.ObjectID) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids
+}
+
+// watchLinkInput is the body for adding a watch link
+type watchLinkInput struct {
+	Name                string     `json:"name"`
+	URL                 string     `json:"url"`
+	Type                string     `json:"type"`
+	LogoURL             string     `json:"logo_url"`
+	LeagueIDs           []string   `json:"league_ids"`
+	ClubIDs             []string   `json:"club_ids"`
+	MatchIDs            []string   `json:"match_ids"`
+	Countries           []string   `json:"countries"`
+	Access              string     `json:"access"`
+	CommentaryLanguages []string   `json:"commentary_languages"`
+	AvailableFrom       *time.Time `json:"available_from"`
+	AvailableUntil      *time.Time `json:"available_until"`
+}
+
+func (in watchLinkInput) toWatchLink(ctx context.Context, h *Handler) (models.WatchLink, ValidationErrors) {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(in.Name) == "" {
+		errs.Add("name", "is required")
+	}
+	checkURL(errs, "url", &in.URL)
+	if in.LogoURL != "" {
+		checkURL(errs, "logo_url", &in.LogoURL)
+	}
+	checkWatchAccess(errs, "access", &in.Access)
+	checkAvailability(errs, in.AvailableFrom, in.AvailableUntil)
+
+	now := time.Now()
+	link := models.WatchLink{
+		ID:             bson.NewObjectID(),
+		Name:           strings.TrimSpace(in.Name),
+		URL:            in.URL,
+		Type:           strings.TrimSpace(in.Type),
+		LogoURL:        in.LogoURL,
+		Access:         in.Access,
+		AvailableFrom:  in.AvailableFrom,
+		AvailableUntil: in.AvailableUntil,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Version:        1,
+	}
+	// Empty lists are left out, so the link covers every match
+	if ids := h.watchLeagues(ctx, errs, in.LeagueIDs); len(ids) > 0 {
+		link.LeagueIDs = ids
+	}
+	if ids := h.watchClubs(ctx, errs, in.ClubIDs); len(ids) > 0 {
+		link.ClubIDs = ids
+	}
+	if ids := h.watchMatches(ctx, errs, in.MatchIDs); len(ids) > 0 {
+		link.MatchIDs = ids
+	}
+	if codes := cleanCountries(errs, "countries", in.Countries); len(codes) > 0 {
+		link.Countries = codes
+	}
+	if codes := cleanLanguages(errs, "commentary_languages", in.CommentaryLanguages); len(codes) > 0 {
+		link.CommentaryLanguages = codes
+	}
+	return link, errs
+}
+
+// watchLinkUpdate takes the availability window as RFC 3339 strings, so an
+// empty string can remove a bound
+type watchLinkUpdate struct {
+	Name                *string   `json:"name"`
+	URL                 *string   `json:"url"`
+	Type                *string   `json:"type"`
+	LogoURL             *string   `json:"logo_url"`
+	LeagueIDs           *[]string `json:"league_ids"`
+	ClubIDs             *[]string `json:"club_ids"`
+	MatchIDs            *[]string `json:"match_ids"`
+	Countries           *[]string `json:"countries"`
+	Access              *string   `json:"access"`
+	CommentaryLanguages *[]string `json:"commentary_languages"`
+	AvailableFrom       *string   `json:"available_from"`
+	AvailableUntil      *string   `json:"available_until"`
+}
+
+// windowUpdate parses one bound of the availability window
+func windowUpdate(errs ValidationErrors, field, raw string, update bson.M, bound **time.Time) {
+	if raw == "" {
+		update[field] = nil
+		*bound = nil
+		return
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		errs.Add(field, "must be an RFC 3339 time, e.g. 2024-11-02T13:00:00Z")
+		return
+	}
+	update[field] = t
+	*bound = &t
+}
+
+func (in watchLinkUpdate) toUpdate(ctx context.Context, h *Handler, current *models.WatchLink) (bson.M, ValidationErrors) {
+	errs := ValidationErrors{}
+	update := bson.M{}
+
+	checkRequiredString(errs, "name", in.Name)
+	checkURL(errs, "url", in.URL)
+	checkRequiredString(errs, "type", in.Type)
+	checkURL(errs, "logo_url", in.LogoURL)
+	checkWatchAccess(errs, "access", in.Access)
+
+	if in.Name != nil {
+		update["name"] = strings.TrimSpace(*in.Name)
+	}
+	if in.URL != nil {
+		update["url"] = *in.URL
+	}
+	if in.Type != nil {
+		update["type"] = strings.TrimSpace(*in.Type)
+	}
+	if in.LogoURL != nil {
+		update["logo_url"] = *in.LogoURL
+	}
+	if in.LeagueIDs != nil {
+		update["league_ids"] = idsUpdate(h.watchLeagues(ctx, errs, *in.LeagueIDs))
+	}
+	if in.ClubIDs != nil {
+		update["club_ids"] = idsUpdate(h.watchClubs(ctx, errs, *in.ClubIDs))
+	}
+	if in.MatchIDs != nil {
+		update["match_ids"] = idsUpdate(h.watchMatches(ctx, errs, *in.MatchIDs))
+	}
+	if in.Countries != nil {
+		update["countries"] = listUpdate(cleanCountries(errs, "countries", *in.Countries))
+	}
+	if in.Access != nil {
+		update["access"] = optionalValue(*in.Access)
+	}
+	if in.CommentaryLanguages != nil {
+		update["commentary_languages"] = listUpdate(cleanLanguages(errs, "commentary_languages", *in.CommentaryLanguages))
+	}
+
+	from, until := current.AvailableFrom, current.AvailableUntil
+	if in.AvailableFrom != nil {
+		windowUpdate(errs, "available_from", *in.AvailableFrom, update, &from)
+	}
+	if in.AvailableUntil != nil {
+		windowUpdate(errs, "available_until", *in.AvailableUntil, update, &until)
+	}
+	checkAvailability(errs, from, until)
+	return update, errs
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+func TestAvailableForClub(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	club, rival := bson.NewObjectID(), bson.NewObjectID()
+	next := models.Match{ID: bson.NewObjectID(), HomeClubID: club, AwayClubID: rival, KickoffAt: now.Add(26 * time.Hour)}
+	other := models.Match{ID: bson.NewObjectID(), HomeClubID: rival, AwayClubID: club, KickoffAt: now.Add(8 * 24 * time.Hour)}
+	upcoming := []models.Match{next, other}
+
+	at := func(t time.Time) *time.Time { return &t }
+	tests := []struct {
+		name string
+		link models.WatchLink
+		want bool
+	}{
+		{"always available", models.WatchLink{}, true},
+		{"expired", models.WatchLink{AvailableUntil: at(now.Add(-time.Hour))}, false},
+		{
+			name: "stream opening at the next kickoff",
+			link: models.WatchLink{MatchIDs: []bson.ObjectID{next.ID}, AvailableFrom: at(next.KickoffAt), AvailableUntil: at(next.KickoffAt.Add(2 * time.Hour))},
+			want: true,
+		},
+		{
+			name: "club channel opening for a later match",
+			link: models.WatchLink{ClubIDs: []bson.ObjectID{club}, AvailableFrom: at(other.KickoffAt.Add(-time.Hour))},
+			want: true,
+		},
+		{
+			name: "window between the club's matches",
+			link: models.WatchLink{ClubIDs: []bson.ObjectID{club}, AvailableFrom: at(now.Add(48 * time.Hour)), AvailableUntil: at(now.Add(72 * time.Hour))},
+			want: false,
+		},
+		{
+			name: "stream for another club's match",
+			link: models.WatchLink{MatchIDs: []bson.ObjectID{bson.NewObjectID()}, AvailableFrom: at(next.KickoffAt)},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		if got := availableForClub(tt.link, upcoming, now); got != tt.want {
+			t.Errorf("%s: availableForClub = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchWatchLinks(t *testing.T) {
+	kickoff := time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC)
+	match := models.Match{ID: bson.NewObjectID(), HomeClubID: bson.NewObjectID(), AwayClubID: bson.NewObjectID(), LeagueID: bson.NewObjectID(), KickoffAt: kickoff}
+	expired := kickoff.Add(-time.Hour)
+
+	links := []models.WatchLink{
+		{Name: "general"},
+		{Name: "league", LeagueIDs: []bson.ObjectID{match.LeagueID}},
+		{Name: "other league", LeagueIDs: []bson.ObjectID{bson.NewObjectID()}},
+		{Name: "club", ClubIDs: []bson.ObjectID{match.AwayClubID}},
+		{Name: "match", MatchIDs: []bson.ObjectID{match.ID}},
+		{Name: "expired", MatchIDs: []bson.ObjectID{match.ID}, AvailableUntil: &expired},
+	}
+	got := matchWatchLinks(links, &match)
+	want := []string{"match", "club", "league", "general"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d links; want %v", len(got), want)
+	}
+	for i, link := range got {
+		if link.Name != want[i] {
+			t.Errorf("link %d = %s; want %s", i, link.Name, want[i])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can
+// subscribe to.
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is a published feed of events
+type Calendar struct {
+	Name        string
+	Description string
+	// Refresh is how often subscribers should fetch the feed again
+	Refresh time.Duration
+	Events  []Event
+}
+
+// Event is one calendar entry. UID stays the same as the event changes and
+// Sequence grows with each change, so calendars update it in place.
+type Event struct {
+	UID          string
+	Sequence     int64
+	Start, End   time.Time
+	Summary      string
+	Location     string
+	Description  string
+	URL          string
+	Status       string
+	Categories   []string
+	LastModified time.Time
+}
+
+// Write renders the calendar with CRLF line endings and folded lines. stamp
+// is the DTSTAMP of every event; the time of the latest change keeps the
+// output the same until something changes.
+func (cal Calendar) Write(stamp time.Time) []byte {
+	w := &writer{}
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//FanZone//Fixtures//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		w.line("NAME", text(cal.Name))
+		w.line("X-WR-CALNAME", text(cal.Name))
+	}
+	if cal.Description != "" {
+		w.line("X-WR-CALDESC", text(cal.Description))
+	}
+	if cal.Refresh > 0 {
+		w.line("REFRESH-INTERVAL;VALUE=DURATION", duration(cal.Refresh))
+		w.line("X-PUBLISHED-TTL", duration(cal.Refresh))
+	}
+
+	for _, event := range cal.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", text(event.UID))
+		w.line("DTSTAMP", utc(stamp))
+		w.line("DTSTART", utc(event.Start))
+		w.line("DTEND", utc(event.End))
+		w.line("SEQUENCE", strconv.FormatInt(event.Sequence, 10))
+		w.line("SUMMARY", text(event.Summary))
+		if event.Location != "" {
+			w.line("LOCATION", text(event.Location))
+		}
+		if event.Description != "" {
+			w.line("DESCRIPTION", text(event.Description))
+		}
+		if event.URL != "" {
+			w.line("URL;VALUE=URI", event.URL)
+		}
+		if event.Status != "" {
+			w.line("STATUS", event.Status)
+		}
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for i, category := range event.Categories {
+				categories[i] = text(category)
+			}
+			w.line("CATEGORIES", strings.Join(categories, ","))
+		}
+		if !event.LastModified.IsZero() {
+			w.line("LAST-MODIFIED", utc(event.LastModified))
+		}
+		w.line("END", "VEVENT")
+	}
+	w.line("END", "VCALENDAR")
+	return w.buf.Bytes()
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+// line writes a content line, folding it at 75 octets without splitting a
+// UTF-8 character (RFC 5545, section 3.1)
+func (w *writer) line(name, value string) {
+	content := name + ":" + value
+	width := 0
+	for len(content) > 0 {
+		_, size := utf8.DecodeRuneInString(content)
+		if width+size > 75 {
+			w.buf.WriteString("\r\n ")
+			width = 1
+		}
+		w.buf.WriteString(content[:size])
+		width += size
+		content = content[size:]
+	}
+	w.buf.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// text escapes a TEXT value
+func text(value string) string {
+	return textEscaper.Replace(value)
+}
+
+func utc(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// duration formats a whole number of minutes as an RFC 5545 duration
+func duration(d time.Duration) string {
+	minutes := int64(d / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes%60 == 0 {
+		return "PT" + strconv.FormatInt(minutes/60, 10) + "H"
+	}
+	return "PT" + strconv.FormatInt(minutes, 10) + "M"
+}
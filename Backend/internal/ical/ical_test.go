@@ -0,0 +1,120 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var stamp = time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// unfold joins folded lines back into content lines
+func unfold(t *testing.T, out []byte) []string {
+	t.Helper()
+	s := string(out)
+	if !strings.HasSuffix(s, "\r\n") {
+		t.Fatalf("output does not end with CRLF: %q", s)
+	}
+	if strings.Contains(strings.ReplaceAll(s, "\r\n", ""), "\n") {
+		t.Fatalf("output has a bare LF: %q", s)
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestWrite(t *testing.T) {
+	addis := time.FixedZone("EAT", 3*60*60)
+	cal := Calendar{
+		Name:    "Saint George fixtures",
+		Refresh: 6 * time.Hour,
+		Events: []Event{{
+			UID:          "match-1@fanzone",
+			Sequence:     2,
+			Start:        time.Date(2025, 3, 2, 18, 0, 0, 0, addis),
+			End:          time.Date(2025, 3, 2, 20, 0, 0, 0, addis),
+			Summary:      "Saint George vs Buna",
+			Location:     "Addis Ababa Stadium",
+			Status:       StatusConfirmed,
+			Categories:   []string{"Premier League", "Derby, home"},
+			LastModified: time.Date(2025, 2, 28, 10, 30, 0, 0, time.UTC),
+		}},
+	}
+	want := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//FanZone//Fixtures//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"NAME:Saint George fixtures",
+		"X-WR-CALNAME:Saint George fixtures",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT6H",
+		"X-PUBLISHED-TTL:PT6H",
+		"BEGIN:VEVENT",
+		"UID:match-1@fanzone",
+		"DTSTAMP:20250301T090000Z",
+		"DTSTART:20250302T150000Z",
+		"DTEND:20250302T170000Z",
+		"SEQUENCE:2",
+		"SUMMARY:Saint George vs Buna",
+		"LOCATION:Addis Ababa Stadium",
+		"STATUS:CONFIRMED",
+		`CATEGORIES:Premier League,Derby\, home`,
+		"LAST-MODIFIED:20250228T103000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}
+	got := unfold(t, cal.Write(stamp))
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Write =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	summaries := []string{
+		strings.Repeat("Saint George ", 20),
+		strings.Repeat("ቅዱስ ጊዮርጊስ ከ ኢትዮጵያ ቡና ", 10),   // three octets a letter
+		strings.Repeat("x", 74-len("SUMMARY:")) + "é", // a two-octet letter across the limit
+	}
+	for _, summary := range summaries {
+		out := Calendar{Events: []Event{{UID: "1", Summary: summary}}}.Write(stamp)
+		for _, line := range strings.Split(strings.TrimSuffix(string(out), "\r\n"), "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("line of %d octets: %q", len(line), line)
+			}
+			if !utf8.ValidString(line) {
+				t.Errorf("fold splits a character: %q", line)
+			}
+		}
+		if !strings.Contains(strings.Join(unfold(t, out), "\n"), "\nSUMMARY:"+summary+"\n") {
+			t.Errorf("unfolded output lost the summary %q", summary)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	for value, want := range map[string]string{
+		"Buna; Saint George, 2-1": `Buna\; Saint George\, 2-1`,
+		`C:\stadium`:              `C:\\stadium`,
+		"line one\r\nline two":    `line one\nline two`,
+		"a\nb\rc":                 `a\nb\nc`,
+		"ቅዱስ ጊዮርጊስ":               "ቅዱስ ጊዮርጊስ",
+	} {
+		if got := text(value); got != want {
+			t.Errorf("text(%q) = %q; want %q", value, got, want)
+		}
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		6 * time.Hour:    "PT6H",
+		90 * time.Minute: "PT90M",
+		time.Minute:      "PT1M",
+		time.Second:      "PT1M",
+		0:                "PT1M",
+	} {
+		if got := duration(d); got != want {
+			t.Errorf("duration(%v) = %q; want %q", d, got, want)
+		}
+	}
+}
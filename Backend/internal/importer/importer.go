@@ -0,0 +1,318 @@
+// Package importer applies bulk imports of leagues, clubs, fixtures, results
+// and players from CSV or JSON files. Prepare validates the rows and resolves
+// names to existing IDs into a Plan that can be previewed before it is
+// applied. Rows are matched to what already exists by name, so importing the
+// same file twice changes nothing the second time.
+package importer
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// Kinds of import file
+const (
+	KindLeagues  = "leagues"
+	KindClubs    = "clubs"
+	KindFixtures = "fixtures"
+	KindResults  = "results"
+	KindPlayers  = "players"
+)
+
+var Kinds = []string{KindLeagues, KindClubs, KindFixtures, KindResults, KindPlayers}
+
+// MaxRows caps the rows of one import file
+const MaxRows = 5000
+
+// Row is one record of an import file, keyed by lower-case column name.
+// Blank values are left out.
+type Row map[string]string
+
+// Parse reads an import file. CSV files start with a header row; JSON files
+// hold an array of objects with string or number values.
+func Parse(r io.Reader, format string) ([]Row, error) {
+	var rows []Row
+	var err error
+	switch format {
+	case "csv":
+		rows, err = parseCSV(r)
+	case "json":
+		rows, err = parseJSON(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected csv or json", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the file has no rows")
+	}
+	if len(rows) > MaxRows {
+		return nil, fmt.Errorf("the file has %d rows, at most %d are allowed", len(rows), MaxRows)
+	}
+	return rows, nil
+}
+
+// column normalises a column name, so "Home Score" reads as home_score
+func column(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "_")
+}
+
+func parseCSV(r io.Reader) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1 // short rows leave the last columns blank
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		header[i] = column(strings.TrimPrefix(name, "\ufeff"))
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := Row{}
+		for i, name := range header {
+			if i >= len(record) {
+				break
+			}
+			if value := strings.TrimSpace(record[i]); value != "" {
+				row[name] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseJSON(r io.Reader) ([]Row, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var records []map[string]interface{}
+	if err := decoder.Decode(&records); err != nil {
+		return nil, fmt.Errorf("invalid JSON, expected an array of objects: %w", err)
+	}
+
+	rows := make([]Row, len(records))
+	for i, record := range records {
+		row := Row{}
+		for key, value := range record {
+			var text string
+			switch v := value.(type) {
+			case nil:
+			case string:
+				text = v
+			case json.Number:
+				text = v.String()
+			case bool:
+				text = strconv.FormatBool(v)
+			default:
+				return nil, fmt.Errorf("row %d: %s must be a string or a number", i+1, key)
+			}
+			if text = strings.TrimSpace(text); text != "" {
+				row[column(key)] = text
+			}
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// Actions a row can lead to
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// Change is what applying one row does
+type Change struct {
+	Row    int           `json:"row"`
+	Action string        `json:"action"`
+	ID     bson.ObjectID `json:"id"`
+	Label  string        `json:"label"`
+	// Document is what a create inserts; Fields is what an update changes
+	Document interface{} `json:"document,omitempty"`
+	Fields   bson.M      `json:"fields,omitempty"`
+
+	apply func(ctx context.Context) error
+}
+
+// Plan is the outcome of validating an import file. Rows with errors are
+// left out of the changes; the others still apply.
+type Plan struct {
+	Kind    string               `json:"kind"`
+	Changes []Change             `json:"changes"`
+	Errors  []models.ImportError `json:"errors"`
+	Summary models.ImportSummary `json:"summary"`
+	// Standings lists the league seasons whose tables the changes affect
+	Standings []repository.StandingsKey `json:"-"`
+
+	// after runs once every row has applied, given the rows that failed
+	after []func(ctx context.Context, failed map[int]bool) error
+}
+
+// Store is the part of the repository an import reads and writes
+type Store interface {
+	GetLeagues(ctx context.Context) ([]models.League, error)
+	CreateLeague(ctx context.Context, league models.League) error
+	UpdateLeague(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error)
+	GetClubs(ctx context.Context) ([]models.Club, error)
+	CreateClub(ctx context.Context, club models.Club) error
+	UpdateClub(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error)
+	FindSeason(ctx context.Context, leagueID bson.ObjectID, name string) (*models.Season, error)
+	FindCurrentSeason(ctx context.Context, leagueID bson.ObjectID) (*models.Season, error)
+	UpdateSeason(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error)
+	ApplyCurrentSeason(ctx context.Context, leagueID bson.ObjectID) ([]bson.ObjectID, error)
+	GetMatches(ctx context.Context, filter bson.M, ascending bool, limit int64) ([]models.Match, error)
+	CreateMatch(ctx context.Context, match models.Match) error
+	UpdateMatch(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error)
+	CreateMatchEvent(ctx context.Context, event models.MatchEvent) error
+	GetPlayers(ctx context.Context, filter bson.M) ([]models.Player, error)
+	CreatePlayer(ctx context.Context, player models.Player) error
+	UpdatePlayer(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error)
+	GetSquads(ctx context.Context, filter bson.M) ([]models.Squad, error)
+	CreateSquad(ctx context.Context, squad models.Squad) error
+	UpdateSquad(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error)
+}
+
+// Prepare validates rows of the given kind against the store and plans the
+// changes they make. It only fails when the store cannot be read.
+func Prepare(ctx context.Context, store Store, kind string, rows []Row) (*Plan, error) {
+	p := &planner{
+		ctx:   ctx,
+		store: store,
+		plan:  &Plan{Kind: kind, Changes: []Change{}, Errors: []models.ImportError{}},
+		seen:  map[string]int{},
+	}
+
+	var plan func(n int, row Row) *Change
+	switch kind {
+	case KindLeagues:
+		plan = p.leagueRow
+	case KindClubs:
+		plan = p.clubRow
+	case KindFixtures:
+		plan = p.fixtureRow
+	case KindResults:
+		plan = p.resultRow
+	case KindPlayers:
+		plan = p.playerRow
+	default:
+		return nil, fmt.Errorf("unknown kind %q, expected one of: %s", kind, strings.Join(Kinds, ", "))
+	}
+	if err := p.load(kind); err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		n := i + 1
+		failed := len(p.plan.Errors)
+		change := plan(n, row)
+		if p.err != nil {
+			return nil, p.err
+		}
+		if len(p.plan.Errors) > failed || change == nil {
+			p.plan.Summary.Failed++
+			continue
+		}
+		change.Row = n
+		p.plan.Changes = append(p.plan.Changes, *change)
+		switch change.Action {
+		case ActionCreate:
+			p.plan.Summary.Created++
+		case ActionUpdate:
+			p.plan.Summary.Updated++
+		default:
+			p.plan.Summary.Unchanged++
+		}
+	}
+	switch kind {
+	case KindClubs:
+		p.planSeasons()
+	case KindPlayers:
+		p.planSquads()
+	}
+	return p.plan, nil
+}
+
+// Apply makes the planned changes in row order. A change that fails is
+// recorded against its row and the others still apply.
+func (p *Plan) Apply(ctx context.Context) models.ImportSummary {
+	summary := models.ImportSummary{}
+	failed := map[int]bool{}
+	for _, change := range p.Changes {
+		if change.apply != nil {
+			if err := change.apply(ctx); err != nil {
+				p.Errors = append(p.Errors, models.ImportError{Row: change.Row, Message: applyError(err)})
+				failed[change.Row] = true
+				summary.Failed++
+				continue
+			}
+		}
+		switch change.Action {
+		case ActionCreate:
+			summary.Created++
+		case ActionUpdate:
+			summary.Updated++
+		default:
+			summary.Unchanged++
+		}
+	}
+	summary.Failed += p.Summary.Failed
+
+	for _, after := range p.after {
+		if err := after(ctx, failed); err != nil {
+			p.Errors = append(p.Errors, models.ImportError{Message: applyError(err)})
+		}
+	}
+	slices.SortStableFunc(p.Errors, func(a, b models.ImportError) int { return cmp.Compare(a.Row, b.Row) })
+	p.Summary = summary
+	return summary
+}
+
+func applyError(err error) string {
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return "changed by someone else while importing; import the row again"
+	}
+	return err.Error()
+}
+
+// WriteErrorReport writes import errors as CSV, one line per problem
+func WriteErrorReport(w io.Writer, errs []models.ImportError) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "field", "message"}); err != nil {
+		return err
+	}
+	for _, e := range errs {
+		if err := writer.Write([]string{strconv.Itoa(e.Row), e.Field, e.Message}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,375 @@
+package importer
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+)
+
+// memStore is a Store over slices. Updates are recorded rather than applied,
+// except for seasons, whose club lists the tests check.
+type memStore struct {
+	leagues []models.League
+	clubs   []models.Club
+	seasons []models.Season
+	matches []models.Match
+	players []models.Player
+	squads  []models.Squad
+
+	created []interface{}
+	updates map[bson.ObjectID]bson.M
+	events  []models.MatchEvent
+	applied []bson.ObjectID // leagues whose current season was applied
+}
+
+func (s *memStore) update(id bson.ObjectID, update bson.M) (int64, error) {
+	if s.updates == nil {
+		s.updates = map[bson.ObjectID]bson.M{}
+	}
+	s.updates[id] = update
+	return 2, nil
+}
+
+func (s *memStore) GetLeagues(ctx context.Context) ([]models.League, error) { return s.leagues, nil }
+func (s *memStore) CreateLeague(ctx context.Context, league models.League) error {
+	s.created = append(s.created, league)
+	return nil
+}
+func (s *memStore) UpdateLeague(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return s.update(id, update)
+}
+func (s *memStore) GetClubs(ctx context.Context) ([]models.Club, error) { return s.clubs, nil }
+func (s *memStore) CreateClub(ctx context.Context, club models.Club) error {
+	s.created = append(s.created, club)
+	return nil
+}
+func (s *memStore) UpdateClub(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return s.update(id, update)
+}
+
+func (s *memStore) FindSeason(ctx context.Context, leagueID bson.ObjectID, name string) (*models.Season, error) {
+	for i := range s.seasons {
+		if s.seasons[i].LeagueID == leagueID && s.seasons[i].Name == name {
+			return &s.seasons[i], nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (s *memStore) FindCurrentSeason(ctx context.Context, leagueID bson.ObjectID) (*models.Season, error) {
+	for i := range s.seasons {
+		if s.seasons[i].LeagueID == leagueID && s.seasons[i].Current {
+			return &s.seasons[i], nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (s *memStore) UpdateSeason(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	for i := range s.seasons {
+		if s.seasons[i].ID == id {
+			s.seasons[i].ClubIDs = update["club_ids"].([]bson.ObjectID)
+		}
+	}
+	return s.update(id, update)
+}
+
+func (s *memStore) ApplyCurrentSeason(ctx context.Context, leagueID bson.ObjectID) ([]bson.ObjectID, error) {
+	s.applied = append(s.applied, leagueID)
+	return nil, nil
+}
+
+func (s *memStore) GetMatches(ctx context.Context, filter bson.M, ascending bool, limit int64) ([]models.Match, error) {
+	var out []models.Match
+	for _, match := range s.matches {
+		if match.LeagueID == filter["league_id"] && match.Season == filter["season"] {
+			out = append(out, match)
+		}
+	}
+	return out, nil
+}
+func (s *memStore) CreateMatch(ctx context.Context, match models.Match) error {
+	s.created = append(s.created, match)
+	return nil
+}
+func (s *memStore) UpdateMatch(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return s.update(id, update)
+}
+func (s *memStore) CreateMatchEvent(ctx context.Context, event models.MatchEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memStore) GetPlayers(ctx context.Context, filter bson.M) ([]models.Player, error) {
+	return s.players, nil
+}
+func (s *memStore) CreatePlayer(ctx context.Context, player models.Player) error {
+	s.created = append(s.created, player)
+	return nil
+}
+func (s *memStore) UpdatePlayer(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return s.update(id, update)
+}
+func (s *memStore) GetSquads(ctx context.Context, filter bson.M) ([]models.Squad, error) {
+	return s.squads, nil
+}
+func (s *memStore) CreateSquad(ctx context.Context, squad models.Squad) error {
+	s.created = append(s.created, squad)
+	return nil
+}
+func (s *memStore) UpdateSquad(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return s.update(id, update)
+}
+
+// fixture is a league with two clubs and a current season listing them
+type fixture struct {
+	store             *memStore
+	league, other     models.League
+	home, away, third models.Club
+	season            models.Season
+}
+
+func newFixture() fixture {
+	f := fixture{
+		league: models.League{ID: bson.NewObjectID(), Name: "Ethiopian Premier League", Aliases: []string{"EPL"}},
+		other:  models.League{ID: bson.NewObjectID(), Name: "Ethiopian Higher League"},
+	}
+	f.home = models.Club{ID: bson.NewObjectID(), Name: "Saint George", LeagueID: f.league.ID, Version: 1}
+	f.away = models.Club{ID: bson.NewObjectID(), Name: "Fasil Kenema", LeagueID: f.league.ID, Version: 1}
+	f.third = models.Club{ID: bson.NewObjectID(), Name: "Hadiya Hossana", LeagueID: f.other.ID, Version: 1}
+	f.season = models.Season{
+		ID: bson.NewObjectID(), LeagueID: f.league.ID, Name: "2024/25", Current: true,
+		ClubIDs: []bson.ObjectID{f.home.ID, f.away.ID}, Version: 1,
+	}
+	f.store = &memStore{
+		leagues: []models.League{f.league, f.other},
+		clubs:   []models.Club{f.home, f.away, f.third},
+		seasons: []models.Season{f.season},
+	}
+	return f
+}
+
+func run(t *testing.T, store Store, kind string, rows ...Row) *Plan {
+	t.Helper()
+	plan, err := Prepare(context.Background(), store, kind, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan.Apply(context.Background())
+	return plan
+}
+
+func actions(plan *Plan) []string {
+	out := make([]string, len(plan.Changes))
+	for i, change := range plan.Changes {
+		out[i] = change.Action
+	}
+	return out
+}
+
+// errorFields lists the failed rows as "row:field"
+func errorFields(plan *Plan) []string {
+	var out []string
+	for _, e := range plan.Errors {
+		out = append(out, strconv.Itoa(e.Row)+":"+e.Field)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	csvRows, err := Parse(strings.NewReader("\xef\xbb\xbfName, Short Name,Country\nSaint George,,ET\nFasil Kenema\n"), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{{"name": "Saint George", "country": "ET"}, {"name": "Fasil Kenema"}}
+	if len(csvRows) != 2 || !maps.Equal(csvRows[0], want[0]) || !maps.Equal(csvRows[1], want[1]) {
+		t.Errorf("csv rows = %v; want %v", csvRows, want)
+	}
+
+	jsonRows, err := Parse(strings.NewReader(`[{"Home Score": 2, "home": "Saint George", "venue": null}]`), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jsonRows) != 1 || !maps.Equal(jsonRows[0], Row{"home_score": "2", "home": "Saint George"}) {
+		t.Errorf("json rows = %v", jsonRows)
+	}
+
+	if _, err := Parse(strings.NewReader("name\n"), "xml"); err == nil {
+		t.Error("an unknown format parsed")
+	}
+}
+
+func TestLeagueRows(t *testing.T) {
+	f := newFixture()
+	plan := run(t, f.store, KindLeagues,
+		Row{"name": "epl", "country": "ET"}, // alias, ignoring case
+		Row{"name": "Ethiopian Premier League"},
+		Row{"name": "Ethiopian Women's Premier League", "website": "not a url"},
+		Row{"name": "Ethiopian Cup"},
+		Row{"name": "ethiopian  cup"},
+	)
+
+	if got, want := actions(plan), []string{ActionUpdate, ActionCreate}; !slices.Equal(got, want) {
+		t.Errorf("actions = %v; want %v", got, want)
+	}
+	if got, want := errorFields(plan), []string{"2:name", "3:website", "5:name"}; !slices.Equal(got, want) {
+		t.Errorf("errors = %v; want %v", got, want)
+	}
+	if update := f.store.updates[f.league.ID]; update["country"] != "ET" {
+		t.Errorf("league update = %v; want country ET", update)
+	}
+	if plan.Summary.Created != 1 || plan.Summary.Updated != 1 || plan.Summary.Failed != 3 {
+		t.Errorf("summary = %+v", plan.Summary)
+	}
+}
+
+func TestImportIsIdempotent(t *testing.T) {
+	f := newFixture()
+	plan := run(t, f.store, KindClubs, Row{"name": "Saint George", "league": f.league.Name})
+	if got := actions(plan); !slices.Equal(got, []string{ActionUnchanged}) {
+		t.Errorf("actions = %v; want unchanged", got)
+	}
+	if len(f.store.updates) > 0 || len(f.store.created) > 0 {
+		t.Errorf("an unchanged row wrote %v %v", f.store.updates, f.store.created)
+	}
+}
+
+func TestClubRowsJoinTheCurrentSeason(t *testing.T) {
+	f := newFixture()
+	other := models.Season{ID: bson.NewObjectID(), LeagueID: f.other.ID, Name: "2024/25", Current: true, ClubIDs: []bson.ObjectID{f.third.ID}}
+	f.store.seasons = append(f.store.seasons, other)
+
+	plan := run(t, f.store, KindClubs,
+		Row{"name": "Bahir Dar Kenema", "league": f.league.Name},
+		Row{"name": f.third.Name, "league": f.league.Name},
+		Row{"name": "Welwalo Adigrat"}, // no league for a new club
+	)
+	if got, want := errorFields(plan), []string{"3:league"}; !slices.Equal(got, want) {
+		t.Fatalf("errors = %v; want %v", got, want)
+	}
+	created := plan.Changes[0].ID
+
+	season, _ := f.store.FindCurrentSeason(context.Background(), f.league.ID)
+	if want := []bson.ObjectID{f.home.ID, f.away.ID, created, f.third.ID}; !slices.Equal(season.ClubIDs, want) {
+		t.Errorf("current season clubs = %v; want %v", season.ClubIDs, want)
+	}
+	former, _ := f.store.FindCurrentSeason(context.Background(), f.other.ID)
+	if len(former.ClubIDs) != 0 {
+		t.Errorf("former season clubs = %v; want the moved club gone", former.ClubIDs)
+	}
+	if len(f.store.applied) != 2 {
+		t.Errorf("applied current seasons of %v; want both leagues", f.store.applied)
+	}
+	if len(plan.Standings) != 2 {
+		t.Errorf("standings to recompute = %v; want both leagues' seasons", plan.Standings)
+	}
+}
+
+func TestFixtureRows(t *testing.T) {
+	f := newFixture()
+	existing := models.Match{
+		ID: bson.NewObjectID(), LeagueID: f.league.ID, Season: "2024/25",
+		HomeClubID: f.home.ID, AwayClubID: f.away.ID, Status: models.MatchScheduled, Version: 1,
+	}
+	f.store.matches = []models.Match{existing}
+
+	plan := run(t, f.store, KindFixtures,
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.home.Name, "away": f.away.Name, "venue": "Addis Ababa Stadium"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.away.Name, "away": f.home.Name, "kickoff_at": "2025-03-01 16:00"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.home.Name, "away": f.home.Name, "kickoff_at": "2025-03-01 16:00"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.home.Name, "away": f.third.Name, "kickoff_at": "2025-03-01 16:00"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.away.Name, "away": f.home.Name},
+		Row{"league": f.league.Name, "season": "24-25", "home": f.away.Name, "away": f.home.Name, "kickoff_at": "tomorrow"},
+	)
+	if got, want := actions(plan), []string{ActionUpdate, ActionCreate}; !slices.Equal(got, want) {
+		t.Errorf("actions = %v; want %v", got, want)
+	}
+	if got, want := errorFields(plan), []string{"3:away", "4:away", "5:kickoff_at", "6:season", "6:kickoff_at"}; !slices.Equal(got, want) {
+		t.Errorf("errors = %v; want %v", got, want)
+	}
+
+	match := plan.Changes[1].Document.(models.Match)
+	if got := match.KickoffAt.UTC().Format("2006-01-02T15:04"); got != "2025-03-01T13:00" {
+		t.Errorf("kickoff = %s; want 16:00 Ethiopian time", got)
+	}
+}
+
+func TestResultRows(t *testing.T) {
+	f := newFixture()
+	cancelled := models.Match{
+		ID: bson.NewObjectID(), LeagueID: f.league.ID, Season: "2024/25",
+		HomeClubID: f.away.ID, AwayClubID: f.home.ID, Status: models.MatchCancelled, Version: 1,
+	}
+	f.store.matches = []models.Match{cancelled}
+
+	plan := run(t, f.store, KindResults,
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.home.Name, "away": f.away.Name, "home_score": "1", "away_score": "0"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.home.Name, "away": f.away.Name, "home_score": "3", "away_score": "1", "kickoff_at": "2025-03-01 16:00"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.away.Name, "away": f.home.Name, "home_score": "0", "away_score": "0"},
+		Row{"league": f.league.Name, "season": "2024/25", "home": f.home.Name, "away": f.third.Name, "home_score": "-1"},
+	)
+	if got, want := actions(plan), []string{ActionCreate}; !slices.Equal(got, want) {
+		t.Errorf("actions = %v; want %v", got, want)
+	}
+	if got, want := errorFields(plan), []string{"1:kickoff_at", "3:home", "4:away", "4:home_score", "4:away_score"}; !slices.Equal(got, want) {
+		t.Errorf("errors = %v; want %v", got, want)
+	}
+
+	match := plan.Changes[0].Document.(models.Match)
+	if match.Status != models.MatchFinished || *match.Score != (models.Score{Home: 3, Away: 1}) {
+		t.Errorf("created match = %s %v; want finished 3-1", match.Status, match.Score)
+	}
+	if len(f.store.events) != 1 || f.store.events[0].MatchID != match.ID || f.store.events[0].Type != models.EventFinalScore {
+		t.Errorf("timeline events = %+v; want the final score of the new match", f.store.events)
+	}
+	if len(plan.Standings) != 1 || plan.Standings[0].LeagueID != f.league.ID {
+		t.Errorf("standings to recompute = %v", plan.Standings)
+	}
+}
+
+func TestPlayerRowsFillSquads(t *testing.T) {
+	f := newFixture()
+	known := models.Player{ID: bson.NewObjectID(), Name: "Getaneh Kebede", Position: "forward", Version: 1}
+	squad := models.Squad{ID: bson.NewObjectID(), ClubID: f.away.ID, Season: "2024/25", PlayerIDs: []bson.ObjectID{}, Version: 1}
+	f.store.players = []models.Player{known}
+	f.store.squads = []models.Squad{squad}
+
+	plan := run(t, f.store, KindPlayers,
+		Row{"name": "Abel Yalew", "position": "forward", "club": f.home.Name, "season": "2024/25"},
+		Row{"name": "Abel Yalew", "club": f.away.Name, "season": "2024/25"},
+		Row{"name": "Getaneh Kebede", "shirt_number": "9", "club": f.away.Name, "season": "2024/25"},
+		Row{"name": "Shimeles Bekele"}, // new players need a position
+		Row{"name": "Abel Yalew", "position": "striker"},
+	)
+	if got, want := errorFields(plan), []string{"4:position", "5:position"}; !slices.Equal(got, want) {
+		t.Fatalf("errors = %v; want %v", got, want)
+	}
+	abel := plan.Changes[0].ID
+	if plan.Changes[1].ID != abel {
+		t.Errorf("second row for the new player planned %v; want the player of row 1", plan.Changes[1])
+	}
+	if update := f.store.updates[known.ID]; update["shirt_number"] != 9 {
+		t.Errorf("player update = %v; want shirt number 9", update)
+	}
+
+	var newSquad *models.Squad
+	for _, doc := range f.store.created {
+		if s, ok := doc.(models.Squad); ok {
+			newSquad = &s
+		}
+	}
+	if newSquad == nil || newSquad.ClubID != f.home.ID || !slices.Equal(newSquad.PlayerIDs, []bson.ObjectID{abel}) {
+		t.Errorf("new squad = %+v; want Saint George with the new player", newSquad)
+	}
+	if got := f.store.updates[squad.ID]["player_ids"]; !slices.Equal(got.([]bson.ObjectID), []bson.ObjectID{abel, known.ID}) {
+		t.Errorf("updated squad players = %v", got)
+	}
+}
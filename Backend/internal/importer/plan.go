@@ -0,0 +1,371 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// seasonPattern is the season format the API accepts, e.g. 2024 or 2024/25
+var seasonPattern = regexp.MustCompile(`^\d{4}(/\d{2})?$`)
+
+// localZone is the zone kickoff times without an offset are read in, as for
+// match days in the API
+var localZone = time.FixedZone("EAT", 3*60*60)
+
+// maxSquadPlayers matches the cap the admin API puts on a squad
+const maxSquadPlayers = 60
+
+// index finds documents by ID or by any of their names, ignoring case and
+// spacing
+type index[T any] struct {
+	noun   string
+	id     func(*T) bson.ObjectID
+	names  func(*T) []string
+	byID   map[bson.ObjectID]*T
+	byName map[string][]*T
+}
+
+func newIndex[T any](noun string, docs []T, id func(*T) bson.ObjectID, names func(*T) []string) *index[T] {
+	x := &index[T]{
+		noun:   noun,
+		id:     id,
+		names:  names,
+		byID:   map[bson.ObjectID]*T{},
+		byName: map[string][]*T{},
+	}
+	for i := range docs {
+		x.add(&docs[i])
+	}
+	return x
+}
+
+func (x *index[T]) add(doc *T) {
+	x.byID[x.id(doc)] = doc
+	seen := map[string]bool{}
+	for _, name := range x.names(doc) {
+		key := fold(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		x.byName[key] = append(x.byName[key], doc)
+	}
+}
+
+func (x *index[T]) find(ref string) []*T {
+	if id, err := bson.ObjectIDFromHex(ref); err == nil {
+		if doc, ok := x.byID[id]; ok {
+			return []*T{doc}
+		}
+		return nil
+	}
+	return x.byName[fold(ref)]
+}
+
+// key identifies what a row names for spotting repeats: the document it
+// matches, so two names of one document count as a repeat, or else the name
+func (x *index[T]) key(name string, found []*T) string {
+	if len(found) == 1 {
+		return x.noun + ":" + x.id(found[0]).Hex()
+	}
+	return x.noun + ":" + fold(name)
+}
+
+func fold(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// names lists a document's canonical name, its translations and aliases
+func names(name string, aliases []string, texts ...*models.MultiLangString) []string {
+	out := append([]string{name}, aliases...)
+	for _, text := range texts {
+		if text != nil {
+			out = append(out, text.EN, text.AM, text.OM)
+		}
+	}
+	return out
+}
+
+func leagueIndex(leagues []models.League) *index[models.League] {
+	return newIndex("league", leagues,
+		func(l *models.League) bson.ObjectID { return l.ID },
+		func(l *models.League) []string { return names(l.Name, l.Aliases, l.Names, l.ShortNames) })
+}
+
+func clubIndex(clubs []models.Club) *index[models.Club] {
+	return newIndex("club", clubs,
+		func(c *models.Club) bson.ObjectID { return c.ID },
+		func(c *models.Club) []string { return names(c.Name, c.Aliases, c.Names, c.ShortNames) })
+}
+
+func playerIndex(players []models.Player) *index[models.Player] {
+	return newIndex("player", players,
+		func(p *models.Player) bson.ObjectID { return p.ID },
+		func(p *models.Player) []string { return names(p.Name, nil, p.Names) })
+}
+
+type squadKey struct {
+	clubID bson.ObjectID
+	season string
+}
+
+// squadAdd collects the players rows add to one squad
+type squadAdd struct {
+	club    *models.Club
+	season  string
+	players []bson.ObjectID
+	rows    []int
+}
+
+// planner holds what Prepare has loaded and planned so far
+type planner struct {
+	ctx   context.Context
+	store Store
+	plan  *Plan
+	err   error          // a failed store read, which stops the import
+	seen  map[string]int // row keys to the first row that had them
+
+	leagues *index[models.League]
+	clubs   *index[models.Club]
+	players *index[models.Player]
+	seasons map[repository.StandingsKey]*models.Season
+	matches map[repository.StandingsKey][]models.Match
+	squads  map[squadKey]*models.Squad
+
+	squadAdds  map[squadKey]*squadAdd
+	squadOrder []squadKey
+
+	currentSeasons map[bson.ObjectID]*models.Season
+	leagueMoves    []leagueMove
+}
+
+// leagueMove is a club a row puts into a league, from its former league if
+// it had one
+type leagueMove struct {
+	row      int
+	club     bson.ObjectID
+	from, to bson.ObjectID
+}
+
+func (p *planner) load(kind string) error {
+	p.seasons = map[repository.StandingsKey]*models.Season{}
+	p.matches = map[repository.StandingsKey][]models.Match{}
+	p.squads = map[squadKey]*models.Squad{}
+	p.squadAdds = map[squadKey]*squadAdd{}
+	p.currentSeasons = map[bson.ObjectID]*models.Season{}
+
+	if kind != KindPlayers {
+		leagues, err := p.store.GetLeagues(p.ctx)
+		if err != nil {
+			return err
+		}
+		p.leagues = leagueIndex(slices.Clone(leagues))
+	}
+	if kind != KindLeagues {
+		clubs, err := p.store.GetClubs(p.ctx)
+		if err != nil {
+			return err
+		}
+		p.clubs = clubIndex(slices.Clone(clubs))
+	}
+	if kind == KindPlayers {
+		players, err := p.store.GetPlayers(p.ctx, bson.M{})
+		if err != nil {
+			return err
+		}
+		p.players = playerIndex(players)
+
+		squads, err := p.store.GetSquads(p.ctx, bson.M{})
+		if err != nil {
+			return err
+		}
+		for i := range squads {
+			p.squads[squadKey{squads[i].ClubID, squads[i].Season}] = &squads[i]
+		}
+	}
+	return nil
+}
+
+func (p *planner) fail(n int, field, message string) {
+	p.plan.Errors = append(p.plan.Errors, models.ImportError{Row: n, Field: field, Message: message})
+}
+
+// failed reports whether the row has errors
+func (p *planner) failed(n int) bool {
+	errs := p.plan.Errors
+	return len(errs) > 0 && errs[len(errs)-1].Row == n
+}
+
+// duplicate reports whether an earlier row already has the key
+func (p *planner) duplicate(n int, field, key string) bool {
+	if first, ok := p.seen[key]; ok {
+		p.fail(n, field, fmt.Sprintf("repeats row %d", first))
+		return true
+	}
+	p.seen[key] = n
+	return false
+}
+
+// resolve finds the one document a reference names, or records why not
+func resolve[T any](p *planner, x *index[T], n int, field, ref string) *T {
+	if ref == "" {
+		p.fail(n, field, "is required")
+		return nil
+	}
+	found := x.find(ref)
+	switch len(found) {
+	case 1:
+		return found[0]
+	case 0:
+		p.fail(n, field, fmt.Sprintf("no %s named %q", x.noun, ref))
+	default:
+		p.fail(n, field, fmt.Sprintf("%q matches %d %ss; use the ID instead", ref, len(found), x.noun))
+	}
+	return nil
+}
+
+func (p *planner) url(n int, row Row, field string) string {
+	value := row[field]
+	if value == "" {
+		return ""
+	}
+	u, err := url.ParseRequestURI(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.fail(n, field, "must be a valid http(s) URL")
+	}
+	return value
+}
+
+// number reads a whole number column, reporting whether it was given
+func (p *planner) number(n int, row Row, field string, min, max int) (int, bool) {
+	raw, ok := row[field]
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min || value > max {
+		p.fail(n, field, fmt.Sprintf("must be a whole number from %d to %d", min, max))
+		return 0, false
+	}
+	return value, true
+}
+
+func (p *planner) season(n int, row Row, field string) string {
+	season := row[field]
+	switch {
+	case season == "":
+		p.fail(n, field, "is required")
+	case !seasonPattern.MatchString(season):
+		p.fail(n, field, "must look like 2024 or 2024/25")
+	}
+	return season
+}
+
+// leagueSeason returns a league's season record, or nil when there is none
+func (p *planner) leagueSeason(key repository.StandingsKey) *models.Season {
+	if season, ok := p.seasons[key]; ok {
+		return season
+	}
+	season, err := p.store.FindSeason(p.ctx, key.LeagueID, key.Season)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		season, err = nil, nil
+	}
+	if err != nil {
+		p.err = err
+		return nil
+	}
+	p.seasons[key] = season
+	return season
+}
+
+// currentSeason returns a league's current season, or nil when it has none
+func (p *planner) currentSeason(leagueID bson.ObjectID) *models.Season {
+	if season, ok := p.currentSeasons[leagueID]; ok {
+		return season
+	}
+	season, err := p.store.FindCurrentSeason(p.ctx, leagueID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		season, err = nil, nil
+	}
+	if err != nil {
+		p.err = err
+		return nil
+	}
+	p.currentSeasons[leagueID] = season
+	return season
+}
+
+func (p *planner) seasonMatches(key repository.StandingsKey) []models.Match {
+	if matches, ok := p.matches[key]; ok {
+		return matches
+	}
+	matches, err := p.store.GetMatches(p.ctx, bson.M{"league_id": key.LeagueID, "season": key.Season}, true, 0)
+	if err != nil {
+		p.err = err
+		return nil
+	}
+	p.matches[key] = matches
+	return matches
+}
+
+// affects notes a league season whose table must be recomputed
+func (p *planner) affects(key repository.StandingsKey) {
+	if !slices.Contains(p.plan.Standings, key) {
+		p.plan.Standings = append(p.plan.Standings, key)
+	}
+}
+
+// translated overlays the translations a row gives on the current ones
+func translated(current *models.MultiLangString, en, am, om string) *models.MultiLangString {
+	if en == "" && am == "" && om == "" {
+		return current
+	}
+	text := models.MultiLangString{}
+	if current != nil {
+		text = *current
+	}
+	if en != "" {
+		text.EN = en
+	}
+	if am != "" {
+		text.AM = am
+	}
+	if om != "" {
+		text.OM = om
+	}
+	return &text
+}
+
+// set adds a field to the update when the row changes its value
+func set(update bson.M, field string, current, want interface{}) {
+	if !reflect.DeepEqual(current, want) {
+		update[field] = want
+	}
+}
+
+// created plans the insert of a new document
+func created(id bson.ObjectID, label string, doc interface{}, apply func(ctx context.Context) error) *Change {
+	return &Change{Action: ActionCreate, ID: id, Label: label, Document: doc, apply: apply}
+}
+
+// updated plans the update of an existing document, if the row changes it
+func updated(id bson.ObjectID, label string, update bson.M, apply func(ctx context.Context) error) *Change {
+	if len(update) == 0 {
+		return &Change{Action: ActionUnchanged, ID: id, Label: label}
+	}
+	return &Change{Action: ActionUpdate, ID: id, Label: label, Fields: update, apply: apply}
+}
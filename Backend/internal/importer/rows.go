@@ -0,0 +1,678 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+	"fanzone/internal/repository"
+)
+
+// leagueRow plans a league from the columns name, name_am, name_om,
+// short_name, short_name_am, short_name_om, country, logo_url and website
+func (p *planner) leagueRow(n int, row Row) *Change {
+	name := row["name"]
+	if name == "" {
+		p.fail(n, "name", "is required")
+		return nil
+	}
+	logoURL := p.url(n, row, "logo_url")
+	website := p.url(n, row, "website")
+	if p.failed(n) {
+		return nil
+	}
+
+	found := p.leagues.find(name)
+	if len(found) > 1 {
+		p.fail(n, "name", fmt.Sprintf("%q matches %d leagues", name, len(found)))
+		return nil
+	}
+	if p.duplicate(n, "name", p.leagues.key(name, found)) {
+		return nil
+	}
+	if len(found) == 0 {
+		now := time.Now()
+		league := models.League{
+			ID:         bson.NewObjectID(),
+			Name:       name,
+			Names:      translated(nil, "", row["name_am"], row["name_om"]),
+			ShortNames: translated(nil, row["short_name"], row["short_name_am"], row["short_name_om"]),
+			LogoURL:    logoURL,
+			Country:    row["country"],
+			Website:    website,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Version:    1,
+		}
+		return created(league.ID, name, league, func(ctx context.Context) error {
+			return p.store.CreateLeague(ctx, league)
+		})
+	}
+
+	current := found[0]
+	update := bson.M{}
+	set(update, "names", current.Names, translated(current.Names, "", row["name_am"], row["name_om"]))
+	set(update, "short_names", current.ShortNames, translated(current.ShortNames, row["short_name"], row["short_name_am"], row["short_name_om"]))
+	if country := row["country"]; country != "" {
+		set(update, "country", current.Country, country)
+	}
+	if logoURL != "" {
+		set(update, "logo_url", current.LogoURL, logoURL)
+	}
+	if website != "" {
+		set(update, "website", current.Website, website)
+	}
+	return updated(current.ID, current.Name, update, func(ctx context.Context) error {
+		_, err := p.store.UpdateLeague(ctx, current.ID, update, current.Version)
+		return err
+	})
+}
+
+// clubRow plans a club from the columns name, name_am, name_om, short_name,
+// short_name_am, short_name_om, league, logo_url, stadium, stadium_am,
+// stadium_om and website. New clubs need a league.
+func (p *planner) clubRow(n int, row Row) *Change {
+	name := row["name"]
+	if name == "" {
+		p.fail(n, "name", "is required")
+		return nil
+	}
+	logoURL := p.url(n, row, "logo_url")
+	website := p.url(n, row, "website")
+	var league *models.League
+	if ref := row["league"]; ref != "" {
+		league = resolve(p, p.leagues, n, "league", ref)
+	}
+	if p.failed(n) {
+		return nil
+	}
+
+	found := p.clubs.find(name)
+	if len(found) > 1 {
+		p.fail(n, "name", fmt.Sprintf("%q matches %d clubs", name, len(found)))
+		return nil
+	}
+	if p.duplicate(n, "name", p.clubs.key(name, found)) {
+		return nil
+	}
+	if len(found) == 0 {
+		if league == nil {
+			p.fail(n, "league", "is required for a new club")
+			return nil
+		}
+		now := time.Now()
+		club := models.Club{
+			ID:         bson.NewObjectID(),
+			Name:       name,
+			Names:      translated(nil, "", row["name_am"], row["name_om"]),
+			ShortNames: translated(nil, row["short_name"], row["short_name_am"], row["short_name_om"]),
+			LogoURL:    logoURL,
+			LeagueID:   league.ID,
+			Stadium:    translated(nil, row["stadium"], row["stadium_am"], row["stadium_om"]),
+			Website:    website,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Version:    1,
+		}
+		p.moveClub(n, club.ID, bson.ObjectID{}, league.ID)
+		return created(club.ID, name, club, func(ctx context.Context) error {
+			return p.store.CreateClub(ctx, club)
+		})
+	}
+
+	current := found[0]
+	update := bson.M{}
+	set(update, "names", current.Names, translated(current.Names, "", row["name_am"], row["name_om"]))
+	set(update, "short_names", current.ShortNames, translated(current.ShortNames, row["short_name"], row["short_name_am"], row["short_name_om"]))
+	set(update, "stadium", current.Stadium, translated(current.Stadium, row["stadium"], row["stadium_am"], row["stadium_om"]))
+	if league != nil && league.ID != current.LeagueID {
+		update["league_id"] = league.ID
+		p.moveClub(n, current.ID, current.LeagueID, league.ID)
+	}
+	if logoURL != "" {
+		set(update, "logo_url", current.LogoURL, logoURL)
+	}
+	if website != "" {
+		set(update, "website", current.Website, website)
+	}
+	return updated(current.ID, current.Name, update, func(ctx context.Context) error {
+		_, err := p.store.UpdateClub(ctx, current.ID, update, current.Version)
+		return err
+	})
+}
+
+// moveClub notes a club joining a league, whose current season then lists
+// it, as when an admin edits the season
+func (p *planner) moveClub(n int, club, from, to bson.ObjectID) {
+	p.leagueMoves = append(p.leagueMoves, leagueMove{row: n, club: club, from: from, to: to})
+	for _, leagueID := range []bson.ObjectID{from, to} {
+		if leagueID.IsZero() {
+			continue
+		}
+		if season := p.currentSeason(leagueID); season != nil {
+			p.affects(repository.StandingsKey{LeagueID: leagueID, Season: season.Name})
+		}
+	}
+}
+
+// planSeasons adds the clubs that changed league to the current season of
+// their new league and takes them off their old one, leaving out clubs whose
+// rows failed. A season without a club list takes every club of its league,
+// so it is left as it is.
+func (p *planner) planSeasons() {
+	if len(p.leagueMoves) == 0 {
+		return
+	}
+	p.plan.after = append(p.plan.after, func(ctx context.Context, failed map[int]bool) error {
+		members := map[bson.ObjectID][]bson.ObjectID{}
+		var changed []bson.ObjectID
+		edit := func(leagueID, club bson.ObjectID, join bool) {
+			season := p.currentSeasons[leagueID]
+			if season == nil || len(season.ClubIDs) == 0 {
+				return
+			}
+			clubs, ok := members[leagueID]
+			if !ok {
+				clubs = season.ClubIDs
+			}
+			if slices.Contains(clubs, club) == join {
+				return
+			}
+			if join {
+				clubs = append(slices.Clone(clubs), club)
+			} else {
+				clubs = slices.DeleteFunc(slices.Clone(clubs), func(id bson.ObjectID) bool { return id == club })
+			}
+			if !ok {
+				changed = append(changed, leagueID)
+			}
+			members[leagueID] = clubs
+		}
+		for _, move := range p.leagueMoves {
+			if !failed[move.row] {
+				edit(move.from, move.club, false)
+				edit(move.to, move.club, true)
+			}
+		}
+
+		var errs []error
+		for _, leagueID := range changed {
+			season := p.currentSeasons[leagueID]
+			if _, err := p.store.UpdateSeason(ctx, season.ID, bson.M{"club_ids": members[leagueID]}, season.Version); err != nil {
+				errs = append(errs, fmt.Errorf("clubs of the %s season: %w", season.Name, err))
+				continue
+			}
+			if _, err := p.store.ApplyCurrentSeason(ctx, leagueID); err != nil {
+				errs = append(errs, fmt.Errorf("clubs of the %s season: %w", season.Name, err))
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// matchRef is what a fixture or result row says about its match
+type matchRef struct {
+	key        repository.StandingsKey
+	home, away *models.Club
+	kickoff    time.Time // zero when not given
+}
+
+func (m matchRef) label() string {
+	return fmt.Sprintf("%s vs %s (%s)", m.home.Name, m.away.Name, m.key.Season)
+}
+
+// rowKey identifies the match among the rows; a kickoff day tells meetings
+// of the same clubs apart
+func (m matchRef) rowKey() string {
+	key := "match:" + m.key.LeagueID.Hex() + ":" + m.key.Season + ":" + m.home.ID.Hex() + ":" + m.away.ID.Hex()
+	if !m.kickoff.IsZero() {
+		key += ":" + m.kickoff.In(localZone).Format("2006-01-02")
+	}
+	return key
+}
+
+func parseKickoff(raw string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, raw, localZone); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// matchRef reads the league, season, home, away and kickoff_at columns
+func (p *planner) matchRef(n int, row Row) (matchRef, bool) {
+	var ref matchRef
+	league := resolve(p, p.leagues, n, "league", row["league"])
+	season := p.season(n, row, "season")
+	ref.home = resolve(p, p.clubs, n, "home", row["home"])
+	ref.away = resolve(p, p.clubs, n, "away", row["away"])
+	if raw := row["kickoff_at"]; raw != "" {
+		kickoff, ok := parseKickoff(raw)
+		if !ok {
+			p.fail(n, "kickoff_at", "must look like 2024-11-02 16:00 (Ethiopian time) or 2024-11-02T13:00:00Z")
+		}
+		ref.kickoff = kickoff
+	}
+	if p.failed(n) {
+		return ref, false
+	}
+	ref.key = repository.StandingsKey{LeagueID: league.ID, Season: season}
+
+	if ref.home.ID == ref.away.ID {
+		p.fail(n, "away", "must differ from home")
+		return ref, false
+	}
+	if record := p.leagueSeason(ref.key); record != nil && len(record.ClubIDs) > 0 {
+		if !slices.Contains(record.ClubIDs, ref.home.ID) {
+			p.fail(n, "home", "club is not in this league for the season")
+		}
+		if !slices.Contains(record.ClubIDs, ref.away.ID) {
+			p.fail(n, "away", "club is not in this league for the season")
+		}
+	}
+	return ref, !p.failed(n)
+}
+
+// existingMatch finds the match a row refers to, or nil for a new one. When
+// the clubs meet more than once in the season, the kickoff day picks one.
+func (p *planner) existingMatch(n int, ref matchRef) (*models.Match, bool) {
+	var candidates []*models.Match
+	matches := p.seasonMatches(ref.key)
+	for i := range matches {
+		if matches[i].HomeClubID == ref.home.ID && matches[i].AwayClubID == ref.away.ID {
+			candidates = append(candidates, &matches[i])
+		}
+	}
+	if len(candidates) > 1 && !ref.kickoff.IsZero() {
+		day := ref.kickoff.In(localZone).Format("2006-01-02")
+		candidates = slices.DeleteFunc(candidates, func(m *models.Match) bool {
+			return m.KickoffAt.In(localZone).Format("2006-01-02") != day
+		})
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, true
+	case 1:
+		return candidates[0], true
+	}
+	p.fail(n, "kickoff_at", "the clubs meet more than once this season; give the kickoff time of the match")
+	return nil, false
+}
+
+// fixtureRow plans a match from the columns league, season, home, away,
+// kickoff_at and venue. An existing fixture is moved to the new kickoff.
+func (p *planner) fixtureRow(n int, row Row) *Change {
+	ref, ok := p.matchRef(n, row)
+	if !ok || p.duplicate(n, "home", ref.rowKey()) {
+		return nil
+	}
+	match, ok := p.existingMatch(n, ref)
+	if !ok {
+		return nil
+	}
+	venue := row["venue"]
+
+	if match == nil {
+		if ref.kickoff.IsZero() {
+			p.fail(n, "kickoff_at", "is required for a new match")
+			return nil
+		}
+		now := time.Now()
+		created := models.Match{
+			ID:         bson.NewObjectID(),
+			HomeClubID: ref.home.ID,
+			AwayClubID: ref.away.ID,
+			LeagueID:   ref.key.LeagueID,
+			Season:     ref.key.Season,
+			KickoffAt:  ref.kickoff,
+			Venue:      venue,
+			Status:     models.MatchScheduled,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Version:    1,
+		}
+		p.affects(ref.key)
+		return &Change{Action: ActionCreate, ID: created.ID, Label: ref.label(), Document: created, apply: func(ctx context.Context) error {
+			return p.store.CreateMatch(ctx, created)
+		}}
+	}
+
+	update := bson.M{}
+	if !ref.kickoff.IsZero() && !ref.kickoff.Equal(match.KickoffAt) {
+		update["kickoff_at"] = ref.kickoff
+	}
+	if venue != "" {
+		set(update, "venue", match.Venue, venue)
+	}
+	return updated(match.ID, ref.label(), update, func(ctx context.Context) error {
+		_, err := p.store.UpdateMatch(ctx, match.ID, update, match.Version)
+		return err
+	})
+}
+
+// resultRow records a final score from the columns league, season, home,
+// away, home_score, away_score and kickoff_at. A result without a fixture
+// adds the match when kickoff_at is given. The score is also posted to the
+// match timeline as a final_score event, so later events do not undo it.
+func (p *planner) resultRow(n int, row Row) *Change {
+	ref, ok := p.matchRef(n, row)
+	homeScore, hasHome := p.number(n, row, "home_score", 0, 99)
+	awayScore, hasAway := p.number(n, row, "away_score", 0, 99)
+	if _, given := row["home_score"]; !given {
+		p.fail(n, "home_score", "is required")
+	}
+	if _, given := row["away_score"]; !given {
+		p.fail(n, "away_score", "is required")
+	}
+	if !ok || !hasHome || !hasAway || p.duplicate(n, "home", ref.rowKey()) {
+		return nil
+	}
+	match, ok := p.existingMatch(n, ref)
+	if !ok {
+		return nil
+	}
+	score := &models.Score{Home: homeScore, Away: awayScore}
+
+	if match == nil {
+		if ref.kickoff.IsZero() {
+			p.fail(n, "kickoff_at", "no match between the clubs this season; give kickoff_at to add it")
+			return nil
+		}
+		now := time.Now()
+		created := models.Match{
+			ID:         bson.NewObjectID(),
+			HomeClubID: ref.home.ID,
+			AwayClubID: ref.away.ID,
+			LeagueID:   ref.key.LeagueID,
+			Season:     ref.key.Season,
+			KickoffAt:  ref.kickoff,
+			Status:     models.MatchFinished,
+			Score:      score,
+			Period:     models.PeriodFullTime,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			Version:    1,
+		}
+		p.affects(ref.key)
+		return &Change{Action: ActionCreate, ID: created.ID, Label: ref.label(), Document: created, apply: func(ctx context.Context) error {
+			if err := p.store.CreateMatch(ctx, created); err != nil {
+				return err
+			}
+			return p.store.CreateMatchEvent(ctx, finalScore(created.ID, score))
+		}}
+	}
+	if match.Status == models.MatchCancelled {
+		p.fail(n, "home", "the match was cancelled")
+		return nil
+	}
+
+	update := bson.M{}
+	set(update, "score", match.Score, score)
+	set(update, "status", match.Status, models.MatchFinished)
+	set(update, "period", match.Period, models.PeriodFullTime)
+	if len(update) > 0 {
+		p.affects(ref.key)
+	}
+	return updated(match.ID, ref.label(), update, func(ctx context.Context) error {
+		// The event goes first: if the match changes meanwhile, the next
+		// refresh of its timeline still ends on this result
+		if err := p.store.CreateMatchEvent(ctx, finalScore(match.ID, score)); err != nil {
+			return err
+		}
+		_, err := p.store.UpdateMatch(ctx, match.ID, update, match.Version)
+		return err
+	})
+}
+
+// finalScore is the timeline event of an imported result
+func finalScore(matchID bson.ObjectID, score *models.Score) models.MatchEvent {
+	now := time.Now()
+	return models.MatchEvent{
+		ID:        bson.NewObjectID(),
+		MatchID:   matchID,
+		Type:      models.EventFinalScore,
+		Minute:    90,
+		Score:     score,
+		Note:      "Imported result",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// playerRow plans a player from the columns id, name, name_am, name_om,
+// position, shirt_number, nationality and photo_url, and adds them to a
+// squad when club and season are given. A player can appear on several rows,
+// one per squad.
+func (p *planner) playerRow(n int, row Row) *Change {
+	name := row["name"]
+	position := row["position"]
+	if position != "" && !slices.Contains(models.PlayerPositions, position) {
+		p.fail(n, "position", "must be one of: goalkeeper, defender, midfielder, forward")
+	}
+	shirtNumber, hasShirt := p.number(n, row, "shirt_number", models.MinShirtNumber, models.MaxShirtNumber)
+	photoURL := p.url(n, row, "photo_url")
+
+	var club *models.Club
+	var season string
+	if row["club"] != "" || row["season"] != "" {
+		club = resolve(p, p.clubs, n, "club", row["club"])
+		season = p.season(n, row, "season")
+	}
+
+	var current *models.Player
+	switch id := row["id"]; {
+	case id != "":
+		if _, err := bson.ObjectIDFromHex(id); err != nil {
+			p.fail(n, "id", "must be a valid player ID")
+		} else if current = resolve(p, p.players, n, "id", id); current == nil {
+			return nil
+		}
+	case name == "":
+		p.fail(n, "name", "is required")
+	default:
+		found := p.players.find(name)
+		if len(found) > 1 && club != nil {
+			found = p.inSquadsOf(found, club.ID)
+		}
+		if len(found) > 1 {
+			p.fail(n, "name", fmt.Sprintf("%q matches %d players; give their id", name, len(found)))
+		} else if len(found) == 1 {
+			current = found[0]
+		}
+	}
+	if p.failed(n) {
+		return nil
+	}
+
+	identity := "new:" + fold(name)
+	if current != nil {
+		identity = current.ID.Hex()
+	}
+	membership := ""
+	if club != nil {
+		membership = club.ID.Hex() + ":" + season
+	}
+	if p.duplicate(n, "name", "player:"+identity+":"+membership) {
+		return nil
+	}
+
+	var change *Change
+	if current == nil {
+		if position == "" {
+			p.fail(n, "position", "is required for a new player")
+			return nil
+		}
+		now := time.Now()
+		player := models.Player{
+			ID:          bson.NewObjectID(),
+			Name:        name,
+			Names:       translated(nil, "", row["name_am"], row["name_om"]),
+			Position:    position,
+			ShirtNumber: shirtNumber,
+			Nationality: row["nationality"],
+			PhotoURL:    photoURL,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			Version:     1,
+		}
+		// Later rows for the same player find it by name
+		indexed := player
+		p.players.add(&indexed)
+		change = created(player.ID, name, player, func(ctx context.Context) error {
+			return p.store.CreatePlayer(ctx, player)
+		})
+	} else {
+		update := bson.M{}
+		if row["id"] != "" && name != "" {
+			set(update, "name", current.Name, name)
+		}
+		names := translated(current.Names, "", row["name_am"], row["name_om"])
+		set(update, "names", current.Names, names)
+		if position != "" {
+			set(update, "position", current.Position, position)
+		}
+		if hasShirt {
+			set(update, "shirt_number", current.ShirtNumber, shirtNumber)
+		}
+		if nationality := row["nationality"]; nationality != "" {
+			set(update, "nationality", current.Nationality, nationality)
+		}
+		if photoURL != "" {
+			set(update, "photo_url", current.PhotoURL, photoURL)
+		}
+
+		id, version := current.ID, current.Version
+		change = updated(id, current.Name, update, func(ctx context.Context) error {
+			_, err := p.store.UpdatePlayer(ctx, id, update, version)
+			return err
+		})
+		if len(update) > 0 {
+			// Later rows for the same player compare against the new values
+			current.Name = cmpOr(update["name"], current.Name)
+			current.Names = names
+			current.Position = cmpOr(update["position"], current.Position)
+			current.ShirtNumber = cmpOr(update["shirt_number"], current.ShirtNumber)
+			current.Nationality = cmpOr(update["nationality"], current.Nationality)
+			current.PhotoURL = cmpOr(update["photo_url"], current.PhotoURL)
+			current.Version++
+		}
+	}
+
+	if club != nil && p.addToSquad(n, club, season, change.ID) {
+		if change.Fields == nil {
+			change.Fields = bson.M{}
+		}
+		change.Fields["squad"] = club.Name + " " + season
+		if change.Action == ActionUnchanged {
+			change.Action = ActionUpdate
+		}
+	}
+	if p.failed(n) {
+		return nil
+	}
+	return change
+}
+
+// cmpOr returns the planned value of a field, or the current one when the
+// update leaves it alone
+func cmpOr[T any](planned interface{}, current T) T {
+	if v, ok := planned.(T); ok {
+		return v
+	}
+	return current
+}
+
+// inSquadsOf keeps the players that were ever in the club's squad
+func (p *planner) inSquadsOf(players []*models.Player, clubID bson.ObjectID) []*models.Player {
+	var kept []*models.Player
+	for _, player := range players {
+		for key, squad := range p.squads {
+			if key.clubID == clubID && slices.Contains(squad.PlayerIDs, player.ID) {
+				kept = append(kept, player)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// addToSquad plans a player joining a club's squad for a season and reports
+// whether they were not in it yet
+func (p *planner) addToSquad(n int, club *models.Club, season string, playerID bson.ObjectID) bool {
+	key := squadKey{club.ID, season}
+	squad := p.squads[key]
+	size := 0
+	if squad != nil {
+		if slices.Contains(squad.PlayerIDs, playerID) {
+			return false
+		}
+		size = len(squad.PlayerIDs)
+	}
+
+	add := p.squadAdds[key]
+	if add == nil {
+		add = &squadAdd{club: club, season: season}
+		p.squadAdds[key] = add
+		p.squadOrder = append(p.squadOrder, key)
+	}
+	if slices.Contains(add.players, playerID) {
+		return false
+	}
+	if size+len(add.players) >= maxSquadPlayers {
+		p.fail(n, "club", fmt.Sprintf("the squad would have more than %d players", maxSquadPlayers))
+		return false
+	}
+	add.players = append(add.players, playerID)
+	add.rows = append(add.rows, n)
+	return true
+}
+
+// planSquads writes the squads once the players exist, leaving out players
+// whose rows failed
+func (p *planner) planSquads() {
+	for _, key := range p.squadOrder {
+		add, squad := p.squadAdds[key], p.squads[key]
+		p.plan.after = append(p.plan.after, func(ctx context.Context, failed map[int]bool) error {
+			var joining []bson.ObjectID
+			for i, id := range add.players {
+				if !failed[add.rows[i]] {
+					joining = append(joining, id)
+				}
+			}
+			if len(joining) == 0 {
+				return nil
+			}
+
+			var err error
+			if squad == nil {
+				now := time.Now()
+				err = p.store.CreateSquad(ctx, models.Squad{
+					ID:        bson.NewObjectID(),
+					ClubID:    add.club.ID,
+					Season:    add.season,
+					PlayerIDs: joining,
+					CreatedAt: now,
+					UpdatedAt: now,
+					Version:   1,
+				})
+			} else {
+				players := append(slices.Clone(squad.PlayerIDs), joining...)
+				_, err = p.store.UpdateSquad(ctx, squad.ID, bson.M{"player_ids": players}, squad.Version)
+			}
+			if err != nil {
+				return fmt.Errorf("squad of %s for %s: %w", add.club.Name, add.season, err)
+			}
+			return nil
+		})
+	}
+}
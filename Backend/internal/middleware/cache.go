@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache-Control policies for the public API
+const (
+	// CacheReference suits rarely changing data such as clubs and leagues
+	CacheReference = "public, max-age=300"
+	// CacheContent suits news, highlights and public feeds
+	CacheContent = "public, max-age=30"
+	// CachePrivate lets clients keep per-user responses but revalidate each time
+	CachePrivate = "private, no-cache"
+)
+
+// bufferedWriter holds the response body back so its ETag can be computed
+// before anything is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ConditionalGET answers GET requests with an ETag and the given
+// Cache-Control, and with 304 Not Modified when the client's copy is current.
+// Handlers may set their own ETag (such as a document version) and
+// Last-Modified; otherwise the ETag is a hash of the body.
+func ConditionalGET(cacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		w := &bufferedWriter{ResponseWriter: original}
+		c.Writer = w
+		c.Next()
+		c.Writer = original
+
+		header := original.Header()
+		if original.Status() != http.StatusOK {
+			original.Write(w.body.Bytes())
+			return
+		}
+
+		if header.Get("ETag") == "" {
+			sum := sha256.Sum256(w.body.Bytes())
+			header.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+		}
+		if header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		if notModified(c.Request, header) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.Write(w.body.Bytes())
+	}
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tags were sent (RFC 9110, section 13.2.2).
+func notModified(r *http.Request, header http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, header.Get("ETag"))
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	lastModified := header.Get("Last-Modified")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// etagMatches uses weak comparison, as If-None-Match requires: W/"x" matches
+// "x". CDNs weaken strong tags when they compress responses.
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const modified = "Mon, 15 Jan 2024 10:00:00 GMT"
+
+func cacheRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ConditionalGET(CacheContent))
+	r.GET("/hashed", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "Buna"})
+	})
+	r.GET("/versioned", func(c *gin.Context) {
+		c.Header("ETag", `"v3"`)
+		c.Header("Last-Modified", modified)
+		c.Header("Cache-Control", CachePrivate)
+		c.JSON(http.StatusOK, gin.H{"name": "Buna"})
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Club not found"})
+	})
+	r.POST("/versioned", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "Buna"})
+	})
+	return r
+}
+
+func TestConditionalGET(t *testing.T) {
+	r := cacheRouter()
+
+	// The hashed ETag is whatever the first plain request returns
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/hashed", nil))
+	hashed := first.Header().Get("ETag")
+	if hashed == "" || first.Header().Get("Cache-Control") != CacheContent {
+		t.Fatalf("headers %v; want a hashed ETag and %q", first.Header(), CacheContent)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		status  int
+		etag    string
+		cache   string
+	}{
+		{"hashed body", http.MethodGet, "/hashed", nil, http.StatusOK, hashed, CacheContent},
+		{"hashed match", http.MethodGet, "/hashed", map[string]string{"If-None-Match": hashed}, http.StatusNotModified, hashed, CacheContent},
+		{"stale tag", http.MethodGet, "/hashed", map[string]string{"If-None-Match": `"old"`}, http.StatusOK, hashed, CacheContent},
+		{"handler tag and policy", http.MethodGet, "/versioned", nil, http.StatusOK, `"v3"`, CachePrivate},
+		{"tag in a list", http.MethodGet, "/versioned", map[string]string{"If-None-Match": `"v1", "v3"`}, http.StatusNotModified, `"v3"`, CachePrivate},
+		{"weakened tag", http.MethodGet, "/versioned", map[string]string{"If-None-Match": `W/"v3"`}, http.StatusNotModified, `"v3"`, CachePrivate},
+		{"any tag", http.MethodGet, "/versioned", map[string]string{"If-None-Match": "*"}, http.StatusNotModified, `"v3"`, CachePrivate},
+		{"not modified since", http.MethodGet, "/versioned", map[string]string{"If-Modified-Since": modified}, http.StatusNotModified, `"v3"`, CachePrivate},
+		{"modified since", http.MethodGet, "/versioned", map[string]string{"If-Modified-Since": "Sun, 14 Jan 2024 10:00:00 GMT"}, http.StatusOK, `"v3"`, CachePrivate},
+		{"tags win over dates", http.MethodGet, "/versioned", map[string]string{"If-None-Match": `"v2"`, "If-Modified-Since": modified}, http.StatusOK, `"v3"`, CachePrivate},
+		{"errors are not tagged", http.MethodGet, "/missing", map[string]string{"If-None-Match": "*"}, http.StatusNotFound, "", ""},
+		{"writes are not tagged", http.MethodPost, "/versioned", map[string]string{"If-None-Match": "*"}, http.StatusOK, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d; want %d", tt.name, w.Code, tt.status)
+		}
+		if got := w.Header().Get("ETag"); got != tt.etag {
+			t.Errorf("%s: ETag %q; want %q", tt.name, got, tt.etag)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tt.cache {
+			t.Errorf("%s: Cache-Control %q; want %q", tt.name, got, tt.cache)
+		}
+		if tt.status == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("%s: 304 with a body: %s", tt.name, w.Body.String())
+		}
+		if tt.status == http.StatusOK && w.Body.Len() == 0 {
+			t.Errorf("%s: 200 without a body", tt.name)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		header, etag string
+		want         bool
+	}{
+		{`"a"`, `"a"`, true},
+		{`W/"a"`, `"a"`, true},
+		{`"a"`, `W/"a"`, true},
+		{` "b" , "a" `, `"a"`, true},
+		{`"b"`, `"a"`, false},
+		{`"a"`, ``, false},
+		{`*`, `"a"`, true},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, tt.etag); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %v; want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
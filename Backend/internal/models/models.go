@@ -7,15 +7,19 @@ import (
 )
 
 type User struct {
-	ID              bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name            string        `bson:"name" json:"name"`
-	Email           string        `bson:"email" json:"email"`
-	Password        string        `bson:"password" json:"-"`
-	ProfileImageURL string        `bson:"profile_image_url,omitempty" json:"profile_image_url,omitempty"`
-	Language        string        `bson:"language" json:"language"`
-	FavClubID       bson.ObjectID `bson:"fav_club_id,omitempty" json:"fav_club_id"`
-	Role            string        `bson:"role" json:"role"`
-	CreatedAt       time.Time     `bson:"created_at" json:"created_at"`
+	ID                  bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name                string          `bson:"name" json:"name"`
+	Email               string          `bson:"email" json:"email"`
+	Password            string          `bson:"password" json:"-"`
+	ProfileImageURL     string          `bson:"profile_image_url,omitempty" json:"profile_image_url,omitempty"`
+	Language            string          `bson:"language" json:"language"`
+	FavClubID           bson.ObjectID   `bson:"fav_club_id,omitempty" json:"fav_club_id"` // primary club, always followed
+	FollowedClubIDs     []bson.ObjectID `bson:"followed_club_ids,omitempty" json:"followed_club_ids"`
+	FollowedLeagueIDs   []bson.ObjectID `bson:"followed_league_ids,omitempty" json:"followed_league_ids"`
+	PreferredCategories []string        `bson:"preferred_categories,omitempty" json:"preferred_categories"` // boosted in the ranked feed
+	CalendarToken       string          `bson:"calendar_token,omitempty" json:"-"`                          // secret in the fixtures calendar URL
+	Role                string          `bson:"role" json:"role"`
+	CreatedAt           time.Time       `bson:"created_at" json:"created_at"`
 }
 
 type Admin struct {
@@ -36,18 +40,46 @@ type RefreshTokenSession struct {
 	ExpiresAt time.Time     `bson:"expires_at"`
 }
 
+// League and Club keep Name (and Country) as the canonical, usually English,
+// value; the optional MultiLangString fields hold translations of it.
 type League struct {
-	ID      bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name    string        `bson:"name" json:"name"`
-	LogoURL string        `bson:"logo_url" json:"logo_url"`
-	Country string        `bson:"country" json:"country"`
+	ID           bson.ObjectID    `bson:"_id,omitempty" json:"id"`
+	Name         string           `bson:"name" json:"name"`
+	Names        *MultiLangString `bson:"names,omitempty" json:"names,omitempty"`
+	ShortNames   *MultiLangString `bson:"short_names,omitempty" json:"short_names,omitempty"`
+	Aliases      []string         `bson:"aliases,omitempty" json:"aliases,omitempty"` // alternative names for autocomplete
+	LogoURL      string           `bson:"logo_url" json:"logo_url"`
+	Country      string           `bson:"country" json:"country"`
+	CountryNames *MultiLangString `bson:"country_names,omitempty" json:"country_names,omitempty"`
+	Colors       []string         `bson:"colors,omitempty" json:"colors,omitempty"` // "#RRGGBB", primary first
+	FoundedYear  int              `bson:"founded_year,omitempty" json:"founded_year,omitempty"`
+	Website      string           `bson:"website,omitempty" json:"website,omitempty"`
+	// Tiebreakers orders clubs level on points; empty means the defaults
+	Tiebreakers []string      `bson:"tiebreakers,omitempty" json:"tiebreakers,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at" json:"updated_at"`
+	Version     int64         `bson:"version" json:"version"`
+	DeletedAt   *time.Time    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy   bson.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
 }
 
 type Club struct {
-	ID       bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name     string        `bson:"name" json:"name"`
-	LogoURL  string        `bson:"logo_url" json:"logo_url"`
-	LeagueID bson.ObjectID `bson:"league_id" json:"league_id"`
+	ID          bson.ObjectID    `bson:"_id,omitempty" json:"id"`
+	Name        string           `bson:"name" json:"name"`
+	Names       *MultiLangString `bson:"names,omitempty" json:"names,omitempty"`
+	ShortNames  *MultiLangString `bson:"short_names,omitempty" json:"short_names,omitempty"`
+	Aliases     []string         `bson:"aliases,omitempty" json:"aliases,omitempty"` // alternative names for autocomplete
+	LogoURL     string           `bson:"logo_url" json:"logo_url"`
+	LeagueID    bson.ObjectID    `bson:"league_id" json:"league_id"`
+	Colors      []string         `bson:"colors,omitempty" json:"colors,omitempty"` // "#RRGGBB", primary first
+	FoundedYear int              `bson:"founded_year,omitempty" json:"founded_year,omitempty"`
+	Stadium     *MultiLangString `bson:"stadium,omitempty" json:"stadium,omitempty"`
+	Website     string           `bson:"website,omitempty" json:"website,omitempty"`
+	CreatedAt   time.Time        `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `bson:"updated_at" json:"updated_at"`
+	Version     int64            `bson:"version" json:"version"`
+	DeletedAt   *time.Time       `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy   bson.ObjectID    `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
 }
 
 type MultiLangString struct {
@@ -56,30 +88,255 @@ type MultiLangString struct {
 	OM string `bson:"om" json:"om"`
 }
 
+// ContentCategories are the categories an article can be filed under. The
+// first three are used by the dashboard, the rest by older mobile clients.
+// Articles saved with any other value keep it until it is changed.
+var ContentCategories = []string{"News", "Transfer", "Match", "match_report", "transfer_news", "injury_update", "league_news"}
+
 type Content struct {
+	ID          bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Title       MultiLangString `bson:"title" json:"title"`
+	Body        MultiLangString `bson:"body" json:"body"`
+	ImageURL    string          `bson:"image_url" json:"image_url"`
+	Category    string          `bson:"category" json:"category"`
+	ClubID      bson.ObjectID   `bson:"club_id,omitempty" json:"club_id"`
+	LeagueIDs   []bson.ObjectID `bson:"league_ids,omitempty" json:"league_ids,omitempty"` // league-wide news
+	MatchID     bson.ObjectID   `bson:"match_id,omitempty" json:"match_id,omitzero"`
+	PlayerIDs   []bson.ObjectID `bson:"player_ids,omitempty" json:"player_ids,omitempty"` // players the article is about
+	Views       int64           `bson:"views,omitempty" json:"views"`
+	Breaking    bool            `bson:"breaking,omitempty" json:"breaking"`
+	PinnedUntil *time.Time      `bson:"pinned_until,omitempty" json:"pinned_until,omitempty"` // top of feeds until then
+	CreatedAt   time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time       `bson:"updated_at" json:"updated_at"`
+	Version     int64           `bson:"version" json:"version"`
+	DeletedAt   *time.Time      `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy   bson.ObjectID   `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
+}
+
+type Highlight struct {
+	ID          bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	MatchTitle  string          `bson:"match_title" json:"match_title"`
+	YoutubeURL  string          `bson:"youtube_url" json:"youtube_url"`
+	ClubIDs     []bson.ObjectID `bson:"club_ids" json:"club_ids"`
+	LeagueIDs   []bson.ObjectID `bson:"league_ids,omitempty" json:"league_ids,omitempty"`
+	MatchID     bson.ObjectID   `bson:"match_id,omitempty" json:"match_id,omitzero"`
+	PlayerIDs   []bson.ObjectID `bson:"player_ids,omitempty" json:"player_ids,omitempty"` // players shown
+	Views       int64           `bson:"views,omitempty" json:"views"`
+	Breaking    bool            `bson:"breaking,omitempty" json:"breaking"`
+	PinnedUntil *time.Time      `bson:"pinned_until,omitempty" json:"pinned_until,omitempty"`
+	CreatedAt   time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time       `bson:"updated_at" json:"updated_at"`
+	Version     int64           `bson:"version" json:"version"`
+	DeletedAt   *time.Time      `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy   bson.ObjectID   `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
+}
+
+// Season is a season of a league and the clubs playing in it. Clubs of a
+// league's current season have that league as their LeagueID.
+type Season struct {
 	ID        bson.ObjectID   `bson:"_id,omitempty" json:"id"`
-	Title     MultiLangString `bson:"title" json:"title"`
-	Body      MultiLangString `bson:"body" json:"body"`
-	ImageURL  string          `bson:"image_url" json:"image_url"`
-	Category  string          `bson:"category" json:"category"`
-	ClubID    bson.ObjectID   `bson:"club_id,omitempty" json:"club_id"`
+	LeagueID  bson.ObjectID   `bson:"league_id" json:"league_id"`
+	Name      string          `bson:"name" json:"name"` // e.g. "2024/25", as used by matches
+	StartDate *time.Time      `bson:"start_date,omitempty" json:"start_date,omitempty"`
+	EndDate   *time.Time      `bson:"end_date,omitempty" json:"end_date,omitempty"`
+	Current   bool            `bson:"current" json:"current"`
+	ClubIDs   []bson.ObjectID `bson:"club_ids" json:"club_ids"`
 	CreatedAt time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `bson:"updated_at" json:"updated_at"`
+	Version   int64           `bson:"version" json:"version"`
+	DeletedAt *time.Time      `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy bson.ObjectID   `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
 }
 
-type Highlight struct {
-	ID         bson.ObjectID   `bson:"_id,omitempty" json:"id"`
-	MatchTitle string          `bson:"match_title" json:"match_title"`
-	YoutubeURL string          `bson:"youtube_url" json:"youtube_url"`
-	ClubIDs    []bson.ObjectID `bson:"club_ids" json:"club_ids"`
-	CreatedAt  time.Time       `bson:"created_at" json:"created_at"`
+// Player positions
+const (
+	PositionGoalkeeper = "goalkeeper"
+	PositionDefender   = "defender"
+	PositionMidfielder = "midfielder"
+	PositionForward    = "forward"
+)
+
+var PlayerPositions = []string{PositionGoalkeeper, PositionDefender, PositionMidfielder, PositionForward}
+
+// Shirt numbers run from MinShirtNumber to MaxShirtNumber; a player without
+// one has ShirtNumber 0.
+const (
+	MinShirtNumber = 1
+	MaxShirtNumber = 99
+)
+
+type Player struct {
+	ID          bson.ObjectID    `bson:"_id,omitempty" json:"id"`
+	Name        string           `bson:"name" json:"name"`
+	Names       *MultiLangString `bson:"names,omitempty" json:"names,omitempty"`
+	Position    string           `bson:"position" json:"position"`
+	ShirtNumber int              `bson:"shirt_number,omitempty" json:"shirt_number,omitempty"`
+	Nationality string           `bson:"nationality,omitempty" json:"nationality,omitempty"`
+	PhotoURL    string           `bson:"photo_url,omitempty" json:"photo_url,omitempty"`
+	CreatedAt   time.Time        `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time        `bson:"updated_at" json:"updated_at"`
+	Version     int64            `bson:"version" json:"version"`
+	DeletedAt   *time.Time       `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy   bson.ObjectID    `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
+}
+
+// Squad lists the players registered for a club in one season
+type Squad struct {
+	ID        bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	ClubID    bson.ObjectID   `bson:"club_id" json:"club_id"`
+	Season    string          `bson:"season" json:"season"`
+	PlayerIDs []bson.ObjectID `bson:"player_ids" json:"player_ids"`
+	CreatedAt time.Time       `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `bson:"updated_at" json:"updated_at"`
+	Version   int64           `bson:"version" json:"version"`
+	DeletedAt *time.Time      `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy bson.ObjectID   `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
+}
+
+// Match statuses. A match starts scheduled; postponed matches may get a new
+// kickoff time and be scheduled again.
+const (
+	MatchScheduled = "scheduled"
+	MatchLive      = "live"
+	MatchFinished  = "finished"
+	MatchPostponed = "postponed"
+	MatchCancelled = "cancelled"
+)
+
+var MatchStatuses = []string{MatchScheduled, MatchLive, MatchFinished, MatchPostponed, MatchCancelled}
+
+type Match struct {
+	ID         bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	HomeClubID bson.ObjectID `bson:"home_club_id" json:"home_club_id"`
+	AwayClubID bson.ObjectID `bson:"away_club_id" json:"away_club_id"`
+	LeagueID   bson.ObjectID `bson:"league_id" json:"league_id"`
+	Season     string        `bson:"season" json:"season"` // e.g. "2024/25"
+	KickoffAt  time.Time     `bson:"kickoff_at" json:"kickoff_at"`
+	Venue      string        `bson:"venue,omitempty" json:"venue,omitempty"`
+	Status     string        `bson:"status" json:"status"`
+	// Score and Period are computed from the match events
+	Score     *Score        `bson:"score,omitempty" json:"score,omitempty"`
+	Period    string        `bson:"period,omitempty" json:"period,omitempty"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
+	Version   int64         `bson:"version" json:"version"`
+	DeletedAt *time.Time    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy bson.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
+}
+
+type Score struct {
+	Home int `bson:"home" json:"home"`
+	Away int `bson:"away" json:"away"`
+}
+
+// Match event types
+const (
+	EventGoal         = "goal"
+	EventOwnGoal      = "own_goal"
+	EventPenaltyGoal  = "penalty_goal"
+	EventYellowCard   = "yellow_card"
+	EventRedCard      = "red_card"
+	EventSubstitution = "substitution"
+	EventPeriod       = "period"
+	EventFinalScore   = "final_score"
+)
+
+var MatchEventTypes = []string{
+	EventGoal, EventOwnGoal, EventPenaltyGoal, EventYellowCard, EventRedCard,
+	EventSubstitution, EventPeriod, EventFinalScore,
+}
+
+// Match periods, set by period events
+const (
+	PeriodFirstHalf  = "first_half"
+	PeriodHalfTime   = "half_time"
+	PeriodSecondHalf = "second_half"
+	PeriodExtraTime  = "extra_time"
+	PeriodPenalties  = "penalties"
+	PeriodFullTime   = "full_time"
+)
+
+var MatchPeriods = []string{
+	PeriodFirstHalf, PeriodHalfTime, PeriodSecondHalf, PeriodExtraTime, PeriodPenalties, PeriodFullTime,
 }
 
+// MatchEvent is one entry of a match timeline. ClubID is the team of the
+// player involved, so an own goal counts for the other club.
+type MatchEvent struct {
+	ID        bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	MatchID   bson.ObjectID `bson:"match_id" json:"match_id"`
+	Type      string        `bson:"type" json:"type"`
+	Minute    int           `bson:"minute" json:"minute"`
+	Stoppage  int           `bson:"stoppage,omitempty" json:"stoppage,omitempty"` // added time, e.g. 3 for 45+3
+	ClubID    bson.ObjectID `bson:"club_id,omitempty" json:"club_id,omitzero"`
+	Player    string        `bson:"player,omitempty" json:"player,omitempty"`
+	PlayerOut string        `bson:"player_out,omitempty" json:"player_out,omitempty"` // substitutions only
+	Period    string        `bson:"period,omitempty" json:"period,omitempty"`         // period events only
+	Score     *Score        `bson:"score,omitempty" json:"score,omitempty"`           // final_score events only
+	Note      string        `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt time.Time     `bson:"created_at" json:"created_at"`
+	CreatedBy bson.ObjectID `bson:"created_by,omitempty" json:"created_by,omitzero"`
+	UpdatedAt time.Time     `bson:"updated_at" json:"updated_at"`
+	DeletedAt *time.Time    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy bson.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
+}
+
+// StandingRow is one club's line in a league table. Form lists the latest
+// results first, as "W", "D" or "L".
+type StandingRow struct {
+	Position       int           `bson:"position" json:"position"`
+	ClubID         bson.ObjectID `bson:"club_id" json:"club_id"`
+	Played         int           `bson:"played" json:"played"`
+	Won            int           `bson:"won" json:"won"`
+	Drawn          int           `bson:"drawn" json:"drawn"`
+	Lost           int           `bson:"lost" json:"lost"`
+	GoalsFor       int           `bson:"goals_for" json:"goals_for"`
+	GoalsAgainst   int           `bson:"goals_against" json:"goals_against"`
+	GoalDifference int           `bson:"goal_difference" json:"goal_difference"`
+	Points         int           `bson:"points" json:"points"`
+	Form           []string      `bson:"form" json:"form"`
+}
+
+// Standings is the computed table of a league season, keyed "leagueID:season"
+type Standings struct {
+	ID          string        `bson:"_id" json:"-"`
+	LeagueID    bson.ObjectID `bson:"league_id" json:"league_id"`
+	Season      string        `bson:"season" json:"season"`
+	Tiebreakers []string      `bson:"tiebreakers" json:"tiebreakers"`
+	Rows        []StandingRow `bson:"rows" json:"rows"`
+	ComputedAt  time.Time     `bson:"computed_at" json:"computed_at"`
+}
+
+// Watch link access
+const (
+	AccessFree = "free"
+	AccessPaid = "paid"
+)
+
+var WatchAccess = []string{AccessFree, AccessPaid}
+
+// WatchLink is a platform to watch matches on. A link without leagues, clubs
+// or matches covers every match.
 type WatchLink struct {
-	ID      bson.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name    string        `bson:"name" json:"name"`
-	URL     string        `bson:"url" json:"url"`
-	Type    string        `bson:"type" json:"type"`
-	LogoURL string        `bson:"logo_url" json:"logo_url"`
+	ID        bson.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name      string          `bson:"name" json:"name"`
+	URL       string          `bson:"url" json:"url"`
+	Type      string          `bson:"type" json:"type"`
+	LogoURL   string          `bson:"logo_url" json:"logo_url"`
+	LeagueIDs []bson.ObjectID `bson:"league_ids,omitempty" json:"league_ids,omitempty"`
+	ClubIDs   []bson.ObjectID `bson:"club_ids,omitempty" json:"club_ids,omitempty"`
+	MatchIDs  []bson.ObjectID `bson:"match_ids,omitempty" json:"match_ids,omitempty"`
+	// Countries are ISO 3166-1 alpha-2 codes where the link plays; none means everywhere
+	Countries           []string      `bson:"countries,omitempty" json:"countries,omitempty"`
+	Access              string        `bson:"access,omitempty" json:"access,omitempty"`                             // free or paid
+	CommentaryLanguages []string      `bson:"commentary_languages,omitempty" json:"commentary_languages,omitempty"` // ISO 639-1 codes
+	AvailableFrom       *time.Time    `bson:"available_from,omitempty" json:"available_from,omitempty"`             // e.g. when a one-off stream opens
+	AvailableUntil      *time.Time    `bson:"available_until,omitempty" json:"available_until,omitempty"`
+	CreatedAt           time.Time     `bson:"created_at" json:"created_at"`
+	UpdatedAt           time.Time     `bson:"updated_at" json:"updated_at"`
+	Version             int64         `bson:"version" json:"version"`
+	DeletedAt           *time.Time    `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy           bson.ObjectID `bson:"deleted_by,omitempty" json:"deleted_by,omitzero"`
 }
 
 type Activity struct {
@@ -93,3 +350,71 @@ type Activity struct {
 	// Virtual field for display
 	UserName string `bson:"user_name" json:"user_name,omitempty"`
 }
+
+// Revision is a point-in-time snapshot of an admin-managed document,
+// recorded on every create, update and restore.
+type Revision struct {
+	ID            bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	Entity        string        `bson:"entity" json:"entity"` // content or highlight
+	EntityID      bson.ObjectID `bson:"entity_id" json:"entity_id"`
+	Number        int           `bson:"number" json:"number"`
+	Action        string        `bson:"action" json:"action"` // create, update or restore
+	ChangedFields []string      `bson:"changed_fields" json:"changed_fields"`
+	Snapshot      bson.M        `bson:"snapshot" json:"snapshot"`
+	EditorID      bson.ObjectID `bson:"editor_id,omitempty" json:"editor_id,omitzero"`
+	CreatedAt     time.Time     `bson:"created_at" json:"created_at"`
+}
+
+// RankingWeights tunes how much each signal counts in the ranked feed. It is
+// stored as a single settings document and edited by admins.
+type RankingWeights struct {
+	Recency              float64   `bson:"recency" json:"recency"`
+	RecencyHalfLifeHours float64   `bson:"recency_half_life_hours" json:"recency_half_life_hours"`
+	Follow               float64   `bson:"follow" json:"follow"`
+	Category             float64   `bson:"category" json:"category"`
+	Engagement           float64   `bson:"engagement" json:"engagement"`
+	Language             float64   `bson:"language" json:"language"`
+	UpdatedAt            time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Import job statuses. A job is pending until the worker picks it up.
+const (
+	ImportPending = "pending"
+	ImportRunning = "running"
+	ImportDone    = "done"
+	ImportFailed  = "failed"
+)
+
+// ImportError is a problem with one row of an import file. Row 0 is the file
+// as a whole.
+type ImportError struct {
+	Row     int    `bson:"row" json:"row"`
+	Field   string `bson:"field,omitempty" json:"field,omitempty"`
+	Message string `bson:"message" json:"message"`
+}
+
+// ImportSummary counts what an import did, or would do, per row
+type ImportSummary struct {
+	Created   int `bson:"created" json:"created"`
+	Updated   int `bson:"updated" json:"updated"`
+	Unchanged int `bson:"unchanged" json:"unchanged"`
+	Failed    int `bson:"failed" json:"failed"`
+}
+
+// ImportJob is a bulk import applied in the background. The parsed rows are
+// kept so the worker can apply them and admins can see what was sent.
+type ImportJob struct {
+	ID         bson.ObjectID       `bson:"_id,omitempty" json:"id"`
+	Kind       string              `bson:"kind" json:"kind"` // leagues, clubs, fixtures, results or players
+	FileName   string              `bson:"file_name,omitempty" json:"file_name,omitempty"`
+	Status     string              `bson:"status" json:"status"`
+	Rows       []map[string]string `bson:"rows" json:"-"`
+	RowCount   int                 `bson:"row_count" json:"row_count"`
+	Summary    ImportSummary       `bson:"summary" json:"summary"`
+	Errors     []ImportError       `bson:"errors" json:"errors"`
+	Error      string              `bson:"error,omitempty" json:"error,omitempty"` // why the whole job failed
+	CreatedBy  bson.ObjectID       `bson:"created_by,omitempty" json:"created_by,omitzero"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	StartedAt  *time.Time          `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt *time.Time          `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
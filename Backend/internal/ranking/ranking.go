@@ -0,0 +1,157 @@
+// Package ranking orders feed items for a single user. Scorers are pure
+// functions of the item, the user's profile and the current time, so the same
+// inputs always produce the same order.
+package ranking
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+// Item is the part of a feed item that scorers look at
+type Item struct {
+	ID        bson.ObjectID
+	CreatedAt time.Time
+	Category  string
+	ClubIDs   []bson.ObjectID
+	LeagueIDs []bson.ObjectID
+	Views     int64
+	// Languages the item is readable in; empty means language-neutral
+	Languages []string
+	Pinned    bool
+}
+
+// Profile describes what a user follows and prefers
+type Profile struct {
+	PrimaryClubID bson.ObjectID
+	ClubIDs       map[bson.ObjectID]bool
+	LeagueIDs     map[bson.ObjectID]bool
+	// LeagueClubIDs are the clubs playing in followed leagues
+	LeagueClubIDs map[bson.ObjectID]bool
+	Categories    map[string]bool
+	Language      string
+}
+
+// Scorer rates how relevant an item is to a user; higher ranks first
+type Scorer interface {
+	Score(item Item, profile Profile, now time.Time) float64
+}
+
+// DefaultWeights are used until an admin saves their own
+var DefaultWeights = models.RankingWeights{
+	Recency:              1.0,
+	RecencyHalfLifeHours: 24,
+	Follow:               1.0,
+	Category:             0.3,
+	Engagement:           0.2,
+	Language:             0.3,
+}
+
+// WeightedScorer sums per-signal scores in [0, 1] multiplied by their weights
+type WeightedScorer struct {
+	Weights models.RankingWeights
+}
+
+func (s WeightedScorer) Score(item Item, profile Profile, now time.Time) float64 {
+	w := s.Weights
+	return w.Recency*recencyScore(item, now, w.RecencyHalfLifeHours) +
+		w.Follow*followScore(item, profile) +
+		w.Category*categoryScore(item, profile) +
+		w.Engagement*engagementScore(item) +
+		w.Language*languageScore(item, profile)
+}
+
+// recencyScore halves every halfLifeHours; items from the future count as new
+func recencyScore(item Item, now time.Time, halfLifeHours float64) float64 {
+	if halfLifeHours <= 0 {
+		return 0
+	}
+	age := now.Sub(item.CreatedAt).Hours()
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, age/halfLifeHours)
+}
+
+// followScore prefers the primary club, then followed clubs, then followed
+// leagues
+func followScore(item Item, profile Profile) float64 {
+	best := 0.0
+	for _, id := range item.ClubIDs {
+		switch {
+		case id == profile.PrimaryClubID:
+			return 1
+		case profile.ClubIDs[id]:
+			best = math.Max(best, 0.7)
+		case profile.LeagueClubIDs[id]:
+			best = math.Max(best, 0.4)
+		}
+	}
+	for _, id := range item.LeagueIDs {
+		if profile.LeagueIDs[id] {
+			best = math.Max(best, 0.4)
+		}
+	}
+	return best
+}
+
+func categoryScore(item Item, profile Profile) float64 {
+	if item.Category != "" && profile.Categories[item.Category] {
+		return 1
+	}
+	return 0
+}
+
+// engagementScore grows logarithmically so a few viral items cannot bury
+// everything else. Only views count: news and highlights have no reactions
+// to weigh yet.
+func engagementScore(item Item) float64 {
+	if item.Views <= 0 {
+		return 0
+	}
+	l := math.Log1p(float64(item.Views))
+	return l / (1 + l)
+}
+
+func languageScore(item Item, profile Profile) float64 {
+	if len(item.Languages) == 0 || profile.Language == "" {
+		return 1
+	}
+	for _, lang := range item.Languages {
+		if lang == profile.Language {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Rank returns the indexes of items in ranked order: pinned items first, then
+// by descending score, breaking ties by newest and then by ID.
+func Rank(items []Item, scorer Scorer, profile Profile, now time.Time) []int {
+	scores := make([]float64, len(items))
+	order := make([]int, len(items))
+	for i, item := range items {
+		scores[i] = scorer.Score(item, profile, now)
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		x, y := items[order[a]], items[order[b]]
+		if x.Pinned != y.Pinned {
+			return x.Pinned
+		}
+		if scores[order[a]] != scores[order[b]] {
+			return scores[order[a]] > scores[order[b]]
+		}
+		if !x.CreatedAt.Equal(y.CreatedAt) {
+			return x.CreatedAt.After(y.CreatedAt)
+		}
+		return x.ID.Hex() > y.ID.Hex()
+	})
+	return order
+}
@@ -0,0 +1,199 @@
+package ranking
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRecencyScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		age      time.Duration
+		halfLife float64
+		want     float64
+	}{
+		{"new", 0, 24, 1},
+		{"one half-life", 24 * time.Hour, 24, 0.5},
+		{"two half-lives", 48 * time.Hour, 24, 0.25},
+		{"from the future", -time.Hour, 24, 1},
+		{"no half-life", time.Hour, 0, 0},
+	}
+	for _, tt := range tests {
+		item := Item{CreatedAt: now.Add(-tt.age)}
+		if got := recencyScore(item, now, tt.halfLife); !near(got, tt.want) {
+			t.Errorf("%s: recencyScore = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFollowScore(t *testing.T) {
+	primary, followed, leagueClub, other := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+	league := bson.NewObjectID()
+	profile := Profile{
+		PrimaryClubID: primary,
+		ClubIDs:       map[bson.ObjectID]bool{primary: true, followed: true},
+		LeagueIDs:     map[bson.ObjectID]bool{league: true},
+		LeagueClubIDs: map[bson.ObjectID]bool{leagueClub: true},
+	}
+	tests := []struct {
+		name string
+		item Item
+		want float64
+	}{
+		{"primary club", Item{ClubIDs: []bson.ObjectID{other, primary}}, 1},
+		{"followed club", Item{ClubIDs: []bson.ObjectID{followed, leagueClub}}, 0.7},
+		{"club of a followed league", Item{ClubIDs: []bson.ObjectID{leagueClub}}, 0.4},
+		{"followed league", Item{LeagueIDs: []bson.ObjectID{league}}, 0.4},
+		{"unrelated", Item{ClubIDs: []bson.ObjectID{other}}, 0},
+		{"untagged", Item{}, 0},
+	}
+	for _, tt := range tests {
+		if got := followScore(tt.item, profile); !near(got, tt.want) {
+			t.Errorf("%s: followScore = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCategoryScore(t *testing.T) {
+	profile := Profile{Categories: map[string]bool{"transfers": true}}
+	for category, want := range map[string]float64{"transfers": 1, "match": 0, "": 0} {
+		if got := categoryScore(Item{Category: category}, profile); got != want {
+			t.Errorf("categoryScore(%q) = %v; want %v", category, got, want)
+		}
+	}
+}
+
+func TestEngagementScore(t *testing.T) {
+	if got := engagementScore(Item{Views: 0}); got != 0 {
+		t.Errorf("no views: %v; want 0", got)
+	}
+	if got := engagementScore(Item{Views: -5}); got != 0 {
+		t.Errorf("negative views: %v; want 0", got)
+	}
+	l := math.Log1p(100)
+	if got := engagementScore(Item{Views: 100}); !near(got, l/(1+l)) {
+		t.Errorf("100 views: %v; want %v", got, l/(1+l))
+	}
+
+	// More views always help, but stay below 1
+	prev := 0.0
+	for _, views := range []int64{1, 10, 1000, 1_000_000} {
+		got := engagementScore(Item{Views: views})
+		if got <= prev || got >= 1 {
+			t.Errorf("%d views: %v after %v; want growing and below 1", views, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestLanguageScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		languages []string
+		profile   string
+		want      float64
+	}{
+		{"language-neutral", nil, "am", 1},
+		{"no preference", []string{"en"}, "", 1},
+		{"available", []string{"en", "am"}, "am", 1},
+		{"not available", []string{"en"}, "om", 0},
+	}
+	for _, tt := range tests {
+		if got := languageScore(Item{Languages: tt.languages}, Profile{Language: tt.profile}); got != tt.want {
+			t.Errorf("%s: languageScore = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWeightedScorer(t *testing.T) {
+	club := bson.NewObjectID()
+	profile := Profile{
+		ClubIDs:    map[bson.ObjectID]bool{club: true},
+		Categories: map[string]bool{"match": true},
+		Language:   "am",
+	}
+	item := Item{
+		CreatedAt: now.Add(-24 * time.Hour),
+		Category:  "match",
+		ClubIDs:   []bson.ObjectID{club},
+		Views:     100,
+		Languages: []string{"en"},
+	}
+	l := math.Log1p(100)
+	engagement := l / (1 + l)
+
+	tests := []struct {
+		name    string
+		weights models.RankingWeights
+		want    float64
+	}{
+		{"defaults", DefaultWeights, 1.0*0.5 + 1.0*0.7 + 0.3*1 + 0.2*engagement + 0.3*0},
+		{"recency only", models.RankingWeights{Recency: 2, RecencyHalfLifeHours: 12}, 2 * 0.25},
+		{"follow only", models.RankingWeights{Follow: 1}, 0.7},
+		{"engagement only", models.RankingWeights{Engagement: 1}, engagement},
+		{"nothing weighed", models.RankingWeights{}, 0},
+	}
+	for _, tt := range tests {
+		got := WeightedScorer{Weights: tt.weights}.Score(item, profile, now)
+		if !near(got, tt.want) {
+			t.Errorf("%s: Score = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// fixedScorer scores items by ID
+type fixedScorer map[bson.ObjectID]float64
+
+func (s fixedScorer) Score(item Item, profile Profile, now time.Time) float64 {
+	return s[item.ID]
+}
+
+func TestRank(t *testing.T) {
+	ids := make([]bson.ObjectID, 6)
+	for i := range ids {
+		ids[i] = bson.NewObjectID() // ascending
+	}
+	items := []Item{
+		{ID: ids[0], CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: ids[1], CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: ids[2], CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: ids[3], CreatedAt: now.Add(-time.Hour)},
+		{ID: ids[4], CreatedAt: now.Add(-9 * time.Hour), Pinned: true},
+		{ID: ids[5], CreatedAt: now, Pinned: true},
+	}
+	scorer := fixedScorer{
+		ids[0]: 2,   // highest score
+		ids[1]: 1,   // ties with 2 and 3; same age as 2, lower ID
+		ids[2]: 1,   // ties with 1 and 3; same age as 1, higher ID
+		ids[3]: 1,   // ties with 1 and 2, newest
+		ids[4]: 0.5, // pinned, beats the other pin on score
+		ids[5]: 0,   // pinned
+	}
+
+	want := []int{4, 5, 0, 3, 2, 1}
+	if got := Rank(items, scorer, Profile{}, now); !slices.Equal(got, want) {
+		t.Errorf("Rank = %v; want %v", got, want)
+	}
+
+	// The order does not depend on the input order
+	reversed := slices.Clone(items)
+	slices.Reverse(reversed)
+	got := Rank(reversed, scorer, Profile{}, now)
+	for i, index := range got {
+		if reversed[index].ID != items[want[i]].ID {
+			t.Fatalf("Rank of reversed items = %v; want the same order of IDs", got)
+		}
+	}
+}
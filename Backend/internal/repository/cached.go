@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/cache"
+	"fanzone/internal/models"
+)
+
+// CachedRepository serves reads of reference data (clubs, leagues and watch
+// links) from a cache and invalidates it on every write to them. Everything
+// else goes straight to the Repository.
+type CachedRepository struct {
+	*Repository
+	Cache *cache.Cache
+}
+
+func NewCachedRepository(repo *Repository, c *cache.Cache) *CachedRepository {
+	return &CachedRepository{Repository: repo, Cache: c}
+}
+
+// cachedCollections are the collections whose reads are cached
+var cachedCollections = map[string]bool{
+	"clubs":       true,
+	"leagues":     true,
+	"watch_links": true,
+}
+
+// cachedValue wraps a value so any type can be stored as a BSON document
+type cachedValue[T any] struct {
+	V T `bson:"v"`
+}
+
+func fetchCached[T any](ctx context.Context, c *cache.Cache, key string, load func() (T, error)) (T, error) {
+	raw, err := c.Fetch(ctx, key, func() ([]byte, error) {
+		v, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return bson.Marshal(cachedValue[T]{V: v})
+	})
+
+	var out cachedValue[T]
+	if err != nil {
+		return out.V, err
+	}
+	err = bson.Unmarshal(raw, &out)
+	return out.V, err
+}
+
+func listKey(collection string) string {
+	return collection + ":all"
+}
+
+func itemKey(collection string, id bson.ObjectID) string {
+	return collection + ":" + id.Hex()
+}
+
+// invalidate drops the cached list and item after a write. A failure is only
+// logged: the write already succeeded and the TTL bounds the staleness.
+func (r *CachedRepository) invalidate(ctx context.Context, collection string, id bson.ObjectID) {
+	if !cachedCollections[collection] {
+		return
+	}
+	if err := r.Cache.Invalidate(ctx, listKey(collection), itemKey(collection, id)); err != nil {
+		log.Printf("[Cache] Failed to invalidate %s %s: %v", collection, id.Hex(), err)
+	}
+}
+
+// --- Club ---
+
+func (r *CachedRepository) GetClubs(ctx context.Context) ([]models.Club, error) {
+	return fetchCached(ctx, r.Cache, listKey("clubs"), func() ([]models.Club, error) {
+		return r.Repository.GetClubs(ctx)
+	})
+}
+
+func (r *CachedRepository) FindClubByID(ctx context.Context, id bson.ObjectID) (*models.Club, error) {
+	return fetchCached(ctx, r.Cache, itemKey("clubs", id), func() (*models.Club, error) {
+		return r.Repository.FindClubByID(ctx, id)
+	})
+}
+
+func (r *CachedRepository) CreateClub(ctx context.Context, club models.Club) error {
+	err := r.Repository.CreateClub(ctx, club)
+	r.invalidate(ctx, "clubs", club.ID)
+	return err
+}
+
+func (r *CachedRepository) UpdateClub(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	newVersion, err := r.Repository.UpdateClub(ctx, id, update, version)
+	r.invalidate(ctx, "clubs", id)
+	return newVersion, err
+}
+
+func (r *CachedRepository) DeleteClub(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	err := r.Repository.DeleteClub(ctx, id, deletedBy)
+	r.invalidate(ctx, "clubs", id)
+	return err
+}
+
+// --- League ---
+
+func (r *CachedRepository) GetLeagues(ctx context.Context) ([]models.League, error) {
+	return fetchCached(ctx, r.Cache, listKey("leagues"), func() ([]models.League, error) {
+		return r.Repository.GetLeagues(ctx)
+	})
+}
+
+func (r *CachedRepository) FindLeagueByID(ctx context.Context, id bson.ObjectID) (*models.League, error) {
+	return fetchCached(ctx, r.Cache, itemKey("leagues", id), func() (*models.League, error) {
+		return r.Repository.FindLeagueByID(ctx, id)
+	})
+}
+
+func (r *CachedRepository) CreateLeague(ctx context.Context, league models.League) error {
+	err := r.Repository.CreateLeague(ctx, league)
+	r.invalidate(ctx, "leagues", league.ID)
+	return err
+}
+
+func (r *CachedRepository) UpdateLeague(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	newVersion, err := r.Repository.UpdateLeague(ctx, id, update, version)
+	r.invalidate(ctx, "leagues", id)
+	return newVersion, err
+}
+
+func (r *CachedRepository) DeleteLeague(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	err := r.Repository.DeleteLeague(ctx, id, deletedBy)
+	r.invalidate(ctx, "leagues", id)
+	return err
+}
+
+// ApplyCurrentSeason also drops the cached clubs that moved league
+func (r *CachedRepository) ApplyCurrentSeason(ctx context.Context, leagueID bson.ObjectID) ([]bson.ObjectID, error) {
+	moved, err := r.Repository.ApplyCurrentSeason(ctx, leagueID)
+	for _, id := range moved {
+		r.invalidate(ctx, "clubs", id)
+	}
+	return moved, err
+}
+
+// --- Watch Links ---
+
+func (r *CachedRepository) GetWatchLinks(ctx context.Context) ([]models.WatchLink, error) {
+	return fetchCached(ctx, r.Cache, listKey("watch_links"), func() ([]models.WatchLink, error) {
+		return r.Repository.GetWatchLinks(ctx)
+	})
+}
+
+func (r *CachedRepository) FindWatchLinkByID(ctx context.Context, id bson.ObjectID) (*models.WatchLink, error) {
+	return fetchCached(ctx, r.Cache, itemKey("watch_links", id), func() (*models.WatchLink, error) {
+		return r.Repository.FindWatchLinkByID(ctx, id)
+	})
+}
+
+func (r *CachedRepository) CreateWatchLink(ctx context.Context, link models.WatchLink) error {
+	err := r.Repository.CreateWatchLink(ctx, link)
+	r.invalidate(ctx, "watch_links", link.ID)
+	return err
+}
+
+func (r *CachedRepository) UpdateWatchLink(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	newVersion, err := r.Repository.UpdateWatchLink(ctx, id, update, version)
+	r.invalidate(ctx, "watch_links", id)
+	return newVersion, err
+}
+
+func (r *CachedRepository) DeleteWatchLink(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	err := r.Repository.DeleteWatchLink(ctx, id, deletedBy)
+	r.invalidate(ctx, "watch_links", id)
+	return err
+}
+
+// --- Trash ---
+
+func (r *CachedRepository) RestoreFromTrash(ctx context.Context, collection string, id bson.ObjectID) error {
+	err := r.Repository.RestoreFromTrash(ctx, collection, id)
+	r.invalidate(ctx, collection, id)
+	return err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"fanzone/internal/models"
+)
+
+func (r *Repository) CreateImportJob(ctx context.Context, job models.ImportJob) error {
+	_, err := r.DB.Collection("import_jobs").InsertOne(ctx, job)
+	return err
+}
+
+func (r *Repository) FindImportJobByID(ctx context.Context, id bson.ObjectID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.DB.Collection("import_jobs").FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	return &job, err
+}
+
+// GetImportJobs returns the latest jobs without their rows
+func (r *Repository) GetImportJobs(ctx context.Context, limit int64) ([]models.ImportJob, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"rows": 0, "errors": 0}).
+		SetLimit(limit)
+	cursor, err := r.DB.Collection("import_jobs").Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []models.ImportJob{}
+	err = cursor.All(ctx, &jobs)
+	return jobs, err
+}
+
+// ClaimImportJob marks a pending job as running and returns it, so a job
+// queued twice only runs once. It returns mongo.ErrNoDocuments when the job
+// is not pending.
+func (r *Repository) ClaimImportJob(ctx context.Context, id bson.ObjectID) (*models.ImportJob, error) {
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var job models.ImportJob
+	err := r.DB.Collection("import_jobs").FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.ImportPending},
+		bson.M{"$set": bson.M{"status": models.ImportRunning, "started_at": now}},
+		opts,
+	).Decode(&job)
+	return &job, err
+}
+
+// RetryImportJob puts a failed job back in the queue and returns it. It
+// returns mongo.ErrNoDocuments when the job has not failed.
+func (r *Repository) RetryImportJob(ctx context.Context, id bson.ObjectID) (*models.ImportJob, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"rows": 0})
+	var job models.ImportJob
+	err := r.DB.Collection("import_jobs").FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": models.ImportFailed},
+		bson.M{
+			"$set":   bson.M{"status": models.ImportPending, "summary": models.ImportSummary{}, "errors": []models.ImportError{}},
+			"$unset": bson.M{"error": "", "started_at": "", "finished_at": ""},
+		},
+		opts,
+	).Decode(&job)
+	return &job, err
+}
+
+// PendingImportJobIDs returns the jobs waiting to run, oldest first
+func (r *Repository) PendingImportJobIDs(ctx context.Context) ([]bson.ObjectID, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetProjection(bson.M{"_id": 1})
+	cursor, err := r.DB.Collection("import_jobs").Find(ctx, bson.M{"status": models.ImportPending}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var jobs []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	ids := make([]bson.ObjectID, len(jobs))
+	for i, job := range jobs {
+		ids[i] = job.ID
+	}
+	return ids, nil
+}
+
+// FailStaleImportJobs marks jobs that started running before a time as
+// failed, e.g. because the server stopped in the middle of them
+func (r *Repository) FailStaleImportJobs(ctx context.Context, startedBefore time.Time) (int64, error) {
+	result, err := r.DB.Collection("import_jobs").UpdateMany(ctx,
+		bson.M{"status": models.ImportRunning, "started_at": bson.M{"$lt": startedBefore}},
+		bson.M{"$set": bson.M{
+			"status":      models.ImportFailed,
+			"error":       "the import was interrupted; retry it to finish",
+			"finished_at": time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FinishImportJob records the outcome of a job
+func (r *Repository) FinishImportJob(ctx context.Context, id bson.ObjectID, summary models.ImportSummary, errs []models.ImportError, failure error) error {
+	set := bson.M{
+		"status":      models.ImportDone,
+		"summary":     summary,
+		"errors":      errs,
+		"finished_at": time.Now(),
+	}
+	if failure != nil {
+		set["status"] = models.ImportFailed
+		set["error"] = failure.Error()
+	}
+	_, err := r.DB.Collection("import_jobs").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DeletePolicy decides what happens to documents referencing one that is
+// being deleted.
+type DeletePolicy string
+
+const (
+	// Restrict blocks the delete while any active document references it
+	Restrict DeletePolicy = "restrict"
+	// Cascade deletes the referencing documents as well
+	Cascade DeletePolicy = "cascade"
+	// Nullify removes the reference from the referencing documents
+	Nullify DeletePolicy = "nullify"
+)
+
+// Relation describes a field in Collection that references another collection.
+type Relation struct {
+	Collection string
+	Field      string
+	Many       bool // Field holds an array of references
+	Policy     DeletePolicy
+}
+
+// Relations lists, per referenced collection, who points at it and how
+// deletes propagate.
+var Relations = map[string][]Relation{
+	"leagues": {
+		{Collection: "clubs", Field: "league_id", Policy: Restrict},
+		{Collection: "matches", Field: "league_id", Policy: Restrict},
+		{Collection: "seasons", Field: "league_id", Policy: Cascade},
+		{Collection: "users", Field: "followed_league_ids", Many: true, Policy: Nullify},
+		{Collection: "content", Field: "league_ids", Many: true, Policy: Nullify},
+		{Collection: "highlights", Field: "league_ids", Many: true, Policy: Nullify},
+		// Removing the last target would widen a link to every match
+		{Collection: "watch_links", Field: "league_ids", Many: true, Policy: Restrict},
+	},
+	"clubs": {
+		{Collection: "matches", Field: "home_club_id", Policy: Restrict},
+		{Collection: "matches", Field: "away_club_id", Policy: Restrict},
+		{Collection: "seasons", Field: "club_ids", Many: true, Policy: Nullify},
+		{Collection: "squads", Field: "club_id", Policy: Cascade},
+		{Collection: "users", Field: "fav_club_id", Policy: Nullify},
+		{Collection: "users", Field: "followed_club_ids", Many: true, Policy: Nullify},
+		{Collection: "content", Field: "club_id", Policy: Nullify},
+		{Collection: "highlights", Field: "club_ids", Many: true, Policy: Nullify},
+		{Collection: "watch_links", Field: "club_ids", Many: true, Policy: Restrict},
+	},
+	"matches": {
+		{Collection: "match_events", Field: "match_id", Policy: Cascade},
+		{Collection: "content", Field: "match_id", Policy: Nullify},
+		{Collection: "highlights", Field: "match_id", Policy: Nullify},
+		{Collection: "watch_links", Field: "match_ids", Many: true, Policy: Restrict},
+	},
+	"players": {
+		{Collection: "squads", Field: "player_ids", Many: true, Policy: Nullify},
+		{Collection: "content", Field: "player_ids", Many: true, Policy: Nullify},
+		{Collection: "highlights", Field: "player_ids", Many: true, Policy: Nullify},
+	},
+}
+
+// maxListedDependents caps how many dependent IDs a DependentsError carries
+const maxListedDependents = 20
+
+// Dependent summarises documents that block a delete.
+type Dependent struct {
+	Collection string          `json:"collection"`
+	Field      string          `json:"field"`
+	Count      int64           `json:"count"`
+	IDs        []bson.ObjectID `json:"ids"`
+}
+
+// DependentsError is returned when a Restrict relation blocks a delete.
+type DependentsError struct {
+	Collection string
+	ID         bson.ObjectID
+	Dependents []Dependent
+}
+
+func (e *DependentsError) Error() string {
+	return fmt.Sprintf("%s %s is still referenced by %d collection(s)", e.Collection, e.ID.Hex(), len(e.Dependents))
+}
+
+// MissingParent lists deleted documents that a document being restored still
+// refers to.
+type MissingParent struct {
+	Collection string          `json:"collection"`
+	Field      string          `json:"field"`
+	IDs        []bson.ObjectID `json:"ids"`
+}
+
+// MissingParentsError is returned when a restore would bring back a document
+// referring to documents that are deleted, such as a club of a trashed league.
+type MissingParentsError struct {
+	Collection string
+	ID         bson.ObjectID
+	Missing    []MissingParent
+}
+
+func (e *MissingParentsError) Error() string {
+	return fmt.Sprintf("%s %s refers to %d deleted collection(s)", e.Collection, e.ID.Hex(), len(e.Missing))
+}
+
+// parentRef is what one field of a document refers to
+type parentRef struct {
+	parent string
+	field  string
+	ids    []bson.ObjectID
+}
+
+// parentRefs lists the references a document of collection holds, following
+// Relations backwards, in a stable order
+func parentRefs(collection string, doc bson.M) []parentRef {
+	var refs []parentRef
+	for _, parent := range slices.Sorted(maps.Keys(Relations)) {
+		for _, rel := range Relations[parent] {
+			if rel.Collection != collection {
+				continue
+			}
+			var ids []bson.ObjectID
+			switch value := doc[rel.Field].(type) {
+			case bson.ObjectID:
+				ids = []bson.ObjectID{value}
+			case bson.A:
+				for _, v := range value {
+					if id, ok := v.(bson.ObjectID); ok {
+						ids = append(ids, id)
+					}
+				}
+			}
+			if len(ids) > 0 {
+				refs = append(refs, parentRef{parent: parent, field: rel.Field, ids: ids})
+			}
+		}
+	}
+	return refs
+}
+
+// CheckReferences fails with a MissingParentsError when doc, a document of
+// collection, refers to documents that are deleted or gone
+func (r *Repository) CheckReferences(ctx context.Context, collection string, id bson.ObjectID, doc bson.M) error {
+	var missing []MissingParent
+	for _, ref := range parentRefs(collection, doc) {
+		opts := options.Find().SetProjection(bson.M{"_id": 1})
+		cursor, err := r.DB.Collection(ref.parent).Find(ctx, active(bson.M{"_id": bson.M{"$in": ref.ids}}), opts)
+		if err != nil {
+			return err
+		}
+		var found []struct {
+			ID bson.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &found); err != nil {
+			return err
+		}
+		present := make(map[bson.ObjectID]bool, len(found))
+		for _, f := range found {
+			present[f.ID] = true
+		}
+
+		var gone []bson.ObjectID
+		for _, parentID := range ref.ids {
+			if !present[parentID] {
+				gone = append(gone, parentID)
+			}
+		}
+		if len(gone) > 0 {
+			missing = append(missing, MissingParent{Collection: ref.parent, Field: ref.field, IDs: gone})
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingParentsError{Collection: collection, ID: id, Missing: missing}
+	}
+	return nil
+}
+
+// deleteEntity soft deletes a document after applying the delete policies of
+// every relation pointing at its collection, all in one transaction.
+func (r *Repository) deleteEntity(ctx context.Context, collection string, id, deletedBy bson.ObjectID) error {
+	relations := Relations[collection]
+	if len(relations) == 0 {
+		return r.softDelete(ctx, collection, id, deletedBy)
+	}
+
+	session, err := r.DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+		if err := r.checkRestrictions(ctx, collection, id, relations); err != nil {
+			return nil, err
+		}
+		for _, rel := range relations {
+			if err := r.applyDeletePolicy(ctx, rel, id, deletedBy); err != nil {
+				return nil, err
+			}
+		}
+		return nil, r.softDelete(ctx, collection, id, deletedBy)
+	})
+	return err
+}
+
+func (r *Repository) checkRestrictions(ctx context.Context, collection string, id bson.ObjectID, relations []Relation) error {
+	var dependents []Dependent
+	for _, rel := range relations {
+		if rel.Policy != Restrict {
+			continue
+		}
+
+		filter := r.referenceFilter(rel, id)
+		count, err := r.DB.Collection(rel.Collection).CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			continue
+		}
+
+		opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(maxListedDependents)
+		cursor, err := r.DB.Collection(rel.Collection).Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		var docs []struct {
+			ID bson.ObjectID `bson:"_id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return err
+		}
+
+		ids := make([]bson.ObjectID, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.ID
+		}
+		dependents = append(dependents, Dependent{
+			Collection: rel.Collection,
+			Field:      rel.Field,
+			Count:      count,
+			IDs:        ids,
+		})
+	}
+
+	if len(dependents) > 0 {
+		return &DependentsError{Collection: collection, ID: id, Dependents: dependents}
+	}
+	return nil
+}
+
+func (r *Repository) applyDeletePolicy(ctx context.Context, rel Relation, id, deletedBy bson.ObjectID) error {
+	coll := r.DB.Collection(rel.Collection)
+
+	var err error
+	switch rel.Policy {
+	case Nullify:
+		// Trashed documents are nullified too so a later restore cannot
+		// resurrect a dangling reference
+		update := bson.M{"$unset": bson.M{rel.Field: ""}}
+		if rel.Many {
+			update = bson.M{"$pull": bson.M{rel.Field: id}}
+		}
+		if isSoftDeleteCollection(rel.Collection) {
+			update["$set"] = bson.M{"updated_at": time.Now()}
+			update["$inc"] = bson.M{"version": 1}
+		}
+		_, err = coll.UpdateMany(ctx, bson.M{rel.Field: id}, update)
+	case Cascade:
+		filter := r.referenceFilter(rel, id)
+		if isSoftDeleteCollection(rel.Collection) {
+			// deleted_with marks what went to the trash with id, so restoring
+			// id brings back these and not ones deleted on their own
+			now := time.Now()
+			update := bson.M{
+				"$set": bson.M{"deleted_at": now, "deleted_by": deletedBy, "deleted_with": id, "updated_at": now},
+				"$inc": bson.M{"version": 1},
+			}
+			_, err = coll.UpdateMany(ctx, filter, update)
+		} else {
+			_, err = coll.DeleteMany(ctx, filter)
+		}
+	}
+	return err
+}
+
+// restoreCascaded restores what a cascade moved to the trash along with the
+// document id of collection
+func (r *Repository) restoreCascaded(ctx context.Context, collection string, id bson.ObjectID) error {
+	for _, rel := range Relations[collection] {
+		if rel.Policy != Cascade || !isSoftDeleteCollection(rel.Collection) {
+			continue
+		}
+		filter := bson.M{rel.Field: id, "deleted_with": id, "deleted_at": bson.M{"$ne": nil}}
+		update := bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"deleted_at": "", "deleted_by": "", "deleted_with": ""},
+			"$inc":   bson.M{"version": 1},
+		}
+		if _, err := r.DB.Collection(rel.Collection).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// referenceFilter matches the active documents of rel.Collection that
+// reference id.
+func (r *Repository) referenceFilter(rel Relation, id bson.ObjectID) bson.M {
+	filter := bson.M{rel.Field: id}
+	if isSoftDeleteCollection(rel.Collection) {
+		return active(filter)
+	}
+	return filter
+}
+
+func isSoftDeleteCollection(collection string) bool {
+	for _, c := range SoftDeleteCollections {
+		if c == collection {
+			return true
+		}
+	}
+	return false
+}
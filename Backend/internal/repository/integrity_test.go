@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParentRefs(t *testing.T) {
+	league, home, away, match := bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()
+
+	tests := []struct {
+		name       string
+		collection string
+		doc        bson.M
+		want       []parentRef
+	}{
+		{
+			name:       "club of a league",
+			collection: "clubs",
+			doc:        bson.M{"_id": home, "league_id": league, "name": "Saint George"},
+			want:       []parentRef{{parent: "leagues", field: "league_id", ids: []bson.ObjectID{league}}},
+		},
+		{
+			name:       "match of two clubs",
+			collection: "matches",
+			doc:        bson.M{"league_id": league, "home_club_id": home, "away_club_id": away},
+			want: []parentRef{
+				{parent: "clubs", field: "home_club_id", ids: []bson.ObjectID{home}},
+				{parent: "clubs", field: "away_club_id", ids: []bson.ObjectID{away}},
+				{parent: "leagues", field: "league_id", ids: []bson.ObjectID{league}},
+			},
+		},
+		{
+			name:       "watch link with lists",
+			collection: "watch_links",
+			doc:        bson.M{"club_ids": bson.A{home, away}, "match_ids": bson.A{match}, "league_ids": bson.A{}},
+			want: []parentRef{
+				{parent: "clubs", field: "club_ids", ids: []bson.ObjectID{home, away}},
+				{parent: "matches", field: "match_ids", ids: []bson.ObjectID{match}},
+			},
+		},
+		{
+			name:       "club without a league",
+			collection: "clubs",
+			doc:        bson.M{"name": "Saint George"},
+		},
+		{
+			name:       "leagues refer to nothing",
+			collection: "leagues",
+			doc:        bson.M{"_id": league},
+		},
+	}
+	for _, tt := range tests {
+		if got := parentRefs(tt.collection, tt.doc); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parentRefs = %+v; want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"fanzone/internal/models"
+)
+
+// GetPlayers returns active players sorted by name
+func (r *Repository) GetPlayers(ctx context.Context, filter bson.M) ([]models.Player, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.DB.Collection("players").Find(ctx, active(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	players := []models.Player{}
+	err = cursor.All(ctx, &players)
+	return players, err
+}
+
+func (r *Repository) FindPlayerByID(ctx context.Context, id bson.ObjectID) (*models.Player, error) {
+	var player models.Player
+	err := r.DB.Collection("players").FindOne(ctx, active(bson.M{"_id": id})).Decode(&player)
+	return &player, err
+}
+
+func (r *Repository) CreatePlayer(ctx context.Context, player models.Player) error {
+	_, err := r.DB.Collection("players").InsertOne(ctx, player)
+	return err
+}
+
+func (r *Repository) UpdatePlayer(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "players", id, update, version)
+}
+
+func (r *Repository) DeletePlayer(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "players", id, deletedBy)
+}
+
+// GetSquads returns active squads, latest season first
+func (r *Repository) GetSquads(ctx context.Context, filter bson.M) ([]models.Squad, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "season", Value: -1}})
+	cursor, err := r.DB.Collection("squads").Find(ctx, active(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	squads := []models.Squad{}
+	err = cursor.All(ctx, &squads)
+	return squads, err
+}
+
+func (r *Repository) FindSquadByID(ctx context.Context, id bson.ObjectID) (*models.Squad, error) {
+	var squad models.Squad
+	err := r.DB.Collection("squads").FindOne(ctx, active(bson.M{"_id": id})).Decode(&squad)
+	return &squad, err
+}
+
+// FindSquad looks a club's squad up by season
+func (r *Repository) FindSquad(ctx context.Context, clubID bson.ObjectID, season string) (*models.Squad, error) {
+	var squad models.Squad
+	err := r.DB.Collection("squads").FindOne(ctx, active(bson.M{"club_id": clubID, "season": season})).Decode(&squad)
+	return &squad, err
+}
+
+func (r *Repository) CreateSquad(ctx context.Context, squad models.Squad) error {
+	_, err := r.DB.Collection("squads").InsertOne(ctx, squad)
+	return err
+}
+
+func (r *Repository) UpdateSquad(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "squads", id, update, version)
+}
+
+func (r *Repository) DeleteSquad(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "squads", id, deletedBy)
+}
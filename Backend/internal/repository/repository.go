@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -18,6 +21,180 @@ func NewRepository(db *mongo.Database) *Repository {
 	return &Repository{DB: db}
 }
 
+// indexes lists the secondary indexes each collection needs
+var indexes = map[string][]mongo.IndexModel{
+	"content": {
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "league_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "match_id", Value: 1}}},
+		{Keys: bson.D{{Key: "player_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "pinned_until", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"highlights": {
+		{Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "league_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "match_id", Value: 1}}},
+		{Keys: bson.D{{Key: "player_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "pinned_until", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"clubs": {
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"leagues": {
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"watch_links": {
+		{Keys: bson.D{{Key: "league_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "club_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "match_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"matches": {
+		{Keys: bson.D{{Key: "kickoff_at", Value: 1}}},
+		{Keys: bson.D{{Key: "home_club_id", Value: 1}, {Key: "kickoff_at", Value: 1}}},
+		{Keys: bson.D{{Key: "away_club_id", Value: 1}, {Key: "kickoff_at", Value: 1}}},
+		{Keys: bson.D{{Key: "league_id", Value: 1}, {Key: "kickoff_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "kickoff_at", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"seasons": {
+		{Keys: bson.D{{Key: "league_id", Value: 1}, {Key: "name", Value: -1}}},
+		{Keys: bson.D{{Key: "club_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"players": {
+		{Keys: bson.D{{Key: "name", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"squads": {
+		{Keys: bson.D{{Key: "club_id", Value: 1}, {Key: "season", Value: -1}}},
+		{Keys: bson.D{{Key: "player_ids", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"standings": {
+		{Keys: bson.D{{Key: "league_id", Value: 1}, {Key: "season", Value: -1}}},
+	},
+	"match_events": {
+		{Keys: bson.D{{Key: "match_id", Value: 1}, {Key: "minute", Value: 1}, {Key: "stoppage", Value: 1}}},
+		{Keys: bson.D{{Key: "deleted_at", Value: 1}}},
+	},
+	"users": {
+		{Keys: bson.D{{Key: "calendar_token", Value: 1}}, Options: options.Index().SetUnique(true).SetSparse(true)},
+	},
+	"import_jobs": {
+		{Keys: bson.D{{Key: "created_at", Value: -1}}},
+	},
+	// Unique so two edits racing for the same revision number cannot both win
+	"revisions": {
+		{Keys: bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "number", Value: -1}}, Options: options.Index().SetUnique(true)},
+	},
+	"search_index": {
+		{Keys: bson.D{{Key: "collection", Value: 1}, {Key: "terms", Value: 1}}},
+		{Keys: bson.D{{Key: "collection", Value: 1}, {Key: "ref_id", Value: 1}}},
+	},
+}
+
+// EnsureIndexes creates any missing indexes. It is safe to call on every start.
+// A collection whose indexes cannot be built, e.g. because existing documents
+// break a unique index, does not stop the others.
+func (r *Repository) EnsureIndexes(ctx context.Context) error {
+	var errs []error
+	for collection, specs := range indexes {
+		if _, err := r.DB.Collection(collection).Indexes().CreateMany(ctx, specs); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", collection, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SoftDeleteCollections lists the collections whose documents are moved to
+// the trash instead of being removed immediately.
+var SoftDeleteCollections = []string{"clubs", "leagues", "content", "highlights", "watch_links", "matches", "match_events", "seasons", "players", "squads"}
+
+// active restricts a filter to documents that have not been soft deleted.
+func active(filter bson.M) bson.M {
+	f := bson.M{"deleted_at": nil}
+	for k, v := range filter {
+		f[k] = v
+	}
+	return f
+}
+
+// AnyVersion skips the optimistic concurrency check in updateFields
+const AnyVersion int64 = -1
+
+// ErrVersionConflict means the document changed since the caller read it
+var ErrVersionConflict = errors.New("version conflict")
+
+// updateFields applies a partial update to an active document and bumps its
+// version. Fields whose value is nil are removed rather than set. Unless
+// expectedVersion is AnyVersion, the update only applies if the stored
+// version still matches. It returns the new version.
+func (r *Repository) updateFields(ctx context.Context, collection string, id bson.ObjectID, fields bson.M, expectedVersion int64) (int64, error) {
+	set := bson.M{}
+	unset := bson.M{}
+	for k, v := range fields {
+		if v == nil {
+			unset[k] = ""
+		} else {
+			set[k] = v
+		}
+	}
+
+	set["updated_at"] = time.Now()
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	filter := active(bson.M{"_id": id})
+	if expectedVersion != AnyVersion {
+		filter["version"] = versionMatch(expectedVersion)
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"version": 1})
+	var updated struct {
+		Version int64 `bson:"version"`
+	}
+	err := r.DB.Collection(collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err == mongo.ErrNoDocuments && expectedVersion != AnyVersion {
+		// Tell a stale version apart from a missing document
+		count, countErr := r.DB.Collection(collection).CountDocuments(ctx, active(bson.M{"_id": id}))
+		if countErr == nil && count > 0 {
+			return 0, ErrVersionConflict
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return updated.Version, nil
+}
+
+// versionMatch matches a stored version, treating documents written before
+// versioning existed as version 0.
+func versionMatch(version int64) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
 // --- User (Mobile) ---
 
 func (r *Repository) CreateUser(ctx context.Context, user models.User) error {
@@ -48,6 +225,23 @@ func (r *Repository) UpdateUser(ctx context.Context, id bson.ObjectID, update bs
 	return nil
 }
 
+// FindUserByCalendarToken finds the user a calendar subscription URL
+// belongs to
+func (r *Repository) FindUserByCalendarToken(ctx context.Context, token string) (*models.User, error) {
+	var user models.User
+	err := r.DB.Collection("users").FindOne(ctx, bson.M{"calendar_token": token}).Decode(&user)
+	return &user, err
+}
+
+// SetCalendarToken replaces the user's calendar token; an empty token
+// revokes their subscription URL
+func (r *Repository) SetCalendarToken(ctx context.Context, id bson.ObjectID, token string) error {
+	if token == "" {
+		return r.updateUser(ctx, id, bson.M{"$unset": bson.M{"calendar_token": ""}})
+	}
+	return r.updateUser(ctx, id, bson.M{"$set": bson.M{"calendar_token": token}})
+}
+
 func (r *Repository) GetAllUsers(ctx context.Context) ([]models.User, error) {
 	var users []models.User
 	cursor, err := r.DB.Collection("users").Find(ctx, bson.M{})
@@ -59,6 +253,48 @@ func (r *Repository) GetAllUsers(ctx context.Context) ([]models.User, error) {
 	return users, err
 }
 
+// FollowClub adds a club to the user's followed clubs, optionally making it
+// their primary club.
+func (r *Repository) FollowClub(ctx context.Context, userID, clubID bson.ObjectID, primary bool) error {
+	update := bson.M{"$addToSet": bson.M{"followed_club_ids": clubID}}
+	if primary {
+		update["$set"] = bson.M{"fav_club_id": clubID}
+	}
+	return r.updateUser(ctx, userID, update)
+}
+
+// UnfollowClub removes a club from the user's followed clubs, clearing the
+// primary club if it was that club.
+func (r *Repository) UnfollowClub(ctx context.Context, userID, clubID bson.ObjectID) error {
+	err := r.updateUser(ctx, userID, bson.M{"$pull": bson.M{"followed_club_ids": clubID}})
+	if err != nil {
+		return err
+	}
+	_, err = r.DB.Collection("users").UpdateOne(ctx,
+		bson.M{"_id": userID, "fav_club_id": clubID},
+		bson.M{"$unset": bson.M{"fav_club_id": ""}})
+	return err
+}
+
+func (r *Repository) FollowLeague(ctx context.Context, userID, leagueID bson.ObjectID) error {
+	return r.updateUser(ctx, userID, bson.M{"$addToSet": bson.M{"followed_league_ids": leagueID}})
+}
+
+func (r *Repository) UnfollowLeague(ctx context.Context, userID, leagueID bson.ObjectID) error {
+	return r.updateUser(ctx, userID, bson.M{"$pull": bson.M{"followed_league_ids": leagueID}})
+}
+
+func (r *Repository) updateUser(ctx context.Context, id bson.ObjectID, update bson.M) error {
+	result, err := r.DB.Collection("users").UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
 // --- Admin ---
 
 func (r *Repository) CreateAdmin(ctx context.Context, admin models.Admin) error {
@@ -136,7 +372,7 @@ func (r *Repository) DeleteRefreshTokenByID(ctx context.Context, id bson.ObjectI
 
 func (r *Repository) GetClubs(ctx context.Context) ([]models.Club, error) {
 	var clubs []models.Club
-	cursor, err := r.DB.Collection("clubs").Find(ctx, bson.M{})
+	cursor, err := r.DB.Collection("clubs").Find(ctx, active(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -147,30 +383,51 @@ func (r *Repository) GetClubs(ctx context.Context) ([]models.Club, error) {
 
 func (r *Repository) FindClubByID(ctx context.Context, id bson.ObjectID) (*models.Club, error) {
 	var club models.Club
-	err := r.DB.Collection("clubs").FindOne(ctx, bson.M{"_id": id}).Decode(&club)
+	err := r.DB.Collection("clubs").FindOne(ctx, active(bson.M{"_id": id})).Decode(&club)
 	return &club, err
 }
 
+// GetClubIDsByLeagues returns the IDs of the active clubs in any of the leagues
+func (r *Repository) GetClubIDsByLeagues(ctx context.Context, leagueIDs []bson.ObjectID) ([]bson.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.DB.Collection("clubs").Find(ctx, active(bson.M{"league_id": bson.M{"$in": leagueIDs}}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var clubs []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &clubs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, len(clubs))
+	for i, club := range clubs {
+		ids[i] = club.ID
+	}
+	return ids, nil
+}
+
 func (r *Repository) CreateClub(ctx context.Context, club models.Club) error {
 	_, err := r.DB.Collection("clubs").InsertOne(ctx, club)
 	return err
 }
 
-func (r *Repository) UpdateClub(ctx context.Context, id bson.ObjectID, update bson.M) error {
-	_, err := r.DB.Collection("clubs").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
-	return err
+func (r *Repository) UpdateClub(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "clubs", id, update, version)
 }
 
-func (r *Repository) DeleteClub(ctx context.Context, id bson.ObjectID) error {
-	_, err := r.DB.Collection("clubs").DeleteOne(ctx, bson.M{"_id": id})
-	return err
+func (r *Repository) DeleteClub(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "clubs", id, deletedBy)
 }
 
 // --- League ---
 
 func (r *Repository) GetLeagues(ctx context.Context) ([]models.League, error) {
 	var leagues []models.League
-	cursor, err := r.DB.Collection("leagues").Find(ctx, bson.M{})
+	cursor, err := r.DB.Collection("leagues").Find(ctx, active(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -181,7 +438,7 @@ func (r *Repository) GetLeagues(ctx context.Context) ([]models.League, error) {
 
 func (r *Repository) FindLeagueByID(ctx context.Context, id bson.ObjectID) (*models.League, error) {
 	var league models.League
-	err := r.DB.Collection("leagues").FindOne(ctx, bson.M{"_id": id}).Decode(&league)
+	err := r.DB.Collection("leagues").FindOne(ctx, active(bson.M{"_id": id})).Decode(&league)
 	return &league, err
 }
 
@@ -190,21 +447,19 @@ func (r *Repository) CreateLeague(ctx context.Context, league models.League) err
 	return err
 }
 
-func (r *Repository) UpdateLeague(ctx context.Context, id bson.ObjectID, update bson.M) error {
-	_, err := r.DB.Collection("leagues").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
-	return err
+func (r *Repository) UpdateLeague(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "leagues", id, update, version)
 }
 
-func (r *Repository) DeleteLeague(ctx context.Context, id bson.ObjectID) error {
-	_, err := r.DB.Collection("leagues").DeleteOne(ctx, bson.M{"_id": id})
-	return err
+func (r *Repository) DeleteLeague(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "leagues", id, deletedBy)
 }
 
 // --- Content ---
 
 func (r *Repository) GetContent(ctx context.Context, filter bson.M) ([]models.Content, error) {
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := r.DB.Collection("content").Find(ctx, filter, opts)
+	cursor, err := r.DB.Collection("content").Find(ctx, active(filter), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -217,30 +472,35 @@ func (r *Repository) GetContent(ctx context.Context, filter bson.M) ([]models.Co
 
 func (r *Repository) CreateContent(ctx context.Context, content models.Content) error {
 	_, err := r.DB.Collection("content").InsertOne(ctx, content)
+	if err == nil {
+		r.indexForSearch(ctx, "content", content.ID)
+	}
 	return err
 }
 
 func (r *Repository) FindContentByID(ctx context.Context, id bson.ObjectID) (*models.Content, error) {
 	var content models.Content
-	err := r.DB.Collection("content").FindOne(ctx, bson.M{"_id": id}).Decode(&content)
+	err := r.DB.Collection("content").FindOne(ctx, active(bson.M{"_id": id})).Decode(&content)
 	return &content, err
 }
 
-func (r *Repository) UpdateContent(ctx context.Context, id bson.ObjectID, update bson.M) error {
-	_, err := r.DB.Collection("content").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
-	return err
+func (r *Repository) UpdateContent(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	newVersion, err := r.updateFields(ctx, "content", id, update, version)
+	if err == nil {
+		r.indexForSearch(ctx, "content", id)
+	}
+	return newVersion, err
 }
 
-func (r *Repository) DeleteContent(ctx context.Context, id bson.ObjectID) error {
-	_, err := r.DB.Collection("content").DeleteOne(ctx, bson.M{"_id": id})
-	return err
+func (r *Repository) DeleteContent(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "content", id, deletedBy)
 }
 
 // --- Highlight ---
 
 func (r *Repository) GetHighlights(ctx context.Context, filter bson.M) ([]models.Highlight, error) {
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
-	cursor, err := r.DB.Collection("highlights").Find(ctx, filter, opts)
+	cursor, err := r.DB.Collection("highlights").Find(ctx, active(filter), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -253,30 +513,398 @@ func (r *Repository) GetHighlights(ctx context.Context, filter bson.M) ([]models
 
 func (r *Repository) CreateHighlight(ctx context.Context, highlight models.Highlight) error {
 	_, err := r.DB.Collection("highlights").InsertOne(ctx, highlight)
+	if err == nil {
+		r.indexForSearch(ctx, "highlights", highlight.ID)
+	}
 	return err
 }
 
 func (r *Repository) FindHighlightByID(ctx context.Context, id bson.ObjectID) (*models.Highlight, error) {
 	var highlight models.Highlight
-	err := r.DB.Collection("highlights").FindOne(ctx, bson.M{"_id": id}).Decode(&highlight)
+	err := r.DB.Collection("highlights").FindOne(ctx, active(bson.M{"_id": id})).Decode(&highlight)
 	return &highlight, err
 }
 
-func (r *Repository) UpdateHighlight(ctx context.Context, id bson.ObjectID, update bson.M) error {
-	_, err := r.DB.Collection("highlights").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+func (r *Repository) UpdateHighlight(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	newVersion, err := r.updateFields(ctx, "highlights", id, update, version)
+	if err == nil {
+		r.indexForSearch(ctx, "highlights", id)
+	}
+	return newVersion, err
+}
+
+func (r *Repository) DeleteHighlight(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "highlights", id, deletedBy)
+}
+
+// --- Match ---
+
+// GetMatches returns active matches sorted by kickoff, ascending or
+// descending, at most limit of them (0 for no limit)
+func (r *Repository) GetMatches(ctx context.Context, filter bson.M, ascending bool, limit int64) ([]models.Match, error) {
+	direction := -1
+	if ascending {
+		direction = 1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "kickoff_at", Value: direction}, {Key: "_id", Value: direction}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cursor, err := r.DB.Collection("matches").Find(ctx, active(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	matches := []models.Match{}
+	err = cursor.All(ctx, &matches)
+	return matches, err
+}
+
+func (r *Repository) FindMatchByID(ctx context.Context, id bson.ObjectID) (*models.Match, error) {
+	var match models.Match
+	err := r.DB.Collection("matches").FindOne(ctx, active(bson.M{"_id": id})).Decode(&match)
+	return &match, err
+}
+
+func (r *Repository) CreateMatch(ctx context.Context, match models.Match) error {
+	_, err := r.DB.Collection("matches").InsertOne(ctx, match)
 	return err
 }
 
-func (r *Repository) DeleteHighlight(ctx context.Context, id bson.ObjectID) error {
-	_, err := r.DB.Collection("highlights").DeleteOne(ctx, bson.M{"_id": id})
+func (r *Repository) UpdateMatch(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "matches", id, update, version)
+}
+
+func (r *Repository) DeleteMatch(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "matches", id, deletedBy)
+}
+
+// GetMatchEvents returns the timeline of a match in match time, events in the
+// same minute in the order they were posted.
+func (r *Repository) GetMatchEvents(ctx context.Context, matchID bson.ObjectID) ([]models.MatchEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "minute", Value: 1}, {Key: "stoppage", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.DB.Collection("match_events").Find(ctx, active(bson.M{"match_id": matchID}), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.MatchEvent{}
+	err = cursor.All(ctx, &events)
+	return events, err
+}
+
+func (r *Repository) CreateMatchEvent(ctx context.Context, event models.MatchEvent) error {
+	_, err := r.DB.Collection("match_events").InsertOne(ctx, event)
 	return err
 }
 
+// DeleteMatchEvent removes an event from a match timeline, e.g. a goal ruled
+// out, and returns the removed event.
+func (r *Repository) DeleteMatchEvent(ctx context.Context, matchID, id, deletedBy bson.ObjectID) (*models.MatchEvent, error) {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "deleted_by": deletedBy, "updated_at": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var event models.MatchEvent
+	err := r.DB.Collection("match_events").
+		FindOneAndUpdate(ctx, active(bson.M{"_id": id, "match_id": matchID}), update, opts).
+		Decode(&event)
+	return &event, err
+}
+
+// WatchMatchEvents calls fn for every match event posted or removed by any
+// server instance, until ctx is done or the change stream fails.
+func (r *Repository) WatchMatchEvents(ctx context.Context, fn func(models.MatchEvent)) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": bson.A{"insert", "update"}},
+			"ns.coll":       "match_events",
+		}}},
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	stream, err := r.DB.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument *models.MatchEvent `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+		// Documents purged since the update have no full document
+		if change.FullDocument != nil {
+			fn(*change.FullDocument)
+		}
+	}
+	return stream.Err()
+}
+
+// --- Feed ---
+
+// FeedCursor marks the last item of a feed page; the next page starts
+// strictly after it in (created_at, _id) descending order.
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        bson.ObjectID
+}
+
+// FeedEntry is a content or highlight document tagged with its source
+type FeedEntry struct {
+	Type string // "news" or "highlight"
+	Raw  bson.Raw
+}
+
+// GetFeedPage merges content and highlights in the database, newest first,
+// returning at most limit entries after the cursor.
+func (r *Repository) GetFeedPage(ctx context.Context, contentFilter, highlightFilter bson.M, after *FeedCursor, limit int64) ([]FeedEntry, error) {
+	var bound bson.M
+	if after != nil {
+		bound = bson.M{"$or": bson.A{
+			bson.M{"created_at": bson.M{"$lt": after.CreatedAt}},
+			bson.M{"created_at": after.CreatedAt, "_id": bson.M{"$lt": after.ID}},
+		}}
+	}
+	return r.mergeFeed(ctx, contentFilter, highlightFilter, bound, -1, limit)
+}
+
+// GetFeedSince returns at most limit entries newer than since, oldest first.
+// Streaming clients use it to catch up after reconnecting.
+func (r *Repository) GetFeedSince(ctx context.Context, contentFilter, highlightFilter bson.M, since FeedCursor, limit int64) ([]FeedEntry, error) {
+	bound := bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$gt": since.CreatedAt}},
+		bson.M{"created_at": since.CreatedAt, "_id": bson.M{"$gt": since.ID}},
+	}}
+	return r.mergeFeed(ctx, contentFilter, highlightFilter, bound, 1, limit)
+}
+
+// mergeFeed unions matching content and highlights within bound, ordered by
+// (created_at, _id) in direction 1 or -1.
+func (r *Repository) mergeFeed(ctx context.Context, contentFilter, highlightFilter, bound bson.M, direction int, limit int64) ([]FeedEntry, error) {
+	sortStage := bson.D{{Key: "created_at", Value: direction}, {Key: "_id", Value: direction}}
+
+	branch := func(filter bson.M, feedType string) mongo.Pipeline {
+		match := active(filter)
+		if bound != nil {
+			match = bson.M{"$and": bson.A{match, bound}}
+		}
+		return mongo.Pipeline{
+			{{Key: "$match", Value: match}},
+			{{Key: "$sort", Value: sortStage}},
+			{{Key: "$limit", Value: limit}},
+			{{Key: "$addFields", Value: bson.M{"feed_type": feedType}}},
+		}
+	}
+
+	pipeline := append(branch(contentFilter, "news"),
+		bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     "highlights",
+			"pipeline": branch(highlightFilter, "highlight"),
+		}}},
+		bson.D{{Key: "$sort", Value: sortStage}},
+		bson.D{{Key: "$limit", Value: limit}},
+	)
+
+	cursor, err := r.DB.Collection("content").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []FeedEntry
+	for cursor.Next(ctx) {
+		var tag struct {
+			FeedType string `bson:"feed_type"`
+		}
+		if err := cursor.Decode(&tag); err != nil {
+			return nil, err
+		}
+		raw := make(bson.Raw, len(cursor.Current))
+		copy(raw, cursor.Current)
+		entries = append(entries, FeedEntry{Type: tag.FeedType, Raw: raw})
+	}
+	return entries, cursor.Err()
+}
+
+// WatchFeedInserts calls fn for every content item or highlight inserted by
+// any server instance, until ctx is done or the change stream fails. Change
+// streams need MongoDB to run as a replica set.
+func (r *Repository) WatchFeedInserts(ctx context.Context, fn func(FeedEntry)) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType": "insert",
+			"ns.coll":       bson.M{"$in": bson.A{"content", "highlights"}},
+		}}},
+	}
+	stream, err := r.DB.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var change struct {
+			NS struct {
+				Coll string `bson:"coll"`
+			} `bson:"ns"`
+			FullDocument bson.Raw `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+
+		// The decoded document may share the stream's buffer
+		raw := make(bson.Raw, len(change.FullDocument))
+		copy(raw, change.FullDocument)
+
+		entry := FeedEntry{Type: "news", Raw: raw}
+		if change.NS.Coll == "highlights" {
+			entry.Type = "highlight"
+		}
+		fn(entry)
+	}
+	return stream.Err()
+}
+
+// IncrementViews counts one view of a content item or highlight. It leaves
+// updated_at alone so views do not show up as changes to sync.
+func (r *Repository) IncrementViews(ctx context.Context, collection string, id bson.ObjectID) error {
+	_, err := r.DB.Collection(collection).UpdateOne(ctx, active(bson.M{"_id": id}), bson.M{"$inc": bson.M{"views": 1}})
+	return err
+}
+
+// PinnableCollections are the collections whose items can be pinned to the
+// top of feeds
+var PinnableCollections = []string{"content", "highlights"}
+
+// UnpinExpired removes pins that ran out before now, returning how many
+// items were unpinned per collection.
+func (r *Repository) UnpinExpired(ctx context.Context, now time.Time) (map[string]int64, error) {
+	unpinned := make(map[string]int64)
+	for _, collection := range PinnableCollections {
+		result, err := r.DB.Collection(collection).UpdateMany(ctx,
+			bson.M{"pinned_until": bson.M{"$lte": now}},
+			bson.M{
+				"$unset": bson.M{"pinned_until": ""},
+				"$set":   bson.M{"updated_at": now},
+				"$inc":   bson.M{"version": 1},
+			})
+		if err != nil {
+			return unpinned, err
+		}
+		unpinned[collection] = result.ModifiedCount
+	}
+	return unpinned, nil
+}
+
+// CountPinned returns how many active items other than except are pinned at
+// now, across every pinnable collection
+func (r *Repository) CountPinned(ctx context.Context, now time.Time, except bson.ObjectID) (int64, error) {
+	var total int64
+	for _, collection := range PinnableCollections {
+		filter := active(bson.M{"pinned_until": bson.M{"$gt": now}, "_id": bson.M{"$ne": except}})
+		count, err := r.DB.Collection(collection).CountDocuments(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+// rankingSettingsID is the settings document holding the feed ranking weights
+const rankingSettingsID = "feed_ranking"
+
+// GetRankingWeights returns mongo.ErrNoDocuments until weights are saved
+func (r *Repository) GetRankingWeights(ctx context.Context) (*models.RankingWeights, error) {
+	var weights models.RankingWeights
+	err := r.DB.Collection("settings").FindOne(ctx, bson.M{"_id": rankingSettingsID}).Decode(&weights)
+	if err != nil {
+		return nil, err
+	}
+	return &weights, nil
+}
+
+func (r *Repository) SaveRankingWeights(ctx context.Context, weights models.RankingWeights) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.DB.Collection("settings").ReplaceOne(ctx, bson.M{"_id": rankingSettingsID}, weights, opts)
+	return err
+}
+
+// --- Revision ---
+
+func (r *Repository) CreateRevision(ctx context.Context, revision models.Revision) error {
+	_, err := r.DB.Collection("revisions").InsertOne(ctx, revision)
+	return err
+}
+
+func (r *Repository) GetRevisions(ctx context.Context, entity string, entityID bson.ObjectID) ([]models.Revision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "number", Value: -1}})
+	cursor, err := r.DB.Collection("revisions").Find(ctx, bson.M{"entity": entity, "entity_id": entityID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []models.Revision
+	err = cursor.All(ctx, &revisions)
+	return revisions, err
+}
+
+func (r *Repository) FindRevision(ctx context.Context, entity string, entityID, id bson.ObjectID) (*models.Revision, error) {
+	var revision models.Revision
+	filter := bson.M{"_id": id, "entity": entity, "entity_id": entityID}
+	err := r.DB.Collection("revisions").FindOne(ctx, filter).Decode(&revision)
+	return &revision, err
+}
+
+// LatestRevisionNumber returns the highest revision number recorded for an
+// entity, or 0 when it has no history yet.
+func (r *Repository) LatestRevisionNumber(ctx context.Context, entity string, entityID bson.ObjectID) (int, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}})
+	var revision models.Revision
+	err := r.DB.Collection("revisions").FindOne(ctx, bson.M{"entity": entity, "entity_id": entityID}, opts).Decode(&revision)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return revision.Number, nil
+}
+
+// ReplaceDocument overwrites a whole document, used when restoring revisions.
+// As with updates, only expectedVersion is replaced unless it is AnyVersion.
+func (r *Repository) ReplaceDocument(ctx context.Context, collection string, id bson.ObjectID, doc bson.M, expectedVersion int64) error {
+	filter := active(bson.M{"_id": id})
+	if expectedVersion != AnyVersion {
+		filter["version"] = versionMatch(expectedVersion)
+	}
+	result, err := r.DB.Collection(collection).ReplaceOne(ctx, filter, doc)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		count, err := r.DB.Collection(collection).CountDocuments(ctx, active(bson.M{"_id": id}))
+		if err == nil && count > 0 {
+			return ErrVersionConflict
+		}
+		return mongo.ErrNoDocuments
+	}
+	r.indexForSearch(ctx, collection, id)
+	return nil
+}
+
 // --- WatchLink ---
 
 func (r *Repository) GetWatchLinks(ctx context.Context) ([]models.WatchLink, error) {
 	var links []models.WatchLink
-	cursor, err := r.DB.Collection("watch_links").Find(ctx, bson.M{})
+	cursor, err := r.DB.Collection("watch_links").Find(ctx, active(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -292,20 +920,155 @@ func (r *Repository) CreateWatchLink(ctx context.Context, link models.WatchLink)
 
 func (r *Repository) FindWatchLinkByID(ctx context.Context, id bson.ObjectID) (*models.WatchLink, error) {
 	var link models.WatchLink
-	err := r.DB.Collection("watch_links").FindOne(ctx, bson.M{"_id": id}).Decode(&link)
+	err := r.DB.Collection("watch_links").FindOne(ctx, active(bson.M{"_id": id})).Decode(&link)
 	return &link, err
 }
 
-func (r *Repository) UpdateWatchLink(ctx context.Context, id bson.ObjectID, update bson.M) error {
-	_, err := r.DB.Collection("watch_links").UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
-	return err
+func (r *Repository) UpdateWatchLink(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "watch_links", id, update, version)
+}
+
+func (r *Repository) DeleteWatchLink(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "watch_links", id, deletedBy)
+}
+
+// --- Trash ---
+
+func (r *Repository) softDelete(ctx context.Context, collection string, id, deletedBy bson.ObjectID) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "deleted_by": deletedBy, "updated_at": now}}
+	result, err := r.DB.Collection(collection).UpdateOne(ctx, active(bson.M{"_id": id}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
-func (r *Repository) DeleteWatchLink(ctx context.Context, id bson.ObjectID) error {
-	_, err := r.DB.Collection("watch_links").DeleteOne(ctx, bson.M{"_id": id})
+func (r *Repository) GetTrash(ctx context.Context, collection string) ([]bson.M, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+	cursor, err := r.DB.Collection(collection).Find(ctx, bson.M{"deleted_at": bson.M{"$ne": nil}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []bson.M
+	err = cursor.All(ctx, &items)
+	return items, err
+}
+
+// RestoreFromTrash brings back a soft-deleted document as a new version,
+// along with the documents its delete cascaded to. It fails with a
+// MissingParentsError while a document it refers to is deleted.
+func (r *Repository) RestoreFromTrash(ctx context.Context, collection string, id bson.ObjectID) error {
+	session, err := r.DB.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+		coll := r.DB.Collection(collection)
+		filter := bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}
+		var doc bson.M
+		if err := coll.FindOne(ctx, filter).Decode(&doc); err != nil {
+			return nil, err
+		}
+		if err := r.CheckReferences(ctx, collection, id, doc); err != nil {
+			return nil, err
+		}
+
+		update := bson.M{
+			"$set":   bson.M{"updated_at": time.Now()},
+			"$unset": bson.M{"deleted_at": "", "deleted_by": "", "deleted_with": ""},
+			"$inc":   bson.M{"version": 1},
+		}
+		if _, err := coll.UpdateOne(ctx, filter, update); err != nil {
+			return nil, err
+		}
+		return nil, r.restoreCascaded(ctx, collection, id)
+	})
 	return err
 }
 
+// PurgeTrash permanently removes documents that were soft deleted before the
+// given time and returns how many were removed per collection.
+func (r *Repository) PurgeTrash(ctx context.Context, before time.Time) (map[string]int64, error) {
+	purged := make(map[string]int64)
+	for _, collection := range SoftDeleteCollections {
+		result, err := r.DB.Collection(collection).DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+		if err != nil {
+			return purged, err
+		}
+		purged[collection] = result.DeletedCount
+	}
+	return purged, nil
+}
+
+// --- Sync ---
+
+// SyncPosition is the last document a sync page returned from a collection
+type SyncPosition struct {
+	UpdatedAt time.Time
+	ID        bson.ObjectID
+}
+
+// GetChangedSince returns up to limit active documents of a collection
+// written after since, oldest first, resuming after the given position. A
+// zero since returns every active document.
+func (r *Repository) GetChangedSince(ctx context.Context, collection string, since time.Time, after *SyncPosition, limit int64) ([]bson.M, error) {
+	filter := bson.M{}
+	if !since.IsZero() {
+		filter["updated_at"] = bson.M{"$gt": since}
+	}
+	if after != nil {
+		filter["$or"] = bson.A{
+			bson.M{"updated_at": bson.M{"$gt": after.UpdatedAt}},
+			bson.M{"updated_at": after.UpdatedAt, "_id": bson.M{"$gt": after.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+	cursor, err := r.DB.Collection(collection).Find(ctx, active(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.M
+	err = cursor.All(ctx, &docs)
+	return docs, err
+}
+
+// GetDeletedSince returns the IDs of documents moved to the trash after since.
+// Trashed documents act as tombstones until PurgeTrash removes them.
+func (r *Repository) GetDeletedSince(ctx context.Context, collection string, since time.Time) ([]bson.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.DB.Collection(collection).Find(ctx, bson.M{"deleted_at": bson.M{"$gt": since}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
 func (r *Repository) GetCounts(ctx context.Context) (map[string]int64, error) {
 	counts := make(map[string]int64)
 
@@ -315,19 +1078,19 @@ func (r *Repository) GetCounts(ctx context.Context) (map[string]int64, error) {
 	adminCount, _ := r.DB.Collection("admins").CountDocuments(ctx, bson.M{})
 	counts["admins"] = adminCount
 
-	contentCount, _ := r.DB.Collection("content").CountDocuments(ctx, bson.M{})
+	contentCount, _ := r.DB.Collection("content").CountDocuments(ctx, active(bson.M{}))
 	counts["content"] = contentCount
 
-	clubCount, _ := r.DB.Collection("clubs").CountDocuments(ctx, bson.M{})
+	clubCount, _ := r.DB.Collection("clubs").CountDocuments(ctx, active(bson.M{}))
 	counts["clubs"] = clubCount
 
-	highlightCount, _ := r.DB.Collection("highlights").CountDocuments(ctx, bson.M{})
+	highlightCount, _ := r.DB.Collection("highlights").CountDocuments(ctx, active(bson.M{}))
 	counts["highlights"] = highlightCount
 
-	watchLinkCount, _ := r.DB.Collection("watch_links").CountDocuments(ctx, bson.M{})
+	watchLinkCount, _ := r.DB.Collection("watch_links").CountDocuments(ctx, active(bson.M{}))
 	counts["watch_links"] = watchLinkCount
 
-	leagueCount, _ := r.DB.Collection("leagues").CountDocuments(ctx, bson.M{})
+	leagueCount, _ := r.DB.Collection("leagues").CountDocuments(ctx, active(bson.M{}))
 	counts["leagues"] = leagueCount
 
 	return counts, nil
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"fanzone/internal/search"
+)
+
+// SearchFields lists the text fields indexed for search per collection.
+// Clubs and leagues are few and cached, so they are matched in memory
+// instead of through the index.
+var SearchFields = map[string][]string{
+	"content":    {"title.en", "title.am", "title.om", "body.en", "body.am", "body.om"},
+	"highlights": {"match_title"},
+}
+
+// searchEntry holds the terms of one document. Entries live in their own
+// collection so the terms never show up in documents, sync payloads or
+// revisions.
+type searchEntry struct {
+	ID         string        `bson:"_id"`
+	Collection string        `bson:"collection"`
+	RefID      bson.ObjectID `bson:"ref_id"`
+	Terms      []string      `bson:"terms"`
+}
+
+func searchEntryID(collection string, id bson.ObjectID) string {
+	return collection + ":" + id.Hex()
+}
+
+// lookupPath reads a dotted path such as "title.en" from a document
+func lookupPath(doc bson.M, path string) string {
+	var v interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		switch m := v.(type) {
+		case bson.M:
+			v = m[key]
+		case bson.D:
+			v = nil
+			for _, e := range m {
+				if e.Key == key {
+					v = e.Value
+				}
+			}
+		default:
+			return ""
+		}
+	}
+	s, _ := v.(string)
+	return s
+}
+
+// IndexForSearch recomputes the search terms of a document. Documents that no
+// longer exist lose their entry; soft-deleted ones keep it, as searches only
+// return active documents and a restore needs no reindex.
+func (r *Repository) IndexForSearch(ctx context.Context, collection string, id bson.ObjectID) error {
+	fields, ok := SearchFields[collection]
+	if !ok {
+		return nil
+	}
+
+	var doc bson.M
+	err := r.DB.Collection(collection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		_, err = r.DB.Collection("search_index").DeleteOne(ctx, bson.M{"_id": searchEntryID(collection, id)})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return r.saveSearchEntry(ctx, collection, doc, fields)
+}
+
+func (r *Repository) saveSearchEntry(ctx context.Context, collection string, doc bson.M, fields []string) error {
+	id, _ := doc["_id"].(bson.ObjectID)
+	texts := make([]string, len(fields))
+	for i, path := range fields {
+		texts[i] = lookupPath(doc, path)
+	}
+
+	entry := searchEntry{
+		ID:         searchEntryID(collection, id),
+		Collection: collection,
+		RefID:      id,
+		Terms:      search.Terms(texts...),
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.DB.Collection("search_index").ReplaceOne(ctx, bson.M{"_id": entry.ID}, entry, opts)
+	return err
+}
+
+// indexForSearch reindexes after a write. A failure is only logged: the write
+// already succeeded and the nightly reindex repairs the entry.
+func (r *Repository) indexForSearch(ctx context.Context, collection string, id bson.ObjectID) {
+	if err := r.IndexForSearch(ctx, collection, id); err != nil {
+		log.Printf("[Search] Failed to index %s %s: %v", collection, id.Hex(), err)
+	}
+}
+
+// ReindexSearch rebuilds the entries of every searchable document and drops
+// those of purged documents. It returns how many documents were indexed per
+// collection.
+func (r *Repository) ReindexSearch(ctx context.Context) (map[string]int64, error) {
+	indexed := make(map[string]int64)
+	for collection, fields := range SearchFields {
+		cursor, err := r.DB.Collection(collection).Find(ctx, bson.M{})
+		if err != nil {
+			return indexed, err
+		}
+
+		ids := bson.A{}
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				cursor.Close(ctx)
+				return indexed, err
+			}
+			if err := r.saveSearchEntry(ctx, collection, doc, fields); err != nil {
+				cursor.Close(ctx)
+				return indexed, err
+			}
+			ids = append(ids, doc["_id"])
+			indexed[collection]++
+		}
+		err = cursor.Err()
+		cursor.Close(ctx)
+		if err != nil {
+			return indexed, err
+		}
+
+		stale := bson.M{"collection": collection, "ref_id": bson.M{"$nin": ids}}
+		if _, err := r.DB.Collection("search_index").DeleteMany(ctx, stale); err != nil {
+			return indexed, err
+		}
+	}
+	return indexed, nil
+}
+
+// minPrefixRunes is the shortest query term matched as a prefix. Shorter
+// terms, such as "a", start most words, so they only match whole words, as
+// in search.Query.Match.
+const minPrefixRunes = 2
+
+// candidateConditions returns, per query term, a filter on the terms of an
+// entry and an expression that is 1 when the entry matches the term. Short
+// terms are left out unless the query has nothing else.
+func candidateConditions(terms []string) (bson.A, bson.A) {
+	var long []string
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) >= minPrefixRunes {
+			long = append(long, term)
+		}
+	}
+	if len(long) > 0 {
+		terms = long
+	}
+
+	filters, matched := bson.A{}, bson.A{}
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) < minPrefixRunes {
+			filters = append(filters, bson.M{"terms": term})
+			matched = append(matched, bson.M{"$cond": bson.A{bson.M{"$in": bson.A{term, "$terms"}}, 1, 0}})
+			continue
+		}
+		// Anchored prefix regexes can use the terms index
+		prefix := "^" + regexp.QuoteMeta(term)
+		filters = append(filters, bson.M{"terms": bson.M{"$regex": prefix}})
+		matched = append(matched, bson.M{"$cond": bson.A{
+			bson.M{"$anyElementTrue": bson.A{bson.M{"$map": bson.M{
+				"input": "$terms",
+				"as":    "term",
+				"in":    bson.M{"$regexMatch": bson.M{"input": "$$term", "regex": prefix}},
+			}}}},
+			1, 0,
+		}})
+	}
+	return filters, matched
+}
+
+// SearchCandidates returns the IDs of documents with a term starting with one
+// of the query terms, at most limit of them. Documents matching more of the
+// terms come first, then newer ones; the final ranking is left to the caller.
+func (r *Repository) SearchCandidates(ctx context.Context, collection string, terms []string, limit int64) ([]bson.ObjectID, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	filters, matched := candidateConditions(terms)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"collection": collection, "$or": filters}}},
+		{{Key: "$project", Value: bson.M{"ref_id": 1, "matched": bson.M{"$add": matched}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "matched", Value: -1}, {Key: "ref_id", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.DB.Collection("search_index").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []searchEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.RefID
+	}
+	return ids, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCandidateConditions(t *testing.T) {
+	prefix := func(term string) bson.M { return bson.M{"terms": bson.M{"$regex": "^" + term}} }
+	tests := []struct {
+		name  string
+		terms []string
+		want  bson.A
+	}{
+		{"prefixes", []string{"saint", "george"}, bson.A{prefix("saint"), prefix("george")}},
+		{"short terms are dropped", []string{"a", "buna", "ቡ"}, bson.A{prefix("buna")}},
+		{"only short terms match whole words", []string{"a", "ቡ"}, bson.A{bson.M{"terms": "a"}, bson.M{"terms": "ቡ"}}},
+		{"regex characters are quoted", []string{"c++"}, bson.A{prefix(`c\+\+`)}},
+	}
+	for _, tt := range tests {
+		filters, matched := candidateConditions(tt.terms)
+		if !reflect.DeepEqual(filters, tt.want) {
+			t.Errorf("%s: filters = %v; want %v", tt.name, filters, tt.want)
+		}
+		if len(matched) != len(filters) {
+			t.Errorf("%s: %d match counters for %d filters", tt.name, len(matched), len(filters))
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"fanzone/internal/models"
+)
+
+// GetSeasons returns active seasons, latest first
+func (r *Repository) GetSeasons(ctx context.Context, filter bson.M) ([]models.Season, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: -1}})
+	cursor, err := r.DB.Collection("seasons").Find(ctx, active(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	seasons := []models.Season{}
+	err = cursor.All(ctx, &seasons)
+	return seasons, err
+}
+
+func (r *Repository) FindSeasonByID(ctx context.Context, id bson.ObjectID) (*models.Season, error) {
+	var season models.Season
+	err := r.DB.Collection("seasons").FindOne(ctx, active(bson.M{"_id": id})).Decode(&season)
+	return &season, err
+}
+
+// FindSeason looks a season up by league and name
+func (r *Repository) FindSeason(ctx context.Context, leagueID bson.ObjectID, name string) (*models.Season, error) {
+	var season models.Season
+	err := r.DB.Collection("seasons").FindOne(ctx, active(bson.M{"league_id": leagueID, "name": name})).Decode(&season)
+	return &season, err
+}
+
+// FindCurrentSeason returns the season marked current, or else the latest
+// one. It returns mongo.ErrNoDocuments for a league without seasons.
+func (r *Repository) FindCurrentSeason(ctx context.Context, leagueID bson.ObjectID) (*models.Season, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "current", Value: -1}, {Key: "name", Value: -1}})
+	var season models.Season
+	err := r.DB.Collection("seasons").FindOne(ctx, active(bson.M{"league_id": leagueID}), opts).Decode(&season)
+	return &season, err
+}
+
+func (r *Repository) CreateSeason(ctx context.Context, season models.Season) error {
+	_, err := r.DB.Collection("seasons").InsertOne(ctx, season)
+	return err
+}
+
+func (r *Repository) UpdateSeason(ctx context.Context, id bson.ObjectID, update bson.M, version int64) (int64, error) {
+	return r.updateFields(ctx, "seasons", id, update, version)
+}
+
+func (r *Repository) DeleteSeason(ctx context.Context, id, deletedBy bson.ObjectID) error {
+	return r.deleteEntity(ctx, "seasons", id, deletedBy)
+}
+
+// ClearCurrentSeason unmarks every current season of a league but one
+func (r *Repository) ClearCurrentSeason(ctx context.Context, leagueID, except bson.ObjectID) error {
+	filter := active(bson.M{"league_id": leagueID, "current": true, "_id": bson.M{"$ne": except}})
+	update := bson.M{
+		"$set": bson.M{"current": false, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
+	_, err := r.DB.Collection("seasons").UpdateMany(ctx, filter, update)
+	return err
+}
+
+// ApplyCurrentSeason moves the clubs of a league's current season into the
+// league, so Club.LeagueID follows promotion and relegation. It returns the
+// clubs that moved.
+func (r *Repository) ApplyCurrentSeason(ctx context.Context, leagueID bson.ObjectID) ([]bson.ObjectID, error) {
+	season, err := r.FindCurrentSeason(ctx, leagueID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil || len(season.ClubIDs) == 0 {
+		return nil, err
+	}
+
+	filter := active(bson.M{"_id": bson.M{"$in": season.ClubIDs}, "league_id": bson.M{"$ne": leagueID}})
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := r.DB.Collection("clubs").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var clubs []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &clubs); err != nil || len(clubs) == 0 {
+		return nil, err
+	}
+
+	moved := make([]bson.ObjectID, len(clubs))
+	for i, club := range clubs {
+		moved[i] = club.ID
+	}
+	update := bson.M{
+		"$set": bson.M{"league_id": leagueID, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
+	_, err = r.DB.Collection("clubs").UpdateMany(ctx, bson.M{"_id": bson.M{"$in": moved}}, update)
+	return moved, err
+}
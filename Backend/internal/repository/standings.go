@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"fanzone/internal/models"
+	"fanzone/internal/standings"
+)
+
+// StandingsKey selects the league tables to recompute. A zero LeagueID means
+// every league and an empty Season every season.
+type StandingsKey struct {
+	LeagueID bson.ObjectID `bson:"league_id"`
+	Season   string        `bson:"season"`
+}
+
+func standingsID(leagueID bson.ObjectID, season string) string {
+	return leagueID.Hex() + ":" + season
+}
+
+// scope is the filter matching the key's matches or standings
+func (k StandingsKey) scope() bson.M {
+	filter := bson.M{}
+	if !k.LeagueID.IsZero() {
+		filter["league_id"] = k.LeagueID
+	}
+	if k.Season != "" {
+		filter["season"] = k.Season
+	}
+	return filter
+}
+
+// RecomputeStandings rebuilds the tables of every league season in the key
+// that has matches or a season record, drops tables left with neither and
+// returns how many were saved.
+func (r *Repository) RecomputeStandings(ctx context.Context, key StandingsKey) (int, error) {
+	matchSeasons, err := r.standingsKeys(ctx, "matches", "$season", key.scope())
+	if err != nil {
+		return 0, err
+	}
+	scope := key.scope()
+	if key.Season != "" {
+		delete(scope, "season")
+		scope["name"] = key.Season
+	}
+	recordedSeasons, err := r.standingsKeys(ctx, "seasons", "$name", scope)
+	if err != nil {
+		return 0, err
+	}
+
+	saved := bson.A{}
+	seen := map[StandingsKey]bool{}
+	for _, k := range append(matchSeasons, recordedSeasons...) {
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		table, err := r.computeStandings(ctx, k)
+		if err != nil {
+			return len(saved), err
+		}
+		opts := options.Replace().SetUpsert(true)
+		if _, err := r.DB.Collection("standings").ReplaceOne(ctx, bson.M{"_id": table.ID}, table, opts); err != nil {
+			return len(saved), err
+		}
+		saved = append(saved, table.ID)
+	}
+
+	stale := key.scope()
+	stale["_id"] = bson.M{"$nin": saved}
+	_, err = r.DB.Collection("standings").DeleteMany(ctx, stale)
+	return len(saved), err
+}
+
+// standingsKeys lists the distinct league seasons of the active documents
+// of a collection, seasonField naming the field that holds the season
+func (r *Repository) standingsKeys(ctx context.Context, collection, seasonField string, filter bson.M) ([]StandingsKey, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: active(filter)}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"league_id": "$league_id", "season": seasonField}}}},
+	}
+	cursor, err := r.DB.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		ID StandingsKey `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	keys := make([]StandingsKey, len(groups))
+	for i, group := range groups {
+		keys[i] = group.ID
+	}
+	return keys, nil
+}
+
+func (r *Repository) computeStandings(ctx context.Context, key StandingsKey) (models.Standings, error) {
+	var tiebreakers []string
+	league, err := r.FindLeagueByID(ctx, key.LeagueID)
+	if err == nil {
+		tiebreakers = league.Tiebreakers
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Standings{}, err
+	}
+	if len(tiebreakers) == 0 {
+		tiebreakers = standings.DefaultTiebreakers
+	}
+
+	var clubIDs []bson.ObjectID
+	season, err := r.FindSeason(ctx, key.LeagueID, key.Season)
+	if err == nil {
+		clubIDs = season.ClubIDs
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return models.Standings{}, err
+	}
+
+	matches, err := r.GetMatches(ctx, key.scope(), true, 0)
+	if err != nil {
+		return models.Standings{}, err
+	}
+	return models.Standings{
+		ID:          standingsID(key.LeagueID, key.Season),
+		LeagueID:    key.LeagueID,
+		Season:      key.Season,
+		Tiebreakers: tiebreakers,
+		Rows:        standings.Compute(matches, clubIDs, tiebreakers),
+		ComputedAt:  time.Now(),
+	}, nil
+}
+
+func (r *Repository) GetStandings(ctx context.Context, leagueID bson.ObjectID, season string) (*models.Standings, error) {
+	var table models.Standings
+	err := r.DB.Collection("standings").FindOne(ctx, bson.M{"_id": standingsID(leagueID, season)}).Decode(&table)
+	return &table, err
+}
+
+// GetStandingsSeasons lists the seasons a league has tables for, latest first
+func (r *Repository) GetStandingsSeasons(ctx context.Context, leagueID bson.ObjectID) ([]string, error) {
+	opts := options.Find().SetProjection(bson.M{"season": 1}).SetSort(bson.D{{Key: "season", Value: -1}})
+	cursor, err := r.DB.Collection("standings").Find(ctx, bson.M{"league_id": leagueID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		Season string `bson:"season"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	seasons := make([]string, len(docs))
+	for i, doc := range docs {
+		seasons[i] = doc.Season
+	}
+	return seasons, nil
+}
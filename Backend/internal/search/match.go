@@ -0,0 +1,199 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// snippetLength is the most runes a snippet shows around the first match
+const snippetLength = 120
+
+// Field is one searchable text of a document. Weight says how much a match
+// in it counts, e.g. titles count more than bodies.
+type Field struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+// Snippet is an excerpt of the best matching field. Matches are [start, end)
+// character offsets into Text of the matched words.
+type Snippet struct {
+	Field   string   `json:"field"`
+	Text    string   `json:"text"`
+	Matches [][2]int `json:"matches"`
+}
+
+// Result is how well a document matched a query
+type Result struct {
+	Score   float64
+	Snippet *Snippet
+}
+
+// Query holds the terms of a search string
+type Query struct {
+	Terms []string
+}
+
+// ParseQuery tokenises a search string the same way documents are indexed
+func ParseQuery(q string) Query {
+	return Query{Terms: Terms(q)}
+}
+
+// Empty reports whether the query has no searchable words
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0
+}
+
+// termMatch scores a document word against a query term: a whole word counts
+// fully, a word the term is a prefix of counts partly, so "buna" finds
+// "bunaa" and "ኢትዮ" finds "ኢትዮጵያ".
+func termMatch(term, word string) float64 {
+	switch {
+	case word == term:
+		return 1
+	case utf8.RuneCountInString(term) >= 2 && strings.HasPrefix(word, term):
+		return 0.6
+	}
+	return 0
+}
+
+// Match scores fields against the query. A document scores more the more
+// query terms it matches, in heavier fields, whole rather than by prefix,
+// and as a phrase. A zero score means no term matched.
+func (q Query) Match(fields []Field) Result {
+	var result Result
+	matched := make([]bool, len(q.Terms))
+	bestField := -1.0
+
+	for _, field := range fields {
+		tokens := Tokenize(field.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		fieldScore := 0.0
+		var hits [][2]int
+		positions := make([][]int, len(q.Terms))
+		for i, term := range q.Terms {
+			best := 0.0
+			for j, token := range tokens {
+				s := termMatch(term, token.Term)
+				if s == 0 {
+					continue
+				}
+				positions[i] = append(positions[i], j)
+				hits = append(hits, [2]int{token.Start, token.End})
+				if s > best {
+					best = s
+				}
+			}
+			if best > 0 {
+				matched[i] = true
+				fieldScore += best * field.Weight
+			}
+		}
+		if fieldScore == 0 {
+			continue
+		}
+		if isPhrase(positions) {
+			fieldScore += field.Weight
+		}
+
+		result.Score += fieldScore
+		if fieldScore > bestField {
+			bestField = fieldScore
+			result.Snippet = makeSnippet(field, hits)
+		}
+	}
+
+	if result.Score > 0 && len(q.Terms) > 1 {
+		all := true
+		for _, m := range matched {
+			all = all && m
+		}
+		if all {
+			result.Score *= 1.5
+		}
+	}
+	return result
+}
+
+// isPhrase reports whether the terms occur as consecutive words in order
+func isPhrase(positions [][]int) bool {
+	if len(positions) < 2 {
+		return false
+	}
+	for _, start := range positions[0] {
+		ok := true
+		for i := 1; i < len(positions) && ok; i++ {
+			ok = false
+			for _, p := range positions[i] {
+				if p == start+i {
+					ok = true
+					break
+				}
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// makeSnippet cuts a window of the field text around its first match. Cuts
+// are marked with an ellipsis and match offsets are shifted to the snippet.
+func makeSnippet(field Field, hits [][2]int) *Snippet {
+	runes := []rune(field.Text)
+	first := hits[0][0]
+	for _, h := range hits {
+		if h[0] < first {
+			first = h[0]
+		}
+	}
+
+	from := 0
+	if len(runes) > snippetLength {
+		from = first - snippetLength/4
+		if from < 0 {
+			from = 0
+		}
+		if from > len(runes)-snippetLength {
+			from = len(runes) - snippetLength
+		}
+		// Start on a word boundary
+		for from > 0 && from < first && isWordRune(runes[from-1]) {
+			from++
+		}
+	}
+	to := from + snippetLength
+	if to > len(runes) {
+		to = len(runes)
+	}
+	for to < len(runes) && to > first && isWordRune(runes[to]) {
+		to--
+	}
+
+	prefix := ""
+	if from > 0 {
+		prefix = "…"
+	}
+	shift := utf8.RuneCountInString(prefix) - from
+
+	snippet := &Snippet{Field: field.Name, Text: prefix + string(runes[from:to]), Matches: [][2]int{}}
+	if to < len(runes) {
+		snippet.Text += "…"
+	}
+	seen := map[[2]int]bool{}
+	sort.Slice(hits, func(i, j int) bool { return hits[i][0] < hits[j][0] })
+	for _, h := range hits {
+		if h[0] < from || h[1] > to || seen[h] {
+			continue
+		}
+		seen[h] = true
+		snippet.Matches = append(snippet.Matches, [2]int{h[0] + shift, h[1] + shift})
+	}
+	return snippet
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTermMatch(t *testing.T) {
+	tests := []struct {
+		term, word string
+		want       float64
+	}{
+		{"buna", "buna", 1},
+		{"buna", "bunaa", 0.6},
+		{"ኢትዮ", "ኢትዮጵያ", 0.6},
+		{"b", "buna", 0}, // one letter is too short for a prefix
+		{"bunaa", "buna", 0},
+	}
+	for _, tt := range tests {
+		if got := termMatch(tt.term, tt.word); got != tt.want {
+			t.Errorf("termMatch(%q, %q) = %v; want %v", tt.term, tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestMatchRanking(t *testing.T) {
+	title := func(text string) []Field {
+		return []Field{{Name: "title", Text: text, Weight: 3}, {Name: "body", Text: "Match report", Weight: 1}}
+	}
+	body := func(text string) []Field {
+		return []Field{{Name: "title", Text: "Match report", Weight: 3}, {Name: "body", Text: text, Weight: 1}}
+	}
+	q := ParseQuery("saint george")
+
+	// From best to worst
+	ranked := []struct {
+		name   string
+		fields []Field
+	}{
+		{"phrase in the title", title("Saint George win the derby")},
+		{"both words apart in the title", title("George beat Saint Mary")},
+		{"phrase in the body", body("Saint George win the derby")},
+		{"one word in the title", title("George Best remembered")},
+		{"prefix of one word in the title", title("Georgetown visit")},
+		{"one word in the body", body("George Best remembered")},
+	}
+	prev := 0.0
+	for i, tt := range ranked {
+		got := q.Match(tt.fields).Score
+		if got <= 0 {
+			t.Errorf("%s: no match", tt.name)
+		}
+		if i > 0 && got >= prev {
+			t.Errorf("%s scores %v, not below %s at %v", tt.name, got, ranked[i-1].name, prev)
+		}
+		prev = got
+	}
+
+	if got := q.Match(title("Buna win the derby")); got.Score != 0 || got.Snippet != nil {
+		t.Errorf("unrelated document = %+v; want no match", got)
+	}
+}
+
+func TestMatchExactScores(t *testing.T) {
+	fields := []Field{{Name: "title", Text: "Saint George win", Weight: 2}}
+	tests := []struct {
+		query string
+		want  float64
+	}{
+		{"george", 2},
+		{"geo", 1.2},
+		// Both terms, plus the phrase bonus, times 1.5 for matching all
+		{"saint george", (2 + 2 + 2) * 1.5},
+		{"george saint", (2 + 2) * 1.5},
+		{"george buna", 2},
+	}
+	for _, tt := range tests {
+		if got := ParseQuery(tt.query).Match(fields).Score; got != tt.want {
+			t.Errorf("%q: score %v; want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	fields := []Field{
+		{Name: "title", Text: "Derby report", Weight: 3},
+		{Name: "body", Text: "At the stadium, Saint George beat Buna.", Weight: 1},
+	}
+	got := ParseQuery("george buna").Match(fields).Snippet
+	if got == nil || got.Field != "body" || got.Text != fields[1].Text {
+		t.Fatalf("snippet = %+v; want the whole short body", got)
+	}
+	want := [][2]int{{22, 28}, {34, 38}}
+	if !slices.Equal(got.Matches, want) {
+		t.Errorf("matches = %v; want %v", got.Matches, want)
+	}
+}
+
+func TestSnippetOfLongText(t *testing.T) {
+	text := strings.Repeat("filler words here ", 20) + "Saint George scored late " + strings.Repeat("and more words ", 20)
+	got := ParseQuery("george").Match([]Field{{Name: "body", Text: text, Weight: 1}}).Snippet
+	runes := []rune(got.Text)
+	if !strings.HasPrefix(got.Text, "…") || !strings.HasSuffix(got.Text, "…") {
+		t.Errorf("snippet %q; want ellipses at both cuts", got.Text)
+	}
+	if len(runes) > snippetLength+2 {
+		t.Errorf("snippet has %d runes; want at most %d", len(runes), snippetLength+2)
+	}
+	if len(got.Matches) != 1 {
+		t.Fatalf("matches = %v; want one", got.Matches)
+	}
+	if m := got.Matches[0]; string(runes[m[0]:m[1]]) != "George" {
+		t.Errorf("match %v covers %q; want George", m, string(runes[m[0]:m[1]]))
+	}
+	// Cuts fall between words
+	if inner := strings.Trim(got.Text, "…"); strings.HasPrefix(inner, "ords") || strings.HasSuffix(inner, "wor") {
+		t.Errorf("snippet %q cuts a word", got.Text)
+	}
+}
@@ -0,0 +1,128 @@
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NameScore rates how well what a user has typed so far matches a name, for
+// type-ahead. It returns 0 for no match and at most 1 for an exact one.
+// Matches are tried from strictest to loosest: the whole name, the start of
+// the name, the start of later words, the transliterated consonant Key (so
+// either script finds the other), and finally a prefix with typos.
+func NameScore(query, name string) float64 {
+	qWords := words(query)
+	nWords := words(name)
+	if len(qWords) == 0 || len(nWords) == 0 {
+		return 0
+	}
+	q := strings.Join(qWords, " ")
+	n := strings.Join(nWords, " ")
+
+	switch {
+	case q == n:
+		return 1
+	case strings.HasPrefix(n, q):
+		return 0.9
+	case wordPrefixes(qWords, nWords):
+		return 0.8
+	}
+
+	// Later words count too, so "ቡና" finds "ኢትዮጵያ ቡና". Very short
+	// queries reduce to a letter or two and would match too much.
+	qKey := Key(query)
+	if qKey != "" && utf8.RuneCountInString(q) >= 3 {
+		for i := range nWords {
+			if strings.HasPrefix(Key(strings.Join(nWords[i:], " ")), qKey) {
+				if i == 0 {
+					return 0.7
+				}
+				return 0.6
+			}
+		}
+	}
+
+	qLatin := strings.ToLower(Transliterate(q))
+	length := utf8.RuneCountInString(qLatin)
+	allowed := 0
+	switch {
+	case length >= 7:
+		allowed = 2
+	case length >= 4:
+		allowed = 1
+	}
+	if allowed == 0 {
+		return 0
+	}
+
+	best := allowed + 1
+	for i := range nWords {
+		candidate := []rune(strings.ToLower(Transliterate(strings.Join(nWords[i:], " "))))
+		// Compare against name prefixes a little shorter or longer than
+		// the query, as a typo can add or drop a letter
+		for l := length - allowed; l <= length+allowed; l++ {
+			if l < 1 || l > len(candidate) {
+				continue
+			}
+			if d := distance([]rune(qLatin), candidate[:l]); d < best {
+				best = d
+			}
+		}
+	}
+	if best > allowed {
+		return 0
+	}
+	return 0.5 - 0.1*float64(best)
+}
+
+func words(text string) []string {
+	tokens := Tokenize(text)
+	out := make([]string, len(tokens))
+	for i, token := range tokens {
+		out[i] = token.Term
+	}
+	return out
+}
+
+// wordPrefixes reports whether each query word starts a name word, in order,
+// e.g. "man u" against "manchester united"
+func wordPrefixes(query, name []string) bool {
+	j := 0
+	for _, q := range query {
+		for j < len(name) && !strings.HasPrefix(name[j], q) {
+			j++
+		}
+		if j == len(name) {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// distance is the number of single-letter insertions, deletions,
+// substitutions and adjacent swaps that turn a into b
+func distance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
@@ -0,0 +1,67 @@
+package search
+
+import "testing"
+
+func TestNameScore(t *testing.T) {
+	tests := []struct {
+		query, name string
+		want        float64
+	}{
+		{"Saint George", "saint george", 1},
+		{"sain", "Saint George", 0.9},
+		{"man u", "Manchester United", 0.8},
+		{"Ethiopia Bunna", "ኢትዮጵያ ቡና", 0.7},
+		{"ቡና", "ኢትዮጵያ ቡና", 0.8},
+		{"Bunna", "ኢትዮጵያ ቡና", 0.6},
+		{"asrenal", "Arsenal", 0.4}, // one swap
+		{"mancehster", "Manchester City", 0.4},
+		{"mancehstr", "Manchester City", 0.3}, // a swap and a dropped letter
+		{"arx", "Arsenal", 0},                 // too short for typos
+		{"Bu", "ኢትዮጵያ ቡና", 0},                 // too short for the key
+		{"chelsea", "Arsenal", 0},
+		{"", "Arsenal", 0},
+		{"Arsenal", "", 0},
+	}
+	for _, tt := range tests {
+		if got := NameScore(tt.query, tt.name); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("NameScore(%q, %q) = %v; want %v", tt.query, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"buna", "buna", 0},
+		{"buna", "bunaa", 1},
+		{"buna", "bona", 1},
+		{"buna", "bnua", 1},
+		{"", "abc", 3},
+		{"kidus", "qiddus", 2},
+	}
+	for _, tt := range tests {
+		if got := distance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("distance(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestWordPrefixes(t *testing.T) {
+	name := []string{"manchester", "united", "fc"}
+	tests := []struct {
+		query []string
+		want  bool
+	}{
+		{[]string{"man", "u"}, true},
+		{[]string{"man", "fc"}, true},
+		{[]string{"u", "man"}, false},
+		{[]string{"city"}, false},
+	}
+	for _, tt := range tests {
+		if got := wordPrefixes(tt.query, name); got != tt.want {
+			t.Errorf("wordPrefixes(%q) = %v; want %v", tt.query, got, tt.want)
+		}
+	}
+}
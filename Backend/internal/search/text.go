@@ -0,0 +1,123 @@
+// Package search tokenises and matches multilingual text. It handles English
+// and Oromo (Latin script) and Amharic (Ge'ez script), which generic
+// full-text engines either stem with English rules or do not split at all.
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is a normalised word and where it sits in the original text, in runes
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// geezHomophones maps the first letter of each Ge'ez row that is written
+// interchangeably in modern Amharic to the row it is normalised to. Rows are
+// eight code points long, one per vowel order.
+var geezHomophones = map[rune]rune{
+	'ሐ': 'ሀ', // ḥa
+	'ኀ': 'ሀ', // ḫa
+	'ሠ': 'ሰ', // śa
+	'ዐ': 'አ', // ʿa
+	'ፀ': 'ጸ', // ṣ́a
+}
+
+// normalizeRune lowercases Latin letters, unifies apostrophes and folds Ge'ez
+// homophones. It maps one rune to one rune, so token offsets still point into
+// the original text.
+func normalizeRune(r rune) rune {
+	switch r {
+	case '’', 'ʼ', '‘', '`':
+		return '\''
+	}
+	for first, canonical := range geezHomophones {
+		if r >= first && r < first+8 {
+			return canonical + (r - first)
+		}
+	}
+	return unicode.ToLower(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
+
+// Tokenize splits text into normalised terms. Ge'ez punctuation (። ፣ ፤ ...)
+// separates words like Latin punctuation does. An apostrophe inside a word is
+// kept, as Oromo uses it as a letter (hudhaa), e.g. "har'a".
+func Tokenize(text string) []Token {
+	runes := []rune(text)
+	var tokens []Token
+
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		// Drop apostrophes at the edges, which are quotes rather than letters
+		for start < end && normalizeRune(runes[start]) == '\'' {
+			start++
+		}
+		for end > start && normalizeRune(runes[end-1]) == '\'' {
+			end--
+		}
+		if end > start {
+			var b strings.Builder
+			for _, r := range runes[start:end] {
+				b.WriteRune(normalizeRune(r))
+			}
+			tokens = append(tokens, Token{Term: stem(b.String()), Start: start, End: end})
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		n := normalizeRune(r)
+		if isWordRune(n) || (n == '\'' && start >= 0) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(runes))
+	return tokens
+}
+
+// stem strips an English possessive "'s" and a plural "s" from Latin words
+// of four or more letters. It is deliberately light: prefix matching already
+// covers most inflections, and English suffix rules would mangle Oromo words.
+func stem(term string) string {
+	for _, r := range term {
+		if r > unicode.MaxASCII {
+			return term
+		}
+	}
+	if trimmed := strings.TrimSuffix(term, "'s"); trimmed != term && trimmed != "" {
+		return trimmed
+	}
+	if len(term) < 4 || !strings.HasSuffix(term, "s") || strings.HasSuffix(term, "ss") {
+		return term
+	}
+	return strings.TrimSuffix(term, "s")
+}
+
+// Terms returns the distinct terms of all texts, for indexing
+func Terms(texts ...string) []string {
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, text := range texts {
+		for _, token := range Tokenize(text) {
+			if !seen[token.Term] {
+				seen[token.Term] = true
+				terms = append(terms, token.Term)
+			}
+		}
+	}
+	return terms
+}
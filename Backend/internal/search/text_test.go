@@ -0,0 +1,62 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+	}{
+		{"Saint George wins the derby", []string{"saint", "george", "win", "the", "derby"}},
+		{"Arsenal's keeper", []string{"arsenal", "keeper"}},
+		{"Class of 2025", []string{"class", "of", "2025"}},
+		{"Har'a Buna, 'Injifannoo'", []string{"har'a", "buna", "injifannoo"}},
+		{"ኢትዮጵያ ቡና።ቅዱስ ጊዮርጊስ፣ድል", []string{"ኢትዮጵያ", "ቡና", "ቅዱስ", "ጊዮርጊስ", "ድል"}},
+		// ሐ, ሠ and ዐ rows fold to ሀ, ሰ and አ
+		{"ሐዋሳ ሠራ ዐዲስ", []string{"ሀዋሳ", "ሰራ", "አዲስ"}},
+		{"  ...  ", nil},
+	}
+	for _, tt := range tests {
+		var terms []string
+		for _, token := range Tokenize(tt.text) {
+			terms = append(terms, token.Term)
+		}
+		if !slices.Equal(terms, tt.terms) {
+			t.Errorf("Tokenize(%q) = %q; want %q", tt.text, terms, tt.terms)
+		}
+	}
+}
+
+// Offsets count runes of the original text, quotes and all
+func TestTokenizeOffsets(t *testing.T) {
+	text := "‘ቡና’ won"
+	want := []Token{{Term: "ቡና", Start: 1, End: 3}, {Term: "won", Start: 5, End: 8}}
+	if got := Tokenize(text); !slices.Equal(got, want) {
+		t.Errorf("Tokenize(%q) = %+v; want %+v", text, got, want)
+	}
+}
+
+func TestStem(t *testing.T) {
+	for term, want := range map[string]string{
+		"goals":    "goal",
+		"club's":   "club",
+		"pass":     "pass",
+		"bus":      "bus",
+		"gooftaas": "gooftaa",
+		"ቡናዎች":     "ቡናዎች",
+	} {
+		if got := stem(term); got != want {
+			t.Errorf("stem(%q) = %q; want %q", term, got, want)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("Buna wins", "Buna's win at home")
+	if want := []string{"buna", "win", "at", "home"}; !slices.Equal(got, want) {
+		t.Errorf("Terms = %q; want %q", got, want)
+	}
+}
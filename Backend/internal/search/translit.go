@@ -0,0 +1,73 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// geezRows maps the first code point of each Ge'ez consonant row to its
+// Latin consonant. Each row holds the consonant with seven vowels, in order.
+var geezRows = map[rune]string{
+	'ሀ': "h", 'ለ': "l", 'ሐ': "h", 'መ': "m", 'ሠ': "s", 'ረ': "r", 'ሰ': "s", 'ሸ': "sh",
+	'ቀ': "q", 'ቐ': "q", 'በ': "b", 'ቨ': "v", 'ተ': "t", 'ቸ': "ch", 'ኀ': "h", 'ነ': "n",
+	'ኘ': "ny", 'አ': "", 'ከ': "k", 'ኸ': "h", 'ወ': "w", 'ዐ': "", 'ዘ': "z", 'ዠ': "zh",
+	'የ': "y", 'ደ': "d", 'ጀ': "j", 'ገ': "g", 'ጠ': "t", 'ጨ': "ch", 'ጰ': "p", 'ጸ': "ts",
+	'ፀ': "ts", 'ፈ': "f", 'ፐ': "p",
+}
+
+// geezVowels are the vowels of the seven orders plus the labialised eighth.
+// The sixth order is usually silent in names, e.g. ማንቸስተር "manchester".
+var geezVowels = [8]string{"e", "u", "i", "a", "e", "", "o", "wa"}
+
+// Transliterate writes Ge'ez text in Latin letters. Other scripts pass
+// through unchanged. The romanisation is a simple one that sounds like the
+// spellings fans type, not a scholarly standard.
+func Transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r >= 0x1200 && r < 0x1360 {
+			row := r - (r-0x1200)%8
+			if consonant, ok := geezRows[row]; ok {
+				b.WriteString(consonant + geezVowels[r-row])
+				continue
+			}
+		}
+		if r >= 0x1360 && r <= 0x1368 {
+			r = ' ' // Ethiopic punctuation
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// latinFolds merge spellings that romanisations of the same name disagree
+// on. "ph" folds to p rather than f, as in Ethiopia (ኢትዮጵያ).
+var latinFolds = strings.NewReplacer(
+	"ph", "p", "th", "t", "ck", "k", "ch", "c", "sh", "s", "zh", "z", "ts", "s",
+	"q", "k", "c", "k", "v", "b", "x", "ks",
+)
+
+// Key reduces a name to its consonant skeleton after transliteration, so
+// that "ኢትዮጵያ ቡና", "Ethiopia Bunna" and "Itoophiyaa Buna" all become
+// "tpbn". Vowels and the semivowels y and w are left out, as they are what
+// romanisations of Amharic and Oromo disagree on most.
+func Key(text string) string {
+	latin := strings.ToLower(Transliterate(text))
+	var letters strings.Builder
+	for _, r := range latin {
+		if unicode.IsLetter(r) && r <= unicode.MaxASCII {
+			letters.WriteRune(r)
+		}
+	}
+
+	var key strings.Builder
+	var last rune
+	for _, r := range latinFolds.Replace(letters.String()) {
+		if strings.ContainsRune("aeiouyw", r) || r == last {
+			continue
+		}
+		key.WriteRune(r)
+		last = r
+	}
+	return key.String()
+}
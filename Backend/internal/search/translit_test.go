@@ -0,0 +1,37 @@
+package search
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	for text, want := range map[string]string{
+		"ቡና":        "buna",
+		"ማንቸስተር":    "manchester",
+		"ኢትዮጵያ":     "ityopya",
+		"ቅዱስ፣ጊዮርጊስ": "qdus giyorgis",
+		"Arsenal":   "Arsenal",
+		"ጎል 2":      "gol 2",
+	} {
+		if got := Transliterate(text); got != want {
+			t.Errorf("Transliterate(%q) = %q; want %q", text, got, want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{[]string{"ኢትዮጵያ ቡና", "Ethiopia Bunna", "Itoophiyaa Buna"}, "tpbn"},
+		{[]string{"ቅዱስ ጊዮርጊስ", "Kidus Giorgis", "Qidus Giyorgis"}, "kdsgrgs"},
+		{[]string{"ማንቸስተር", "Manchester", "Manchestar"}, "mncstr"},
+		{[]string{"", "ዐ", "Aa"}, ""},
+	}
+	for _, tt := range tests {
+		for _, name := range tt.names {
+			if got := Key(name); got != tt.want {
+				t.Errorf("Key(%q) = %q; want %q", name, got, tt.want)
+			}
+		}
+	}
+}
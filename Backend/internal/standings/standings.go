@@ -0,0 +1,202 @@
+// Package standings builds league tables from match results. Compute is a
+// pure function of the matches and the league's tiebreak rules, so the same
+// results always produce the same table.
+package standings
+
+import (
+	"cmp"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+const (
+	PointsWin  = 3
+	PointsDraw = 1
+	// formLength is how many recent results a row's form shows
+	formLength = 5
+)
+
+// Tiebreak rules, applied in a league's order to clubs level on points
+const (
+	GoalDifference = "goal_difference"
+	GoalsFor       = "goals_for"
+	Wins           = "wins"
+	AwayGoalsFor   = "away_goals_for"
+	// HeadToHead ranks tied clubs by a table of the matches between them:
+	// points, then goal difference, then goals scored
+	HeadToHead = "head_to_head"
+)
+
+var Tiebreakers = []string{GoalDifference, GoalsFor, Wins, AwayGoalsFor, HeadToHead}
+
+// DefaultTiebreakers apply to leagues that have not set their own
+var DefaultTiebreakers = []string{GoalDifference, GoalsFor, HeadToHead}
+
+// record is a club's tally, with what tiebreakers need beyond the row
+type record struct {
+	models.StandingRow
+	AwayGoalsFor int
+}
+
+func (r *record) add(scored, conceded int, away bool) {
+	r.Played++
+	r.GoalsFor += scored
+	r.GoalsAgainst += conceded
+	r.GoalDifference = r.GoalsFor - r.GoalsAgainst
+	if away {
+		r.AwayGoalsFor += scored
+	}
+
+	result := "D"
+	switch {
+	case scored > conceded:
+		r.Won++
+		r.Points += PointsWin
+		result = "W"
+	case scored < conceded:
+		r.Lost++
+		result = "L"
+	default:
+		r.Drawn++
+		r.Points += PointsDraw
+	}
+	if len(r.Form) < formLength {
+		r.Form = append(r.Form, result)
+	}
+}
+
+// Compute returns the table for one league season. The season's clubs and
+// every club with a match in it get a row; only finished matches with a
+// score count. Clubs still level after every tiebreaker are ordered by ID so
+// the table is stable.
+func Compute(matches []models.Match, clubIDs []bson.ObjectID, tiebreakers []string) []models.StandingRow {
+	if len(tiebreakers) == 0 {
+		tiebreakers = DefaultTiebreakers
+	}
+
+	records := map[bson.ObjectID]*record{}
+	tally := func(id bson.ObjectID) *record {
+		if records[id] == nil {
+			records[id] = &record{StandingRow: models.StandingRow{ClubID: id, Form: []string{}}}
+		}
+		return records[id]
+	}
+
+	results := finished(matches)
+	for _, id := range clubIDs {
+		tally(id)
+	}
+	for _, match := range matches {
+		tally(match.HomeClubID)
+		tally(match.AwayClubID)
+	}
+	// Newest first, so form lists the latest result first
+	for _, match := range results {
+		tally(match.HomeClubID).add(match.Score.Home, match.Score.Away, false)
+		tally(match.AwayClubID).add(match.Score.Away, match.Score.Home, true)
+	}
+
+	clubs := make([]bson.ObjectID, 0, len(records))
+	for id := range records {
+		clubs = append(clubs, id)
+	}
+	order(clubs, records, results, append([]string{"points"}, tiebreakers...))
+
+	rows := make([]models.StandingRow, len(clubs))
+	for i, id := range clubs {
+		rows[i] = records[id].StandingRow
+		rows[i].Position = i + 1
+	}
+	return rows
+}
+
+// finished returns the matches that count, newest first
+func finished(matches []models.Match) []models.Match {
+	var results []models.Match
+	for _, match := range matches {
+		if match.Status == models.MatchFinished && match.Score != nil {
+			results = append(results, match)
+		}
+	}
+	slices.SortStableFunc(results, func(a, b models.Match) int {
+		return b.KickoffAt.Compare(a.KickoffAt)
+	})
+	return results
+}
+
+// order sorts clubs by the first criterion, then orders each group still
+// level by the remaining ones
+func order(clubs []bson.ObjectID, records map[bson.ObjectID]*record, results []models.Match, criteria []string) {
+	if len(clubs) < 2 {
+		return
+	}
+	if len(criteria) == 0 {
+		slices.SortFunc(clubs, func(a, b bson.ObjectID) int { return cmp.Compare(a.Hex(), b.Hex()) })
+		return
+	}
+
+	var values map[bson.ObjectID][3]int
+	if criteria[0] == HeadToHead {
+		values = headToHead(clubs, results)
+	} else {
+		values = make(map[bson.ObjectID][3]int, len(clubs))
+		for _, id := range clubs {
+			values[id] = [3]int{value(records[id], criteria[0])}
+		}
+	}
+
+	slices.SortStableFunc(clubs, func(a, b bson.ObjectID) int {
+		vb, va := values[b], values[a]
+		return slices.Compare(vb[:], va[:])
+	})
+	for start := 0; start < len(clubs); {
+		end := start + 1
+		for end < len(clubs) && values[clubs[end]] == values[clubs[start]] {
+			end++
+		}
+		order(clubs[start:end], records, results, criteria[1:])
+		start = end
+	}
+}
+
+func value(r *record, criterion string) int {
+	switch criterion {
+	case "points":
+		return r.Points
+	case GoalDifference:
+		return r.GoalDifference
+	case GoalsFor:
+		return r.GoalsFor
+	case Wins:
+		return r.Won
+	case AwayGoalsFor:
+		return r.AwayGoalsFor
+	}
+	return 0
+}
+
+// headToHead tallies points, goal difference and goals scored in the
+// matches between the given clubs only
+func headToHead(clubs []bson.ObjectID, results []models.Match) map[bson.ObjectID][3]int {
+	tied := make(map[bson.ObjectID]*record, len(clubs))
+	for _, id := range clubs {
+		tied[id] = &record{}
+	}
+	for _, match := range results {
+		home, away := tied[match.HomeClubID], tied[match.AwayClubID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.add(match.Score.Home, match.Score.Away, false)
+		away.add(match.Score.Away, match.Score.Home, true)
+	}
+
+	values := make(map[bson.ObjectID][3]int, len(clubs))
+	for id, r := range tied {
+		values[id] = [3]int{r.Points, r.GoalDifference, r.GoalsFor}
+	}
+	return values
+}
@@ -0,0 +1,171 @@
+package standings
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"fanzone/internal/models"
+)
+
+// Clubs A to D, with IDs in that order so a final tie lists them A to D
+var (
+	clubs = []bson.ObjectID{bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID(), bson.NewObjectID()}
+	a, b  = clubs[0], clubs[1]
+	c, d  = clubs[2], clubs[3]
+	kick  = time.Date(2025, 3, 1, 15, 0, 0, 0, time.UTC)
+)
+
+// result is a finished match played day days after the first one
+func result(home, away bson.ObjectID, homeGoals, awayGoals, day int) models.Match {
+	return models.Match{
+		HomeClubID: home,
+		AwayClubID: away,
+		KickoffAt:  kick.AddDate(0, 0, day),
+		Status:     models.MatchFinished,
+		Score:      &models.Score{Home: homeGoals, Away: awayGoals},
+	}
+}
+
+// names lists a table's clubs as letters, e.g. "BACD"
+func names(rows []models.StandingRow) string {
+	var out strings.Builder
+	for _, row := range rows {
+		out.WriteByte("ABCD"[slices.Index(clubs, row.ClubID)])
+	}
+	return out.String()
+}
+
+func TestComputeTally(t *testing.T) {
+	matches := []models.Match{
+		result(a, b, 2, 0, 0),
+		result(b, a, 1, 1, 1),
+		result(c, a, 3, 1, 2),
+		{HomeClubID: a, AwayClubID: c, KickoffAt: kick.AddDate(0, 0, 3), Status: models.MatchScheduled},
+		{HomeClubID: b, AwayClubID: c, KickoffAt: kick.AddDate(0, 0, 4), Status: models.MatchFinished}, // no score
+	}
+	rows := Compute(matches, clubs, nil)
+	if got := names(rows); got != "ACBD" {
+		t.Fatalf("order = %s; want ACBD", got)
+	}
+
+	want := models.StandingRow{
+		Position: 1, ClubID: a, Played: 3, Won: 1, Drawn: 1, Lost: 1,
+		GoalsFor: 4, GoalsAgainst: 4, GoalDifference: 0, Points: 4,
+		Form: []string{"L", "D", "W"},
+	}
+	if got := rows[0]; got.Position != want.Position || got.Points != want.Points || got.Played != want.Played ||
+		got.Won != want.Won || got.Drawn != want.Drawn || got.Lost != want.Lost ||
+		got.GoalsFor != want.GoalsFor || got.GoalsAgainst != want.GoalsAgainst ||
+		got.GoalDifference != want.GoalDifference || !slices.Equal(got.Form, want.Form) {
+		t.Errorf("row of A = %+v; want %+v", got, want)
+	}
+	// A season club without results still gets an empty row
+	if got := rows[3]; got.ClubID != d || got.Played != 0 || got.Position != 4 || got.Form == nil {
+		t.Errorf("row of D = %+v; want an empty fourth row", got)
+	}
+}
+
+func TestComputeFormKeepsTheLatestResults(t *testing.T) {
+	var matches []models.Match
+	for day := range 7 {
+		matches = append(matches, result(a, b, day%2, 0, day)) // D W D W D W D
+	}
+	rows := Compute(matches, nil, nil)
+	if got, want := rows[0].Form, []string{"D", "W", "D", "W", "D"}; !slices.Equal(got, want) {
+		t.Errorf("form = %v; want %v", got, want)
+	}
+	if rows[0].Played != 7 {
+		t.Errorf("played = %d; want every result counted", rows[0].Played)
+	}
+}
+
+func TestComputeTiebreakers(t *testing.T) {
+	tests := []struct {
+		name        string
+		matches     []models.Match
+		tiebreakers []string
+		want        string
+	}{
+		{
+			name:    "no results fall back to ID",
+			matches: nil,
+			want:    "ABCD",
+		},
+		{
+			name:        "goal difference",
+			matches:     []models.Match{result(a, c, 2, 0, 0), result(b, d, 1, 0, 0)},
+			tiebreakers: []string{GoalDifference},
+			want:        "ABDC",
+		},
+		{
+			name:        "goals for when goal difference is level",
+			matches:     []models.Match{result(a, c, 1, 0, 0), result(b, d, 3, 2, 0)},
+			tiebreakers: []string{GoalDifference, GoalsFor},
+			want:        "BADC",
+		},
+		{
+			name: "wins",
+			matches: []models.Match{
+				result(b, c, 1, 0, 0), result(d, b, 1, 0, 1),
+				result(a, c, 0, 0, 2), result(a, d, 0, 0, 3), result(a, c, 0, 0, 4),
+			},
+			tiebreakers: []string{Wins},
+			want:        "DBAC",
+		},
+		{
+			name: "level on goal difference without wins falls back to ID",
+			matches: []models.Match{
+				result(b, c, 1, 0, 0), result(d, b, 1, 0, 1),
+				result(a, c, 0, 0, 2), result(a, d, 0, 0, 3), result(a, c, 0, 0, 4),
+			},
+			tiebreakers: []string{GoalDifference},
+			want:        "DABC",
+		},
+		{
+			name:        "away goals",
+			matches:     []models.Match{result(a, c, 2, 1, 0), result(d, b, 1, 2, 0)},
+			tiebreakers: []string{GoalDifference, GoalsFor, AwayGoalsFor},
+			want:        "BACD",
+		},
+		{
+			// A's win over C would top goal difference; between A, B and D
+			// only B beat A and D beat B
+			name: "head to head counts only the matches between tied clubs",
+			matches: []models.Match{
+				result(a, c, 3, 0, 0), result(b, a, 1, 0, 1), result(d, b, 1, 0, 2),
+			},
+			tiebreakers: []string{HeadToHead},
+			want:        "DBAC",
+		},
+		{
+			name: "defaults end with head to head",
+			matches: []models.Match{
+				result(b, a, 1, 0, 0), result(a, c, 1, 0, 1), result(d, b, 1, 0, 2),
+			},
+			tiebreakers: nil,
+			want:        "DBAC",
+		},
+	}
+	for _, tt := range tests {
+		rows := Compute(tt.matches, clubs, tt.tiebreakers)
+		if got := names(rows); got != tt.want {
+			t.Errorf("%s: order = %s; want %s", tt.name, got, tt.want)
+		}
+		for i, row := range rows {
+			if row.Position != i+1 {
+				t.Errorf("%s: row %d has position %d", tt.name, i, row.Position)
+			}
+		}
+
+		// The table does not depend on the order of the matches
+		reversed := slices.Clone(tt.matches)
+		slices.Reverse(reversed)
+		if got := names(Compute(reversed, clubs, tt.tiebreakers)); got != tt.want {
+			t.Errorf("%s: order of reversed matches = %s; want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package worker
 import (
 	"fmt"
 	"log"
+	"sync"
 	"time"
 )
 
@@ -12,19 +13,35 @@ type Task struct {
 	Payload interface{}
 }
 
+// HandlerFunc processes a task of a registered type
+type HandlerFunc func(t Task)
+
 // Worker handles background tasks
 type Worker struct {
 	TaskQueue chan Task
 	Quit      chan bool
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	stopOnce sync.Once
 }
 
 func NewWorker(bufferSize int) *Worker {
 	return &Worker{
 		TaskQueue: make(chan Task, bufferSize),
 		Quit:      make(chan bool),
+		handlers:  make(map[string]HandlerFunc),
 	}
 }
 
+// Register installs a handler for a task type, for jobs that need
+// dependencies (such as the repository) the worker package does not own.
+func (w *Worker) Register(taskType string, fn HandlerFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[taskType] = fn
+}
+
 func (w *Worker) Start(numWorkers int) {
 	for i := 0; i < numWorkers; i++ {
 		go func(workerID int) {
@@ -33,7 +50,7 @@ func (w *Worker) Start(numWorkers int) {
 				select {
 				case task := <-w.TaskQueue:
 					fmt.Printf("Worker %d processing task: %v\n", workerID, task.Type)
-					processTask(task)
+					w.process(task)
 				case <-w.Quit:
 					fmt.Printf("Worker %d stopping\n", workerID)
 					return
@@ -43,18 +60,53 @@ func (w *Worker) Start(numWorkers int) {
 	}
 }
 
-func (w *Worker) Stop() {
+// Every queues the task immediately and then once per interval until the
+// worker is stopped.
+func (w *Worker) Every(interval time.Duration, t Task) {
 	go func() {
-		w.Quit <- true
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case w.TaskQueue <- t:
+			case <-w.Quit:
+				return
+			}
+
+			select {
+			case <-ticker.C:
+			case <-w.Quit:
+				return
+			}
+		}
 	}()
 }
 
+func (w *Worker) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.Quit)
+	})
+}
+
 func (w *Worker) AddTask(t Task) {
 	// Non-blocking send (optional, or blocking if valid)
 	// For now, let's block to ensure it's queued
 	w.TaskQueue <- t
 }
 
+func (w *Worker) process(t Task) {
+	w.mu.RLock()
+	fn, ok := w.handlers[t.Type]
+	w.mu.RUnlock()
+
+	if ok {
+		fn(t)
+		return
+	}
+	processTask(t)
+}
+
 func processTask(t Task) {
 	// Simulate work
 	time.Sleep(2 * time.Second)
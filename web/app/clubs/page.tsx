@@ -66,7 +66,7 @@ export default function ClubsManagementPage() {
         }
         try {
             if (editingClub) {
-                await updateClub({ id: editingClub.id, ...formData }).unwrap();
+                await updateClub({ id: editingClub.id, version: editingClub.version, ...formData }).unwrap();
                 toast.success('Club updated successfully');
             } else {
                 await addClub(formData).unwrap();
@@ -20,7 +20,7 @@ export default function EditContentPage() {
 
     const handleSubmit = async (data: any) => {
         try {
-            await updateContent({ id, ...data }).unwrap();
+            await updateContent({ id, version: content?.version, ...data }).unwrap();
             toast.success('Article updated successfully!');
             setTimeout(() => router.push('/content'), 1500);
         } catch (error) {
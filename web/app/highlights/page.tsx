@@ -179,7 +179,7 @@ export default function HighlightsManagementPage() {
                     onSubmit={async (data: Omit<Highlight, 'id'>) => {
                         try {
                             if (editingHighlight) {
-                                await updateHighlight({ id: editingHighlight.id, ...data }).unwrap();
+                                await updateHighlight({ id: editingHighlight.id, version: editingHighlight.version, ...data }).unwrap();
                                 toast.success('Highlight updated successfully');
                             } else {
                                 await createHighlight(data).unwrap();
@@ -57,7 +57,7 @@ export default function LeaguesManagementPage() {
         e.preventDefault();
         try {
             if (editingLeague) {
-                await updateLeague({ id: editingLeague.id, ...formData }).unwrap();
+                await updateLeague({ id: editingLeague.id, version: editingLeague.version, ...formData }).unwrap();
                 toast.success('League updated successfully');
             } else {
                 await addLeague(formData).unwrap();
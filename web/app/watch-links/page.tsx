@@ -185,7 +185,7 @@ export default function WatchLinksManagementPage() {
                     onSubmit={async (data: Omit<WatchLink, 'id'>) => {
                         try {
                             if (editingLink) {
-                                await updateWatchLink({ id: editingLink.id, ...data }).unwrap();
+                                await updateWatchLink({ id: editingLink.id, version: editingLink.version, ...data }).unwrap();
                                 toast.success('Watch link updated successfully');
                             } else {
                                 await createWatchLink(data).unwrap();
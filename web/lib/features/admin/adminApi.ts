@@ -21,7 +21,13 @@ export interface Content {
     image_url: string;
     category: string;
     club_id: string;
+    league_ids?: string[];
+    match_id?: string;
+    player_ids?: string[];
+    breaking?: boolean;
+    pinned_until?: string;
     created_at: string;
+    version: number;
 }
 
 interface AdminStats {
@@ -39,6 +45,12 @@ export interface Highlight {
     match_title: string;
     youtube_url: string;
     club_ids: string[];
+    league_ids?: string[];
+    match_id?: string;
+    player_ids?: string[];
+    breaking?: boolean;
+    pinned_until?: string;
+    version: number;
 }
 
 export interface WatchLink {
@@ -47,6 +59,15 @@ export interface WatchLink {
     url: string;
     type: string;
     logo_url: string;
+    league_ids?: string[];
+    club_ids?: string[];
+    match_ids?: string[];
+    countries?: string[];
+    access?: 'free' | 'paid';
+    commentary_languages?: string[];
+    available_from?: string;
+    available_until?: string;
+    version: number;
 }
 
 export interface UserInfo {
@@ -92,10 +113,11 @@ export const adminApi = apiSlice.injectEndpoints({
             invalidatesTags: ['Content'],
         }),
         updateContent: builder.mutation({
-            query: ({ id, ...data }: Partial<Content> & { id: string }) => ({
+            query: ({ id, version, ...data }: Partial<Content> & { id: string; version: number }) => ({
                 url: `/admin/content/${id}`,
                 method: 'PUT',
                 body: data,
+                headers: { 'If-Match': `"${version}"` },
             }),
             invalidatesTags: ['Content'],
         }),
@@ -107,7 +129,7 @@ export const adminApi = apiSlice.injectEndpoints({
             invalidatesTags: ['Content'],
         }),
         createHighlight: builder.mutation({
-            query: (data: Omit<Highlight, 'id'>) => ({
+            query: (data: Omit<Highlight, 'id' | 'version'>) => ({
                 url: '/admin/highlights',
                 method: 'POST',
                 body: data,
@@ -115,10 +137,11 @@ export const adminApi = apiSlice.injectEndpoints({
             invalidatesTags: ['Highlights'],
         }),
         updateHighlight: builder.mutation({
-            query: ({ id, ...data }: Partial<Highlight> & { id: string }) => ({
+            query: ({ id, version, ...data }: Partial<Highlight> & { id: string; version: number }) => ({
                 url: `/admin/highlights/${id}`,
                 method: 'PUT',
                 body: data,
+                headers: { 'If-Match': `"${version}"` },
             }),
             invalidatesTags: ['Highlights'],
         }),
@@ -130,7 +153,7 @@ export const adminApi = apiSlice.injectEndpoints({
             invalidatesTags: ['Highlights'],
         }),
         createWatchLink: builder.mutation({
-            query: (data: Omit<WatchLink, 'id'>) => ({
+            query: (data: Omit<WatchLink, 'id' | 'version'>) => ({
                 url: '/admin/watch-links',
                 method: 'POST',
                 body: data,
@@ -138,10 +161,11 @@ export const adminApi = apiSlice.injectEndpoints({
             invalidatesTags: ['WatchLinks'],
         }),
         updateWatchLink: builder.mutation({
-            query: ({ id, ...data }: Partial<WatchLink> & { id: string }) => ({
+            query: ({ id, version, ...data }: Partial<WatchLink> & { id: string; version: number }) => ({
                 url: `/admin/watch-links/${id}`,
                 method: 'PUT',
                 body: data,
+                headers: { 'If-Match': `"${version}"` },
             }),
             invalidatesTags: ['WatchLinks'],
         }),
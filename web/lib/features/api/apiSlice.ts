@@ -9,6 +9,8 @@ const mutex = new Mutex();
 
 const baseQuery = fetchBaseQuery({
   baseUrl: process.env.NEXT_PUBLIC_API_BASE_URL || '/api',
+  // Public GETs are cacheable; always revalidate so admin edits show up at once
+  cache: 'no-cache',
   prepareHeaders: (headers, { getState }) => {
     const token = (getState() as RootState).auth.token || localStorage.getItem('token');
 
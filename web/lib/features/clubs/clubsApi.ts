@@ -1,10 +1,24 @@
 import { apiSlice } from '../api/apiSlice';
 
+export interface MultilingualText {
+    en: string;
+    am: string;
+    om: string;
+}
+
 export interface Club {
     id: string;
     name: string;
+    names?: MultilingualText;
+    short_names?: MultilingualText;
+    aliases?: string[];
     logo_url: string;
     league_id: string;
+    colors?: string[];
+    founded_year?: number;
+    stadium?: MultilingualText;
+    website?: string;
+    version: number;
 }
 
 export const clubsApi = apiSlice.injectEndpoints({
@@ -20,7 +34,7 @@ export const clubsApi = apiSlice.injectEndpoints({
             query: (id) => `/clubs/${id}`,
             providesTags: (result, error, id) => [{ type: 'Clubs', id }],
         }),
-        addClub: builder.mutation<Club, Omit<Club, 'id'>>({
+        addClub: builder.mutation<Club, Omit<Club, 'id' | 'version'>>({
             query: (newClub) => ({
                 url: '/admin/clubs',
                 method: 'POST',
@@ -28,11 +42,12 @@ export const clubsApi = apiSlice.injectEndpoints({
             }),
             invalidatesTags: [{ type: 'Clubs', id: 'LIST' }],
         }),
-        updateClub: builder.mutation<void, Partial<Club> & { id: string }>({
-            query: ({ id, ...patch }) => ({
+        updateClub: builder.mutation<void, Partial<Club> & { id: string; version: number }>({
+            query: ({ id, version, ...patch }) => ({
                 url: `/admin/clubs/${id}`,
                 method: 'PUT',
                 body: patch,
+                headers: { 'If-Match': `"${version}"` },
             }),
             invalidatesTags: (result, error, { id }) => [
                 { type: 'Clubs', id },
@@ -1,10 +1,20 @@
 import { apiSlice } from '../api/apiSlice';
+import type { MultilingualText } from '../clubs/clubsApi';
 
 export interface League {
     id: string;
     name: string;
+    names?: MultilingualText;
+    short_names?: MultilingualText;
+    aliases?: string[];
     logo_url: string;
     country: string;
+    country_names?: MultilingualText;
+    colors?: string[];
+    founded_year?: number;
+    website?: string;
+    tiebreakers?: string[];
+    version: number;
 }
 
 export const leaguesApi = apiSlice.injectEndpoints({
@@ -20,7 +30,7 @@ export const leaguesApi = apiSlice.injectEndpoints({
             query: (id) => `/leagues/${id}`,
             providesTags: (result, error, id) => [{ type: 'Leagues', id }],
         }),
-        addLeague: builder.mutation<League, Omit<League, 'id'>>({
+        addLeague: builder.mutation<League, Omit<League, 'id' | 'version'>>({
             query: (newLeague) => ({
                 url: '/admin/leagues',
                 method: 'POST',
@@ -28,11 +38,12 @@ export const leaguesApi = apiSlice.injectEndpoints({
             }),
             invalidatesTags: [{ type: 'Leagues', id: 'LIST' }],
         }),
-        updateLeague: builder.mutation<void, Partial<League> & { id: string }>({
-            query: ({ id, ...patch }) => ({
+        updateLeague: builder.mutation<void, Partial<League> & { id: string; version: number }>({
+            query: ({ id, version, ...patch }) => ({
                 url: `/admin/leagues/${id}`,
                 method: 'PUT',
                 body: patch,
+                headers: { 'If-Match': `"${version}"` },
             }),
             invalidatesTags: (result, error, { id }) => [
                 { type: 'Leagues', id },